@@ -0,0 +1,74 @@
+package syncbus
+
+import "context"
+
+// contextWaiter is implemented by the buses whose waits can be canceled, e.g. *SyncBus.
+type contextWaiter interface {
+	WaitContext(ctx context.Context, keys ...string) error
+}
+
+func waitBus(ctx context.Context, b Bus, key string) error {
+	if cw, ok := b.(contextWaiter); ok {
+		return cw.WaitContext(ctx, key)
+	}
+
+	return b.Wait(key)
+}
+
+// waitAny returns nil as soon as the key was set on any of the buses, canceling the waits on the other ones. If
+// it could not be satisfied on any of the buses, it returns the first error.
+func waitAny(ctx context.Context, buses []Bus, key string) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	c := make(chan error, len(buses))
+	for _, b := range buses {
+		go func(b Bus) { c <- waitBus(ctx, b, key) }(b)
+	}
+
+	var err error
+	for range buses {
+		e := <-c
+		if e == nil {
+			return nil
+		}
+
+		if err == nil {
+			err = e
+		}
+	}
+
+	return err
+}
+
+// WaitAcross blocks until each of the signals represented by the keys is set on any of the provided buses. This
+// way conditions combining events from different buses, e.g. a suite level and a test level one, can be
+// expressed in a single wait. Every key is waited for on every bus with the timeout of the given bus, and it
+// fails with the error of the first key that could not be satisfied on any of them.
+//
+// Once a key was set on one of the buses, the waits for it on the other buses are canceled, and when WaitAcross
+// fails, all its pending waits are canceled. This applies to the buses implementing WaitContext, like
+// *SyncBus. On the other buses, the waits keep running in the background until they time out.
+//
+// If no bus or no key argument is passed to it, it is a noop.
+func WaitAcross(buses []Bus, keys ...string) error {
+	if len(buses) == 0 || len(keys) == 0 {
+		return nil
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	c := make(chan error, len(keys))
+	for _, key := range keys {
+		go func(key string) { c <- waitAny(ctx, buses, key) }(key)
+	}
+
+	for range keys {
+		if err := <-c; err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,69 @@
+package syncbus
+
+import (
+	"testing"
+	"time"
+)
+
+func TestWaitAcrossEmpty(t *testing.T) {
+	if err := WaitAcross(nil, "foo"); err != nil {
+		t.Error(err)
+	}
+
+	bus := New(12 * time.Millisecond)
+	defer bus.Close()
+	if err := WaitAcross([]Bus{bus}); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestWaitAcross(t *testing.T) {
+	suite := New(120 * time.Millisecond)
+	defer suite.Close()
+
+	test := New(120 * time.Millisecond)
+	defer test.Close()
+
+	done := make(chan error)
+	go func() { done <- WaitAcross([]Bus{suite, test}, "db", "server") }()
+
+	suite.Signal("db")
+	test.Signal("server")
+	if err := <-done; err != nil {
+		t.Error(err)
+	}
+}
+
+func TestWaitAcrossTimeout(t *testing.T) {
+	suite := New(12 * time.Millisecond)
+	defer suite.Close()
+
+	test := New(12 * time.Millisecond)
+	defer test.Close()
+
+	suite.Signal("db")
+	if err := WaitAcross([]Bus{suite, test}, "db", "server"); err != ErrTimeout {
+		t.Error("failed to timeout")
+	}
+}
+
+func TestWaitAcrossCancelsOtherWaits(t *testing.T) {
+	suite := New(120 * time.Millisecond)
+	defer suite.Close()
+
+	test := New(120 * time.Millisecond)
+	defer test.Close()
+
+	suite.Signal("db")
+	if err := WaitAcross([]Bus{suite, test}, "db"); err != nil {
+		t.Fatal(err)
+	}
+
+	for test.Stats().Waiting != 0 {
+		time.Sleep(time.Millisecond / 10)
+	}
+
+	if s := test.Stats(); s.Timeouts != 0 {
+		t.Error("failed to cancel the wait", s)
+	}
+}
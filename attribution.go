@@ -0,0 +1,111 @@
+package syncbus
+
+import (
+	"fmt"
+	"time"
+)
+
+// KeySource tells why a key of a timed out wait was missing: it was either never signaled, or it was reset
+// after being set. It is recorded with WithSourceAttribution.
+type KeySource struct {
+
+	// Key is the missing key.
+	Key string
+
+	// Signaled tells whether the signal of the key was ever set.
+	Signaled bool
+
+	// SetAt is the location of the code that set the signal last time, outside of the package.
+	SetAt string
+
+	// SetBy is the ID of the goroutine that set the signal last time.
+	SetBy int64
+
+	// SetTime tells when the signal was set last time.
+	SetTime time.Time
+
+	// ResetAt is the location of the code that reset the signal last time, outside of the package.
+	ResetAt string
+
+	// ResetBy is the ID of the goroutine that reset the signal last time.
+	ResetBy int64
+
+	// ResetTime tells when the signal was reset last time.
+	ResetTime time.Time
+}
+
+// WithSourceAttribution makes the bus record which goroutine and call site set and reset each key last time,
+// and makes the timed out Wait calls return a TimeoutError, that tells for each missing key whether it was
+// never signaled, or it was reset after being set, and where. It pinpoints the misplaced resets. Recording the
+// call sites has a cost on every Signal and Reset call.
+func WithSourceAttribution() Option {
+	return func(o *options) { o.attribution = true }
+}
+
+func (b *SyncBus) attributeSet(now time.Time, key string, c callInfo) {
+	if !b.options.attribution {
+		return
+	}
+
+	s := b.source(key)
+	s.Signaled = true
+	s.SetAt = c.site
+	s.SetBy = c.goid
+	s.SetTime = now
+}
+
+func (b *SyncBus) attributeReset(now time.Time, keys []string, c callInfo) {
+	if !b.options.attribution {
+		return
+	}
+
+	for _, key := range keys {
+		s := b.source(key)
+		s.ResetAt = c.site
+		s.ResetBy = c.goid
+		s.ResetTime = now
+	}
+}
+
+func (b *SyncBus) source(key string) *KeySource {
+	s, ok := b.sources[key]
+	if !ok {
+		s = &KeySource{Key: key}
+		b.sources[key] = s
+	}
+
+	return s
+}
+
+func (b *SyncBus) missingSources(missing []string) []KeySource {
+	if !b.options.attribution {
+		return nil
+	}
+
+	var s []KeySource
+	for _, key := range missing {
+		if si, ok := b.sources[key]; ok {
+			s = append(s, *si)
+			continue
+		}
+
+		s = append(s, KeySource{Key: key})
+	}
+
+	return s
+}
+
+func (s KeySource) String() string {
+	if !s.Signaled {
+		return fmt.Sprintf("%s: never signaled", s.Key)
+	}
+
+	return fmt.Sprintf(
+		"%s: was reset at %s (goroutine %d) after being set at %s (goroutine %d)",
+		s.Key,
+		s.ResetAt,
+		s.ResetBy,
+		s.SetAt,
+		s.SetBy,
+	)
+}
@@ -0,0 +1,55 @@
+package syncbus
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSourceAttribution(t *testing.T) {
+	bus := New(3*time.Millisecond, WithSourceAttribution())
+	defer bus.Close()
+
+	bus.Signal("foo")
+	bus.ResetSignals("foo")
+	err := bus.Wait("foo", "bar")
+	var terr *TimeoutError
+	if !errors.As(err, &terr) {
+		t.Fatal("failed to timeout with attribution", err)
+	}
+
+	if len(terr.Sources) != 2 {
+		t.Fatal("invalid sources", terr.Sources)
+	}
+
+	foo, bar := terr.Sources[0], terr.Sources[1]
+	if !foo.Signaled || !strings.Contains(foo.SetAt, "attribution_test.go") ||
+		!strings.Contains(foo.ResetAt, "attribution_test.go") || foo.SetBy == 0 {
+		t.Error("invalid source", foo)
+	}
+
+	if bar.Signaled || bar.String() != "bar: never signaled" {
+		t.Error("invalid source", bar)
+	}
+
+	if !strings.Contains(err.Error(), "foo: was reset at ") {
+		t.Error("invalid error message", err)
+	}
+}
+
+func TestSourceAttributionResetAll(t *testing.T) {
+	bus := New(3*time.Millisecond, WithSourceAttribution())
+	defer bus.Close()
+
+	bus.Signal("foo")
+	bus.Reset()
+	var terr *TimeoutError
+	if err := bus.Wait("foo"); !errors.As(err, &terr) {
+		t.Fatal("failed to timeout with attribution", err)
+	}
+
+	if len(terr.Sources) != 1 || terr.Sources[0].ResetAt == "" {
+		t.Error("invalid sources", terr.Sources)
+	}
+}
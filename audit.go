@@ -0,0 +1,151 @@
+package syncbus
+
+import (
+	"fmt"
+	"sync"
+	"time"
+)
+
+// AuditOp identifies an operation recorded in the audit log enabled with WithAuditLog.
+type AuditOp int
+
+const (
+	// AuditSignal is the setting of a signal by Signal.
+	AuditSignal AuditOp = iota
+
+	// AuditResetSignals is the clearing of a signal by ResetSignals.
+	AuditResetSignals
+
+	// AuditReset is the clearing of a signal by Reset, of the bus or of a namespace.
+	AuditReset
+)
+
+// AuditEntry is a record of the audit log enabled with WithAuditLog.
+type AuditEntry struct {
+
+	// Key is the key of the signal.
+	Key string
+
+	// Op is the operation that set or cleared the signal.
+	Op AuditOp
+
+	// Actor is the name of the namespace that the operation was called through. It is empty, when the operation
+	// was called directly on the bus.
+	Actor string
+
+	// Site is the location of the call, outside of the package.
+	Site string
+
+	// Goroutine is the ID of the calling goroutine.
+	Goroutine int64
+
+	// Time tells when the operation was processed by the bus.
+	Time time.Time
+}
+
+// auditLog is written by the run loop, and it is protected by a mutex, so that it can be read even after the
+// bus was closed.
+type auditLog struct {
+	mx      sync.Mutex
+	entries []AuditEntry
+}
+
+// WithAuditLog makes the bus keep an append-only log of who set and cleared each signal, recording the
+// operation, the namespace that it was called through, the call site, the goroutine and the time. The log can
+// be queried with Audit, also after the bus was closed, and it answers questions like which code cleared a flag
+// that a test was expecting to be set. The log is not bounded, and recording the call sites has a cost on
+// every Signal and Reset call.
+func WithAuditLog() Option {
+	return func(o *options) { o.auditLog = true }
+}
+
+func newAuditLog(o options) *auditLog {
+	if !o.auditLog {
+		return nil
+	}
+
+	return &auditLog{}
+}
+
+func (l *auditLog) record(now time.Time, op AuditOp, keys []string, namespace string, c callInfo) {
+	if l == nil {
+		return
+	}
+
+	l.mx.Lock()
+	defer l.mx.Unlock()
+	for _, key := range keys {
+		l.entries = append(l.entries, AuditEntry{
+			Key:       key,
+			Op:        op,
+			Actor:     namespace,
+			Site:      c.site,
+			Goroutine: c.goid,
+			Time:      now,
+		})
+	}
+}
+
+func (l *auditLog) query(keys []string) []AuditEntry {
+	if l == nil {
+		return nil
+	}
+
+	l.mx.Lock()
+	defer l.mx.Unlock()
+	if len(keys) == 0 {
+		return append([]AuditEntry(nil), l.entries...)
+	}
+
+	var e []AuditEntry
+	for _, ei := range l.entries {
+		if containsKey(keys, ei.Key) {
+			e = append(e, ei)
+		}
+	}
+
+	return e
+}
+
+func (op AuditOp) String() string {
+	switch op {
+	case AuditSignal:
+		return "Signal"
+	case AuditResetSignals:
+		return "ResetSignals"
+	case AuditReset:
+		return "Reset"
+	default:
+		return "unknown"
+	}
+}
+
+func (e AuditEntry) String() string {
+	actor := "the bus"
+	if e.Actor != "" {
+		actor = fmt.Sprintf("namespace %q", e.Actor)
+	}
+
+	return fmt.Sprintf(
+		"%s %s %s by %s at %s (goroutine %d)",
+		e.Time.Format("15:04:05.000000"),
+		e.Op,
+		e.Key,
+		actor,
+		e.Site,
+		e.Goroutine,
+	)
+}
+
+// Audit returns the entries of the audit log enabled with WithAuditLog, that belong to the provided keys, in
+// the order of the operations. Without keys, it returns the entries of all the keys. It can be called also
+// after the bus was closed.
+//
+// If the receiver *SyncBus is nil, or the audit log is not enabled, it returns nil.
+func (b *SyncBus) Audit(keys ...string) []AuditEntry {
+	if b == nil {
+		return nil
+	}
+
+	return b.audit.query(keys)
+}
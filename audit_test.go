@@ -0,0 +1,68 @@
+package syncbus
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestNilAudit(t *testing.T) {
+	var bus *SyncBus
+	if bus.Audit("foo") != nil {
+		t.Error("unexpected audit log")
+	}
+}
+
+func TestAuditDisabled(t *testing.T) {
+	bus := New(3 * time.Millisecond)
+	defer bus.Close()
+
+	bus.Signal("foo")
+	if err := bus.Wait("foo"); err != nil {
+		t.Fatal(err)
+	}
+
+	if bus.Audit() != nil {
+		t.Error("unexpected audit log")
+	}
+}
+
+func TestAudit(t *testing.T) {
+	bus := New(3*time.Millisecond, WithAuditLog())
+
+	bus.Namespace("db").Signal("ready", "foo")
+	bus.ResetSignals("ready")
+	bus.Signal("ready")
+	bus.Reset()
+	if err := bus.Wait("ready"); err != ErrTimeout {
+		t.Fatal("failed to reset", err)
+	}
+
+	bus.Close()
+	e := bus.Audit("ready")
+	if len(e) != 4 {
+		t.Fatal("invalid number of entries", len(e))
+	}
+
+	for i, op := range []AuditOp{AuditSignal, AuditResetSignals, AuditSignal, AuditReset} {
+		if e[i].Op != op || e[i].Key != "ready" || !strings.Contains(e[i].Site, "audit_test.go") {
+			t.Error("invalid entry", e[i])
+		}
+
+		if i > 0 && e[i].Time.Before(e[i-1].Time) {
+			t.Error("invalid order", e[i-1], e[i])
+		}
+	}
+
+	if e[0].Actor != "db" || e[1].Actor != "" || e[0].Goroutine == 0 {
+		t.Error("invalid actor", e[0], e[1])
+	}
+
+	if !strings.Contains(e[0].String(), `Signal ready by namespace "db" at `) {
+		t.Error("invalid format", e[0])
+	}
+
+	if n := len(bus.Audit()); n != 6 {
+		t.Error("invalid number of entries", n)
+	}
+}
@@ -0,0 +1,99 @@
+package syncbus
+
+import "sync"
+
+// WaitSpec describes a wait registered by WaitAll.
+type WaitSpec struct {
+
+	// Keys are the keys of the signals to wait for.
+	Keys []string
+
+	// Opts customize the wait, like with WaitWith.
+	Opts []WaitOpt
+}
+
+// WaitAll registers multiple independent waits in a single message to the run loop, and blocks until all of
+// them return. It returns the results of the waits, in the order of the specs. The waits with no keys succeed
+// immediately. It reduces the channel round trips of the tests that set up dozens of expectations at once.
+//
+// If the receiver *SyncBus is nil, it returns nil errors for every spec.
+func (b *SyncBus) WaitAll(waits []WaitSpec) []error {
+	errs := make([]error, len(waits))
+	if b == nil {
+		return errs
+	}
+
+	var (
+		batch []waitItem
+		index []int
+		keys  []string
+	)
+
+	call := b.callInfo()
+	for i, spec := range waits {
+		if len(spec.Keys) == 0 || !b.sample() {
+			continue
+		}
+
+		w := waitItem{keys: spec.Keys}
+		for _, opt := range spec.Opts {
+			opt(&w)
+		}
+
+		w.signal = make(chan error, 1)
+		w.ack = make(chan struct{})
+		w.call = call
+		batch = append(batch, w)
+		index = append(index, i)
+		keys = append(keys, w.keys...)
+	}
+
+	if len(batch) == 0 {
+		return errs
+	}
+
+	b.jitter()
+	if b.onLoop("WaitAll", keys, call) {
+		for _, i := range index {
+			errs[i] = ErrLoopCall
+		}
+
+		return errs
+	}
+
+	if b.closed() {
+		err := b.useAfterClose("WaitAll", keys)
+		for _, i := range index {
+			errs[i] = err
+		}
+
+		return errs
+	}
+
+	select {
+	case b.waitBatch <- batch:
+	case <-b.done:
+		err := b.useAfterClose("WaitAll", keys)
+		for _, i := range index {
+			errs[i] = err
+		}
+
+		return errs
+	}
+
+	// every wait is received in its own goroutine, so that the ack of each is closed as soon as its own result
+	// arrives, without SignalSync waiting for the unrelated waits before it:
+	var wg sync.WaitGroup
+	wg.Add(len(batch))
+	for j, w := range batch {
+		go func(i int, w waitItem) {
+			defer wg.Done()
+			errs[i] = b.receive(w)
+			close(w.ack)
+		}(index[j], w)
+	}
+
+	wg.Wait()
+	b.jitter()
+	return errs
+}
@@ -0,0 +1,74 @@
+package syncbus
+
+import (
+	"testing"
+	"time"
+)
+
+func TestNilWaitAll(t *testing.T) {
+	var bus *SyncBus
+	errs := bus.WaitAll([]WaitSpec{{Keys: []string{"foo"}}, {Keys: []string{"bar"}}})
+	if len(errs) != 2 || errs[0] != nil || errs[1] != nil {
+		t.Error("unexpected result", errs)
+	}
+}
+
+func TestWaitAll(t *testing.T) {
+	bus := New(120 * time.Millisecond)
+	defer bus.Close()
+
+	bus.Signal("foo")
+	go func() {
+		for bus.Stats().Waiting != 2 {
+			time.Sleep(time.Millisecond / 10)
+		}
+
+		bus.Signal("bar", "baz")
+	}()
+
+	errs := bus.WaitAll([]WaitSpec{
+		{Keys: []string{"foo"}},
+		{Keys: []string{"bar"}, Opts: []WaitOpt{WithName("bar")}},
+		{},
+		{Keys: []string{"baz", "qux"}, Opts: []WaitOpt{WithTimeoutOpt(12 * time.Millisecond)}},
+	})
+
+	if len(errs) != 4 || errs[0] != nil || errs[1] != nil || errs[2] != nil || errs[3] != ErrTimeout {
+		t.Error("unexpected result", errs)
+	}
+
+	if s := bus.Stats(); s.Waits != 3 || s.Releases != 2 || s.Timeouts != 1 {
+		t.Error("invalid stats", s)
+	}
+}
+
+func TestWaitAllClosed(t *testing.T) {
+	bus := New(120 * time.Millisecond)
+	bus.Close()
+	errs := bus.WaitAll([]WaitSpec{{Keys: []string{"foo"}}})
+	if len(errs) != 1 || errs[0] != ErrClosed {
+		t.Error("unexpected result", errs)
+	}
+}
+
+func TestWaitAllSignalSync(t *testing.T) {
+	bus := New(120 * time.Millisecond)
+	defer bus.Close()
+
+	done := make(chan []error)
+	go func() { done <- bus.WaitAll([]WaitSpec{{Keys: []string{"foo"}}, {Keys: []string{"bar"}}}) }()
+	for bus.Stats().Waiting != 2 {
+		time.Sleep(time.Millisecond / 10)
+	}
+
+	start := time.Now()
+	bus.SignalSync("bar")
+	if d := time.Since(start); d >= 60*time.Millisecond {
+		t.Error("SignalSync blocked by an unrelated wait", d)
+	}
+
+	bus.Signal("foo")
+	if errs := <-done; errs[0] != nil || errs[1] != nil {
+		t.Error(errs)
+	}
+}
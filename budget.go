@@ -0,0 +1,46 @@
+package syncbus
+
+import (
+	"errors"
+	"time"
+)
+
+// ErrBudget is returned by Wait when the timeout budget of the bus set by WithTimeoutBudget is exhausted.
+var ErrBudget = errors.New("timeout budget exhausted")
+
+// WithTimeoutBudget sets a cumulative budget for the time spent blocked in the Wait calls of the bus. The time
+// of the concurrent waits is counted separately for each of them. When the budget is exhausted, the Wait calls
+// that would block fail immediately with ErrBudget, and the pending waits time out, with ErrBudget, when they
+// would exceed the remaining budget. This way, a test with many sequential waits fails quickly, instead of
+// multiplying the individual timeouts. Zero means no budget, which is the default.
+func WithTimeoutBudget(d time.Duration) Option {
+	return func(o *options) { o.timeoutBudget = d }
+}
+
+func (b *SyncBus) remainingBudget() time.Duration {
+	return b.options.timeoutBudget - b.blocked.total
+}
+
+// applyBudget limits the deadline of a new wait to the remaining budget. It returns ErrBudget when the budget
+// is exhausted.
+func (b *SyncBus) applyBudget(now time.Time, w *waitItem) error {
+	if b.options.timeoutBudget <= 0 {
+		return nil
+	}
+
+	remaining := b.remainingBudget()
+	if remaining <= 0 {
+		if done, _ := b.checkWaiting(*w); done {
+			return nil
+		}
+
+		return ErrBudget
+	}
+
+	if d := now.Add(remaining); d.Before(w.deadline) {
+		w.deadline = d
+		w.budgeted = true
+	}
+
+	return nil
+}
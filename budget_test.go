@@ -0,0 +1,64 @@
+package syncbus
+
+import (
+	"testing"
+	"time"
+)
+
+func TestTimeoutBudget(t *testing.T) {
+	bus := New(24*time.Millisecond, WithTimeoutBudget(36*time.Millisecond))
+	defer bus.Close()
+
+	if err := bus.Wait("foo"); err != ErrTimeout {
+		t.Fatal("failed to timeout", err)
+	}
+
+	start := time.Now()
+	if err := bus.Wait("bar"); err != ErrBudget {
+		t.Fatal("failed to limit the wait to the remaining budget", err)
+	}
+
+	if time.Since(start) >= 24*time.Millisecond {
+		t.Error("failed to fail early")
+	}
+
+	start = time.Now()
+	if err := bus.Wait("baz"); err != ErrBudget {
+		t.Fatal("failed to fail on exhausted budget", err)
+	}
+
+	if time.Since(start) > 6*time.Millisecond {
+		t.Error("failed to fail immediately")
+	}
+
+	bus.Signal("baz")
+	if err := bus.Wait("baz"); err != nil {
+		t.Error("failed to succeed without blocking", err)
+	}
+}
+
+func TestTimeoutBudgetReleased(t *testing.T) {
+	bus := New(120*time.Millisecond, WithTimeoutBudget(120*time.Millisecond))
+	defer bus.Close()
+
+	for i := 0; i < 3; i++ {
+		errs := make(chan error, 1)
+		go func() { errs <- bus.Wait("foo") }()
+		time.Sleep(12 * time.Millisecond)
+		bus.Signal("foo")
+		if err := <-errs; err != nil {
+			t.Fatal(err)
+		}
+
+		bus.Reset()
+	}
+
+	start := time.Now()
+	if err := bus.Wait("foo"); err != ErrBudget {
+		t.Fatal("failed to apply the budget", err)
+	}
+
+	if time.Since(start) >= 108*time.Millisecond {
+		t.Error("failed to account the blocked time")
+	}
+}
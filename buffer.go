@@ -0,0 +1,29 @@
+package syncbus
+
+import "sync/atomic"
+
+// WithSignalBuffer sets the size of the buffer of the internal channels carrying the Signal, ResetSignals and
+// Reset calls to the run loop, so that bursty producers don't block while the run loop is busy. The trade-off is
+// that these calls return before the run loop received them, which has the following consequences:
+//
+// - the signals and the resets are carried by different channels, so a Signal followed by a ResetSignals of the
+// same key may be processed in the reverse order, leaving the signal set;
+//
+// - a query, like IsSet or Stats, called right after a Signal may not reflect the signal yet;
+//
+// - the operations still in the buffer when the bus is closed are dropped.
+//
+// The Wait calls are not affected, and the ordering of the signals among themselves is preserved. The number of
+// the times that the producers had to wait for the run loop is reported by Stats as ProducerWaits.
+func WithSignalBuffer(n int) Option {
+	return func(o *options) {
+		if n > 0 {
+			o.signalBuffer = n
+		}
+	}
+}
+
+// countStall counts a producer call that had to wait for the run loop.
+func (b *SyncBus) countStall() {
+	atomic.AddUint64(&b.stalled, 1)
+}
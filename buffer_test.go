@@ -0,0 +1,78 @@
+package syncbus
+
+import (
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// blockLoop blocks the run loop of the bus in the evaluation of a predicate, until the returned function is
+// called.
+func blockLoop(t *testing.T, bus *SyncBus) func() {
+	block, blocked := make(chan struct{}), make(chan struct{})
+	errs := make(chan error, 1)
+	go func() {
+		errs <- bus.WaitFor(func(s State) bool {
+			if !s.IsSet("block") {
+				return false
+			}
+
+			close(blocked)
+			<-block
+			return true
+		})
+	}()
+
+	for bus.Stats().Waiting != 1 {
+		time.Sleep(time.Millisecond / 10)
+	}
+
+	bus.Signal("block")
+	<-blocked
+	return func() {
+		close(block)
+		if err := <-errs; err != nil {
+			t.Error(err)
+		}
+	}
+}
+
+func TestSignalBuffer(t *testing.T) {
+	bus := New(120*time.Millisecond, WithSignalBuffer(3))
+	defer bus.Close()
+
+	release := blockLoop(t, bus)
+	stalled := atomic.LoadUint64(&bus.stalled)
+	bus.Signal("foo")
+	bus.Signal("bar")
+	bus.ResetSignals("baz")
+	if atomic.LoadUint64(&bus.stalled) != stalled {
+		t.Error("unexpected producer wait")
+	}
+
+	release()
+	if err := bus.Wait("foo", "bar"); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestProducerWaits(t *testing.T) {
+	bus := New(120 * time.Millisecond)
+	defer bus.Close()
+
+	release := blockLoop(t, bus)
+	stalled := atomic.LoadUint64(&bus.stalled)
+	go bus.Signal("foo")
+	for atomic.LoadUint64(&bus.stalled) == stalled {
+		time.Sleep(time.Millisecond / 10)
+	}
+
+	release()
+	if err := bus.Wait("foo"); err != nil {
+		t.Error(err)
+	}
+
+	if s := bus.Stats(); s.ProducerWaits <= stalled {
+		t.Error("failed to count the producer waits", s)
+	}
+}
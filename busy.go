@@ -0,0 +1,58 @@
+package syncbus
+
+import "errors"
+
+// ErrBusy is returned by Wait when the number of the pending waits reached the limit set by WithMaxWaiting or
+// WithMaxWaitingPerKey.
+var ErrBusy = errors.New("too many pending waits")
+
+// WithMaxWaiting limits the number of the concurrently pending waits on the bus. Beyond the limit, the Wait
+// calls that would block fail immediately with ErrBusy. It protects the tests from growing memory silently, when
+// the tested code spawns an unbounded number of waiting goroutines. Zero means no limit, which is the default.
+func WithMaxWaiting(n int) Option {
+	return func(o *options) { o.maxWaiting = n }
+}
+
+// WithMaxWaitingPerKey limits the number of the concurrently pending waits depending on the same key. Beyond the
+// limit, the Wait calls that would block fail immediately with ErrBusy. Zero means no limit, which is the
+// default.
+func WithMaxWaitingPerKey(n int) Option {
+	return func(o *options) { o.maxWaitingPerKey = n }
+}
+
+// checkBusy tells whether a new wait exceeds the limits of the pending waits.
+func (b *SyncBus) checkBusy(w waitItem) error {
+	if b.options.maxWaiting <= 0 && b.options.maxWaitingPerKey <= 0 {
+		return nil
+	}
+
+	if done, _ := b.checkWaiting(w); done {
+		return nil
+	}
+
+	if b.options.maxWaiting > 0 && len(b.waiting) >= b.options.maxWaiting {
+		return ErrBusy
+	}
+
+	if b.options.maxWaitingPerKey <= 0 {
+		return nil
+	}
+
+	for _, key := range w.keys {
+		var n int
+		for _, wi := range b.waiting {
+			for _, k := range wi.keys {
+				if k == key {
+					n++
+					break
+				}
+			}
+		}
+
+		if n >= b.options.maxWaitingPerKey {
+			return ErrBusy
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,71 @@
+package syncbus
+
+import (
+	"testing"
+	"time"
+)
+
+func TestMaxWaiting(t *testing.T) {
+	bus := New(120*time.Millisecond, WithMaxWaiting(2))
+	defer bus.Close()
+
+	tw := newTestWait(2)
+	for _, key := range []string{"foo", "bar"} {
+		go func(key string) {
+			if err := bus.Wait(key); err != nil {
+				t.Error(err)
+			}
+
+			tw.done()
+		}(key)
+	}
+
+	for bus.Stats().Waiting != 2 {
+		time.Sleep(time.Millisecond / 10)
+	}
+
+	if err := bus.Wait("baz"); err != ErrBusy {
+		t.Error("failed to fail with busy", err)
+	}
+
+	bus.Signal("qux")
+	if err := bus.Wait("qux"); err != nil {
+		t.Error(err)
+	}
+
+	bus.Signal("foo", "bar")
+	if err := tw.wait(); err != nil {
+		t.Error(err)
+	}
+
+	if s := bus.Stats(); s.Waits != 3 {
+		t.Error("invalid stats", s)
+	}
+}
+
+func TestMaxWaitingPerKey(t *testing.T) {
+	bus := New(120*time.Millisecond, WithMaxWaitingPerKey(1))
+	defer bus.Close()
+
+	tw := newTestWait(1)
+	go func() {
+		if err := bus.Wait("foo", "bar"); err != nil {
+			t.Error(err)
+		}
+
+		tw.done()
+	}()
+
+	for bus.Stats().Waiting != 1 {
+		time.Sleep(time.Millisecond / 10)
+	}
+
+	if err := bus.Wait("baz", "bar"); err != ErrBusy {
+		t.Error("failed to fail with busy", err)
+	}
+
+	bus.Signal("foo", "bar")
+	if err := tw.wait(); err != nil {
+		t.Error(err)
+	}
+}
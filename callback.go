@@ -0,0 +1,86 @@
+package syncbus
+
+import (
+	"runtime/debug"
+	"sync/atomic"
+)
+
+type callbackItem struct {
+	key string
+	f   func(key string)
+}
+
+// OnSignal registers f to be called whenever the signal represented by key is set. The callbacks are executed
+// outside of the run loop, on a single goroutine dedicated to them, one by one, in the order of the signals that
+// triggered them. This means that a callback can call the methods of the bus, including Wait, without
+// deadlocking it. Waiting in a callback, however, delays the execution of the subsequent callbacks. The pending
+// callbacks are dropped when the bus is closed.
+//
+// When a callback panics, the bus stops the same way as when its run loop panics: the pending waits are
+// released with a *PanicError, see Panic.
+//
+// If the receiver *SyncBus is nil, it is a noop.
+func (b *SyncBus) OnSignal(key string, f func(key string)) {
+	if b == nil {
+		return
+	}
+
+	if b.closed() {
+		return
+	}
+
+	select {
+	case b.onSignal <- callbackItem{key: key, f: f}:
+	case <-b.done:
+	}
+}
+
+// queueCallbacks schedules the callbacks registered for the signaled keys.
+func (b *SyncBus) queueCallbacks(keys []string) {
+	for _, key := range keys {
+		for _, f := range b.callbacks[key] {
+			f, key := f, key
+			b.queue = append(b.queue, func() { f(key) })
+		}
+	}
+}
+
+// nextCallback returns the dispatch channel and the next callback, when there is a pending one. Otherwise, it
+// returns a nil channel, disabling the dispatch case of the run loop.
+func (b *SyncBus) nextCallback() (chan<- func(), func()) {
+	if len(b.queue) == 0 {
+		return nil, nil
+	}
+
+	return b.dispatch, b.queue[0]
+}
+
+// runCallback executes a callback, and reports its panic to the run loop, if any.
+func (b *SyncBus) runCallback(f func()) {
+	defer func() {
+		r := recover()
+		if r == nil {
+			return
+		}
+
+		select {
+		case b.failed <- &PanicError{Value: r, Stack: debug.Stack()}:
+		case <-b.done:
+		}
+	}()
+
+	f()
+}
+
+func (b *SyncBus) dispatchCallbacks() {
+	if b.options.vet {
+		atomic.StoreInt64(&b.callbackID, goid())
+	}
+
+	for f := range b.dispatch {
+		b.runCallback(f)
+	}
+
+	<-b.done
+	close(b.exited)
+}
@@ -0,0 +1,84 @@
+package syncbus
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestNilOnSignal(t *testing.T) {
+	var bus *SyncBus
+	bus.OnSignal("foo", func(string) { t.Error("unexpected callback") })
+}
+
+func TestOnSignalReentrant(t *testing.T) {
+	bus := New(120 * time.Millisecond)
+	defer bus.Close()
+
+	errs := make(chan error, 1)
+	bus.OnSignal("foo", func(key string) {
+		if key != "foo" {
+			t.Error("invalid key", key)
+		}
+
+		errs <- bus.Wait("bar")
+	})
+
+	bus.OnSignal("foo", func(string) { bus.Signal("bar") })
+	bus.Signal("foo")
+	bus.Signal("bar")
+	if err := <-errs; err != nil {
+		t.Error(err)
+	}
+}
+
+func TestOnSignalOrder(t *testing.T) {
+	bus := New(120 * time.Millisecond)
+	defer bus.Close()
+
+	keys := make(chan string, 4)
+	record := func(key string) { keys <- key }
+	bus.OnSignal("foo", record)
+	bus.OnSignal("bar", record)
+	bus.OnSignal("baz", record)
+	bus.Signal("foo")
+	bus.Signal("bar", "baz")
+	bus.Signal("foo")
+	for _, expected := range []string{"foo", "bar", "baz", "foo"} {
+		if key := <-keys; key != expected {
+			t.Error("invalid order", key, expected)
+		}
+	}
+}
+
+func TestOnSignalAfterClose(t *testing.T) {
+	bus := New(120 * time.Millisecond)
+	bus.Close()
+	bus.OnSignal("foo", func(string) { t.Error("unexpected callback") })
+	bus.Signal("foo")
+}
+
+func TestOnSignalPanic(t *testing.T) {
+	bus := New(120 * time.Millisecond)
+	defer bus.Close()
+
+	errs := make(chan error, 1)
+	go func() { errs <- bus.Wait("bar") }()
+	for bus.Stats().Waiting != 1 {
+		time.Sleep(time.Millisecond / 10)
+	}
+
+	bus.OnSignal("foo", func(string) { panic("foo") })
+	bus.Signal("foo")
+	err := <-errs
+	var perr *PanicError
+	if !errors.As(err, &perr) || perr.Value != "foo" || len(perr.Stack) == 0 {
+		t.Error("failed to surface the panic of the callback", err)
+	}
+
+	if bus.Panic() != perr {
+		t.Error("failed to record the panic", bus.Panic())
+	}
+
+	<-bus.Closed()
+}
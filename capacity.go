@@ -0,0 +1,39 @@
+package syncbus
+
+// WithExpectedKeys presizes the internal storage of the signals for n distinct keys, avoiding its repeated
+// growth in large scale simulations.
+func WithExpectedKeys(n int) Option {
+	return func(o *options) {
+		if n > 0 {
+			o.expectedKeys = n
+		}
+	}
+}
+
+// WithExpectedWaiters presizes the internal storage of the pending waits for n concurrent waits, avoiding its
+// repeated growth in large scale simulations.
+func WithExpectedWaiters(n int) Option {
+	return func(o *options) {
+		if n > 0 {
+			o.expectedWaiters = n
+		}
+	}
+}
+
+// keepWaiting returns an empty slice for collecting the waits that stay pending, reusing the storage of a
+// previous generation of the pending waits.
+func (b *SyncBus) keepWaiting() []waitItem {
+	return b.spare[:0]
+}
+
+// swapWaiting replaces the pending waits with the ones collected by keepWaiting, and keeps the storage of the
+// previous ones for reuse.
+func (b *SyncBus) swapWaiting(keep []waitItem) {
+	old := b.waiting
+	for i := range old {
+		old[i] = waitItem{}
+	}
+
+	b.spare = old[:0]
+	b.waiting = keep
+}
@@ -0,0 +1,45 @@
+package syncbus
+
+import (
+	"fmt"
+	"testing"
+	"time"
+)
+
+func TestExpectedCapacity(t *testing.T) {
+	bus := New(120*time.Millisecond, WithExpectedKeys(1<<10), WithExpectedWaiters(1<<6))
+	defer bus.Close()
+
+	if cap(bus.signals.keys) != 1<<10 || cap(bus.signals.bits) != 1<<4 || cap(bus.waiting) != 1<<6 {
+		t.Error("failed to presize the storage")
+	}
+
+	errs := make(chan error, 1<<6)
+	for i := 0; i < cap(errs); i++ {
+		go func(i int) { errs <- bus.Wait(fmt.Sprint(i)) }(i)
+	}
+
+	for bus.Stats().Waiting != cap(errs) {
+		time.Sleep(time.Millisecond / 10)
+	}
+
+	for i := 0; i < cap(errs); i++ {
+		bus.Signal(fmt.Sprint(i))
+		if err := <-errs; err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if s := bus.Stats(); s.Waiting != 0 || s.Releases != 1<<6 {
+		t.Error("invalid stats", s)
+	}
+}
+
+func TestInvalidExpectedCapacity(t *testing.T) {
+	bus := New(120*time.Millisecond, WithExpectedKeys(-1), WithExpectedWaiters(-1))
+	defer bus.Close()
+	bus.Signal("foo")
+	if err := bus.Wait("foo"); err != nil {
+		t.Error(err)
+	}
+}
@@ -0,0 +1,43 @@
+package syncbus
+
+// ReleaseKey returns the key that releases the checkpoint represented by key.
+func ReleaseKey(key string) string {
+	return key + ".release"
+}
+
+// Checkpoint signals the key, and then blocks until the checkpoint gets released by Release, or the timeout of
+// the bus expires. It allows test code to pause a process at a precise point and then let it continue.
+//
+// If b is nil, it is a noop.
+func Checkpoint(b Bus, key string) error {
+	if b == nil {
+		return nil
+	}
+
+	b.Signal(key)
+	return b.Wait(ReleaseKey(key))
+}
+
+// Release releases the checkpoint represented by key. The checkpoint stays released until it is armed again
+// by Hold.
+//
+// If b is nil, it is a noop.
+func Release(b Bus, key string) {
+	if b == nil {
+		return
+	}
+
+	b.Signal(ReleaseKey(key))
+}
+
+// Hold arms the checkpoint represented by key, clearing both the signal of a previous release and the signal
+// set when a process reached the checkpoint.
+//
+// If b is nil, it is a noop.
+func Hold(b Bus, key string) {
+	if b == nil {
+		return
+	}
+
+	b.ResetSignals(key, ReleaseKey(key))
+}
@@ -0,0 +1,53 @@
+package syncbus
+
+import (
+	"testing"
+	"time"
+)
+
+func TestCheckpointNil(t *testing.T) {
+	if err := Checkpoint(nil, "foo"); err != nil {
+		t.Error(err)
+	}
+
+	Release(nil, "foo")
+	Hold(nil, "foo")
+}
+
+func TestCheckpoint(t *testing.T) {
+	bus := New(120 * time.Millisecond)
+	defer bus.Close()
+
+	done := make(chan error, 1)
+	go func() { done <- Checkpoint(bus, "foo") }()
+
+	if err := bus.Wait("foo"); err != nil {
+		t.Fatal(err)
+	}
+
+	select {
+	case <-done:
+		t.Fatal("checkpoint passed before release")
+	default:
+	}
+
+	Release(bus, "foo")
+	if err := <-done; err != nil {
+		t.Error(err)
+	}
+
+	if err := Checkpoint(bus, "foo"); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestCheckpointHold(t *testing.T) {
+	bus := New(12 * time.Millisecond)
+	defer bus.Close()
+
+	Release(bus, "foo")
+	Hold(bus, "foo")
+	if err := Checkpoint(bus, "foo"); err != ErrTimeout {
+		t.Error("failed to hold")
+	}
+}
@@ -0,0 +1,279 @@
+package syncbus
+
+import (
+	"sync"
+	"time"
+)
+
+// DefaultSettle is the default duration of inactivity, after which a bus with simulated time advances its
+// clock.
+const DefaultSettle = time.Millisecond
+
+// Clock provides the time for the code under test. The clock of a bus is returned by its Clock method. With
+// WithSimulatedTime, it is a virtual clock controlled by the bus, otherwise it uses the real time.
+type Clock interface {
+
+	// Now returns the current time of the clock.
+	Now() time.Time
+
+	// Since returns the time elapsed since t, according to the clock.
+	Since(t time.Time) time.Duration
+
+	// Sleep blocks for the duration d, according to the clock.
+	Sleep(d time.Duration)
+
+	// After returns a channel that receives the time of the clock once the duration d elapsed.
+	After(d time.Duration) <-chan time.Time
+
+	// NewTicker returns a ticker, that delivers the time of the clock on its channel every period d.
+	NewTicker(d time.Duration) *Ticker
+}
+
+// Ticker delivers ticks of a Clock on its channel, like time.Ticker.
+type Ticker struct {
+
+	// C is the channel on which the ticks are delivered.
+	C <-chan time.Time
+
+	stop func()
+}
+
+type realClock struct{}
+
+type simTimer struct {
+	at     time.Time
+	period time.Duration
+	c      chan time.Time
+}
+
+type simClock struct {
+	lock    sync.Mutex
+	now     time.Time
+	timers  []*simTimer
+	changed chan struct{}
+}
+
+type simulation struct {
+	clock  *simClock
+	settle time.Duration
+	timer  *time.Timer
+}
+
+// WithSimulatedTime makes the bus use a virtual clock, both for the timeouts of the waits, and for the code
+// under test, that receives the clock by calling the Clock method of the bus. The virtual clock is advanced by
+// the bus, to the earliest deadline of the pending waits, or of the timers of the clock, whenever neither the
+// bus nor the clock sees any activity for settle real time, assuming that by then all the goroutines of the test
+// are blocked on the bus, or on the clock. This way, the tests with second scale timeouts and tickers complete
+// in milliseconds, deterministically. Zero settle means DefaultSettle. The settle time needs to be longer than
+// the longest computation of the code under test, that doesn't interact with the bus or the clock.
+func WithSimulatedTime(settle time.Duration) Option {
+	return func(o *options) {
+		o.simulated = true
+		o.settle = settle
+	}
+}
+
+// Stop turns off the ticker. It doesn't close the channel.
+func (t *Ticker) Stop() {
+	t.stop()
+}
+
+func (realClock) Now() time.Time                         { return time.Now() }
+func (realClock) Since(t time.Time) time.Duration        { return time.Since(t) }
+func (realClock) Sleep(d time.Duration)                  { time.Sleep(d) }
+func (realClock) After(d time.Duration) <-chan time.Time { return time.After(d) }
+
+func (realClock) NewTicker(d time.Duration) *Ticker {
+	t := time.NewTicker(d)
+	return &Ticker{C: t.C, stop: t.Stop}
+}
+
+func newSimulation(simulated bool, settle time.Duration) *simulation {
+	if !simulated {
+		return nil
+	}
+
+	if settle <= 0 {
+		settle = DefaultSettle
+	}
+
+	return &simulation{
+		clock:  &simClock{now: time.Now(), changed: make(chan struct{}, 1)},
+		settle: settle,
+	}
+}
+
+func (c *simClock) Now() time.Time {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	return c.now
+}
+
+func (c *simClock) Since(t time.Time) time.Duration {
+	return c.Now().Sub(t)
+}
+
+func (c *simClock) Sleep(d time.Duration) {
+	<-c.After(d)
+}
+
+func (c *simClock) addTimer(d, period time.Duration) *simTimer {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	t := &simTimer{at: c.now.Add(d), period: period, c: make(chan time.Time, 1)}
+	if d <= 0 && period <= 0 {
+		t.c <- c.now
+		return t
+	}
+
+	c.timers = append(c.timers, t)
+	select {
+	case c.changed <- struct{}{}:
+	default:
+	}
+
+	return t
+}
+
+func (c *simClock) After(d time.Duration) <-chan time.Time {
+	return c.addTimer(d, 0).c
+}
+
+func (c *simClock) NewTicker(d time.Duration) *Ticker {
+	if d <= 0 {
+		panic("syncbus: non-positive interval for NewTicker")
+	}
+
+	t := c.addTimer(d, d)
+	return &Ticker{C: t.c, stop: func() { c.removeTimer(t) }}
+}
+
+func (c *simClock) removeTimer(t *simTimer) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	for i, ti := range c.timers {
+		if ti == t {
+			c.timers = append(c.timers[:i], c.timers[i+1:]...)
+			return
+		}
+	}
+}
+
+// next returns the time of the earliest timer of the clock.
+func (c *simClock) next() (time.Time, bool) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	var (
+		next time.Time
+		ok   bool
+	)
+
+	for _, t := range c.timers {
+		if !ok || t.at.Before(next) {
+			next, ok = t.at, true
+		}
+	}
+
+	return next, ok
+}
+
+// advance sets the clock to the time to, and fires the timers that are due. Like with time.Ticker, the ticks
+// are dropped when the receiver is not ready.
+func (c *simClock) advance(to time.Time) time.Time {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	if to.After(c.now) {
+		c.now = to
+	}
+
+	keep := c.timers[:0]
+	for _, t := range c.timers {
+		if t.at.After(c.now) {
+			keep = append(keep, t)
+			continue
+		}
+
+		select {
+		case t.c <- c.now:
+		default:
+		}
+
+		if t.period > 0 {
+			for !t.at.After(c.now) {
+				t.at = t.at.Add(t.period)
+			}
+
+			keep = append(keep, t)
+		}
+	}
+
+	c.timers = keep
+	return c.now
+}
+
+// now returns the current time of the bus, which is the virtual time with WithSimulatedTime.
+func (b *SyncBus) now() time.Time {
+	if b.sim == nil {
+		return time.Now()
+	}
+
+	return b.sim.clock.Now()
+}
+
+func (b *SyncBus) clockChanged() <-chan struct{} {
+	if b.sim == nil {
+		return nil
+	}
+
+	return b.sim.clock.changed
+}
+
+// nextEvent returns the earliest deadline of the pending waits, and of the timers of the clock.
+func (b *SyncBus) nextEvent() (time.Time, bool) {
+	next, ok := b.sim.clock.next()
+	for _, w := range b.waiting {
+		if !ok || w.deadline.Before(next) {
+			next, ok = w.deadline, true
+		}
+	}
+
+	return next, ok
+}
+
+// settleTimer returns a timer that fires when the bus was inactive for the settle time, if there is a deadline
+// to advance the virtual clock to. It is restarted on every iteration of the run loop.
+func (b *SyncBus) settleTimer() <-chan time.Time {
+	if b.sim == nil {
+		return nil
+	}
+
+	if b.sim.timer != nil {
+		b.sim.timer.Stop()
+		b.sim.timer = nil
+	}
+
+	if _, ok := b.nextEvent(); !ok {
+		return nil
+	}
+
+	b.sim.timer = time.NewTimer(b.sim.settle)
+	return b.sim.timer.C
+}
+
+// advanceClock advances the virtual clock to the next deadline, and returns the new time.
+func (b *SyncBus) advanceClock() time.Time {
+	next, _ := b.nextEvent()
+	return b.sim.clock.advance(next)
+}
+
+// Clock returns the clock of the bus. With WithSimulatedTime, it is the virtual clock controlled by the bus,
+// otherwise it uses the real time.
+//
+// If the receiver *SyncBus is nil, it returns a clock using the real time.
+func (b *SyncBus) Clock() Clock {
+	if b == nil || b.sim == nil {
+		return realClock{}
+	}
+
+	return b.sim.clock
+}
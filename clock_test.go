@@ -0,0 +1,89 @@
+package syncbus
+
+import (
+	"testing"
+	"time"
+)
+
+func TestRealClock(t *testing.T) {
+	var bus *SyncBus
+	c := bus.Clock()
+	start := c.Now()
+	c.Sleep(time.Millisecond)
+	if c.Since(start) < time.Millisecond {
+		t.Error("failed to sleep")
+	}
+
+	tk := New(time.Second).Clock().NewTicker(time.Millisecond)
+	defer tk.Stop()
+	<-tk.C
+}
+
+func TestSimulatedTimeSleep(t *testing.T) {
+	bus := New(time.Minute, WithSimulatedTime(0))
+	defer bus.Close()
+
+	c := bus.Clock()
+	start, realStart := c.Now(), time.Now()
+	go func() {
+		c.Sleep(30 * time.Second)
+		bus.Signal("foo")
+	}()
+
+	if err := bus.Wait("foo"); err != nil {
+		t.Fatal(err)
+	}
+
+	if d := c.Since(start); d != 30*time.Second {
+		t.Error("unexpected virtual time", d)
+	}
+
+	if d := time.Since(realStart); d > 15*time.Second {
+		t.Error("took too long", d)
+	}
+}
+
+func TestSimulatedTimeTimeout(t *testing.T) {
+	bus := New(time.Hour, WithSimulatedTime(0))
+	defer bus.Close()
+
+	c := bus.Clock()
+	start := c.Now()
+	if err := bus.Wait("foo"); err != ErrTimeout {
+		t.Fatal("failed to timeout", err)
+	}
+
+	if d := c.Since(start); d != time.Hour {
+		t.Error("unexpected virtual time", d)
+	}
+}
+
+func TestSimulatedTimeTicker(t *testing.T) {
+	bus := New(time.Hour, WithSimulatedTime(0))
+	defer bus.Close()
+
+	c := bus.Clock()
+	start := c.Now()
+	tk := c.NewTicker(10 * time.Second)
+	for i := 1; i <= 3; i++ {
+		now := <-tk.C
+		if d := now.Sub(start); d != time.Duration(i)*10*time.Second {
+			t.Fatal("unexpected tick", d)
+		}
+	}
+
+	tk.Stop()
+	if err := bus.WaitTimeout(time.Minute, "foo"); err != ErrTimeout {
+		t.Fatal("failed to timeout", err)
+	}
+
+	if d := c.Since(start); d != 30*time.Second+time.Minute {
+		t.Error("unexpected virtual time", d)
+	}
+
+	select {
+	case <-c.After(0):
+	default:
+		t.Error("failed to fire immediately")
+	}
+}
@@ -0,0 +1,82 @@
+package syncbus
+
+import (
+	"fmt"
+	"path/filepath"
+	"runtime"
+	"strings"
+)
+
+// UseAfterCloseError is returned by Wait, and passed to the handler set by WithCloseGuard, when an operation is
+// called on the bus after or concurrently with Close.
+type UseAfterCloseError struct {
+
+	// Op is the name of the operation, e.g. Wait or Signal.
+	Op string
+
+	// Keys contains the keys passed to the operation.
+	Keys []string
+
+	// Caller is the location of the code that called the operation, outside of the package.
+	Caller string
+}
+
+// WithCloseGuard enables detecting the Wait, Signal and Reset calls made after or concurrently with Close. Such
+// calls are typically made by goroutines that outlive the test, and they tend to wedge the test suites. With the
+// guard, the affected Wait calls return a *UseAfterCloseError, instead of plain ErrClosed, and the error is also
+// passed to h, that is typically used to fail the test, e.g. by calling t.Error. Unlike the violation handler, h
+// is called on the goroutine of the misused operation. It can be nil.
+func WithCloseGuard(h func(error)) Option {
+	return func(o *options) {
+		o.closeGuard = true
+		o.closeGuardHandler = h
+	}
+}
+
+var packageDir = func() string {
+	_, file, _, _ := runtime.Caller(0)
+	return filepath.Dir(file)
+}()
+
+func (err *UseAfterCloseError) Error() string {
+	return fmt.Sprintf("%v: %s %s called at %s", ErrClosed, err.Op, strings.Join(err.Keys, ", "), err.Caller)
+}
+
+// Unwrap returns ErrClosed.
+func (err *UseAfterCloseError) Unwrap() error {
+	return ErrClosed
+}
+
+// externalCaller returns the location of the first caller outside of the package, skipping the frames of the
+// non-test files of the package.
+func externalCaller() string {
+	pc := make([]uintptr, 32)
+	n := runtime.Callers(2, pc)
+	frames := runtime.CallersFrames(pc[:n])
+	for {
+		f, more := frames.Next()
+		if filepath.Dir(f.File) != packageDir || strings.HasSuffix(f.File, "_test.go") {
+			return fmt.Sprintf("%s:%d", f.File, f.Line)
+		}
+
+		if !more {
+			return "unknown"
+		}
+	}
+}
+
+// useAfterClose reports an operation called after or concurrently with Close, when the close guard is enabled.
+// It returns the error to be returned by Wait.
+func (b *SyncBus) useAfterClose(op string, keys []string) error {
+	err := b.closedErr()
+	if !b.options.closeGuard || err != ErrClosed {
+		return err
+	}
+
+	uerr := &UseAfterCloseError{Op: op, Keys: keys, Caller: externalCaller()}
+	if b.options.closeGuardHandler != nil {
+		b.options.closeGuardHandler(uerr)
+	}
+
+	return uerr
+}
@@ -0,0 +1,74 @@
+package syncbus
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestCloseGuard(t *testing.T) {
+	var reported []error
+	bus := New(120*time.Millisecond, WithCloseGuard(func(err error) { reported = append(reported, err) }))
+	bus.Close()
+
+	err := bus.Wait("foo")
+	var uerr *UseAfterCloseError
+	if !errors.As(err, &uerr) || !errors.Is(err, ErrClosed) {
+		t.Fatal("failed to detect wait after close", err)
+	}
+
+	if uerr.Op != "Wait" || len(uerr.Keys) != 1 || uerr.Keys[0] != "foo" {
+		t.Error("invalid error", uerr)
+	}
+
+	if !strings.Contains(uerr.Caller, "closeguard_test.go") {
+		t.Error("invalid caller", uerr.Caller)
+	}
+
+	bus.Signal("bar")
+	bus.ResetSignals("baz")
+	bus.Reset()
+	bus.Namespace("test").Reset()
+	if len(reported) != 5 {
+		t.Fatal("failed to report the misuse", len(reported))
+	}
+
+	for i, op := range []string{"Wait", "Signal", "ResetSignals", "Reset", "Reset"} {
+		if !errors.As(reported[i], &uerr) || uerr.Op != op || !strings.Contains(uerr.Caller, "closeguard_test.go") {
+			t.Error("invalid report", reported[i])
+		}
+	}
+}
+
+func TestCloseGuardPending(t *testing.T) {
+	bus := New(120*time.Millisecond, WithCloseGuard(nil))
+	errs := make(chan error, 1)
+	go func() { errs <- bus.Wait("foo") }()
+	for bus.Stats().Waiting != 1 {
+		time.Sleep(time.Millisecond / 10)
+	}
+
+	bus.Close()
+	if err := <-errs; err != ErrClosed {
+		t.Error("failed to release the pending wait with ErrClosed", err)
+	}
+}
+
+func TestCloseGuardBackground(t *testing.T) {
+	var reported int
+	bus := New(120*time.Millisecond, WithCloseGuard(func(error) { reported++ }))
+	bus.Close()
+	bus.signalBackground("foo")
+	if reported != 0 {
+		t.Error("unexpected report")
+	}
+}
+
+func TestWithoutCloseGuard(t *testing.T) {
+	bus := New(120 * time.Millisecond)
+	bus.Close()
+	if err := bus.Wait("foo"); err != ErrClosed {
+		t.Error("unexpected error", err)
+	}
+}
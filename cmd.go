@@ -0,0 +1,208 @@
+package syncbus
+
+import (
+	"bytes"
+	"io"
+	"os/exec"
+	"regexp"
+	"sync"
+)
+
+type outputPattern struct {
+	text string
+	rx   *regexp.Regexp
+}
+
+// valueStore is implemented by the buses that can store a value alongside a signal, e.g. *SyncBus.
+type valueStore interface {
+	Put(key string, v interface{})
+}
+
+type lineMatcher struct {
+	mx   sync.Mutex
+	cmd  *Cmd
+	line []byte
+}
+
+// Cmd wraps an exec.Cmd, and signals the lifecycle events of the child process: <name>.started when it was
+// started, <name>.exited when it exited, and <name>.output.<pattern> when a line of its output, either stdout
+// or stderr, matches a pattern registered with OnOutput.
+//
+// When the bus supports storing values, like *SyncBus, <name>.exited is set with Put, storing the exit code
+// of the child process as an int, that can be retrieved with Get after waiting for the signal. On other
+// buses, e.g. a LinkBus or a remote bus, it is only signaled, and the exit code is available from ExitCode.
+//
+// Once started, the child process is waited for in the background, so that the exited signal gets set even if
+// the Wait method is not called.
+type Cmd struct {
+	*exec.Cmd
+	bus      Bus
+	name     string
+	patterns []outputPattern
+	exitCode int
+	done     chan struct{}
+	err      error
+}
+
+// NewCmd wraps cmd, using name as the prefix of the signaled keys.
+func NewCmd(b Bus, name string, cmd *exec.Cmd) *Cmd {
+	return &Cmd{bus: b, name: name, Cmd: cmd}
+}
+
+func (c *Cmd) signal(key string) {
+	if c.bus != nil {
+		c.bus.Signal(c.name + "." + key)
+	}
+}
+
+func (c *Cmd) signalExit() {
+	if vs, ok := c.bus.(valueStore); ok {
+		vs.Put(c.name+".exited", c.exitCode)
+		return
+	}
+
+	c.signal("exited")
+}
+
+func (m *lineMatcher) Write(p []byte) (int, error) {
+	m.mx.Lock()
+	defer m.mx.Unlock()
+	m.line = append(m.line, p...)
+	for {
+		i := bytes.IndexByte(m.line, '\n')
+		if i < 0 {
+			return len(p), nil
+		}
+
+		m.cmd.match(m.line[:i])
+		m.line = m.line[i+1:]
+	}
+}
+
+func (m *lineMatcher) flush() {
+	m.mx.Lock()
+	defer m.mx.Unlock()
+	if len(m.line) > 0 {
+		m.cmd.match(m.line)
+		m.line = nil
+	}
+}
+
+func (c *Cmd) match(line []byte) {
+	for _, p := range c.patterns {
+		if p.rx.Match(line) {
+			c.signal("output." + p.text)
+		}
+	}
+}
+
+func teeOutput(w io.Writer, m *lineMatcher) io.Writer {
+	if w == nil {
+		return m
+	}
+
+	return io.MultiWriter(w, m)
+}
+
+// sameWriter tells whether stdout and stderr are the same writer, the same way as exec.Cmd does it, in which
+// case exec.Cmd serializes the writes to them.
+func sameWriter(stdout, stderr io.Writer) (same bool) {
+	defer func() {
+		if recover() != nil {
+			same = false
+		}
+	}()
+
+	return stdout != nil && stdout == stderr
+}
+
+// matchOutput connects the line matchers to the output of the child process. Every stream gets its own
+// matcher, so that the partial lines of the two streams don't get mixed, except when stdout and stderr are
+// the same writer, in which case they share a single one, preserving that exec.Cmd sees them as the same
+// writer.
+func (c *Cmd) matchOutput() []*lineMatcher {
+	if len(c.patterns) == 0 {
+		return nil
+	}
+
+	if sameWriter(c.Stdout, c.Stderr) {
+		m := &lineMatcher{cmd: c}
+		w := teeOutput(c.Stdout, m)
+		c.Stdout, c.Stderr = w, w
+		return []*lineMatcher{m}
+	}
+
+	stdout, stderr := &lineMatcher{cmd: c}, &lineMatcher{cmd: c}
+	c.Stdout = teeOutput(c.Stdout, stdout)
+	c.Stderr = teeOutput(c.Stderr, stderr)
+	return []*lineMatcher{stdout, stderr}
+}
+
+// OnOutput registers a regular expression. When a line of the output of the child process matches it, the key
+// <name>.output.<pattern> is signaled. It needs to be called before Start.
+func (c *Cmd) OnOutput(pattern string) error {
+	rx, err := regexp.Compile(pattern)
+	if err != nil {
+		return err
+	}
+
+	c.patterns = append(c.patterns, outputPattern{text: pattern, rx: rx})
+	return nil
+}
+
+// Start starts the child process, and signals <name>.started.
+func (c *Cmd) Start() error {
+	matchers := c.matchOutput()
+	if err := c.Cmd.Start(); err != nil {
+		return err
+	}
+
+	c.signal("started")
+	c.done = make(chan struct{})
+	go func() {
+		c.err = c.Cmd.Wait()
+		for _, m := range matchers {
+			m.flush()
+		}
+
+		c.exitCode = c.ProcessState.ExitCode()
+		close(c.done)
+		c.signalExit()
+	}()
+
+	return nil
+}
+
+// Wait waits for the child process to exit, and returns the same error as exec.Cmd.Wait.
+func (c *Cmd) Wait() error {
+	if c.done == nil {
+		return c.Cmd.Wait()
+	}
+
+	<-c.done
+	return c.err
+}
+
+// Run starts the child process, and waits for it to exit.
+func (c *Cmd) Run() error {
+	if err := c.Start(); err != nil {
+		return err
+	}
+
+	return c.Wait()
+}
+
+// ExitCode returns the exit code of the child process. It is safe to call it after Wait returned, or after
+// <name>.exited was signaled and waited for on a *SyncBus. Before the process exited, it returns 0.
+func (c *Cmd) ExitCode() int {
+	if c.done == nil {
+		return 0
+	}
+
+	select {
+	case <-c.done:
+		return c.exitCode
+	default:
+		return 0
+	}
+}
@@ -0,0 +1,132 @@
+package syncbus
+
+import (
+	"bytes"
+	"os/exec"
+	"testing"
+	"time"
+)
+
+func TestCmd(t *testing.T) {
+	sh, err := exec.LookPath("sh")
+	if err != nil {
+		t.Skip("sh not found")
+	}
+
+	bus := New(3 * time.Second)
+	defer bus.Close()
+
+	var out bytes.Buffer
+	execCmd := exec.Command(sh, "-c", "echo listening on 8080; read line; echo $line >&2; exit 3")
+	execCmd.Stdout = &out
+	stdin, err := execCmd.StdinPipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cmd := NewCmd(bus, "server", execCmd)
+	if err := cmd.OnOutput("listening on [0-9]+"); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := cmd.OnOutput("^bye$"); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := cmd.Start(); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := bus.Wait("server.started", "server.output.listening on [0-9]+"); err != nil {
+		t.Fatal(err)
+	}
+
+	stdin.Write([]byte("bye\n"))
+	if err := bus.Wait("server.output.^bye$", "server.exited"); err != nil {
+		t.Fatal(err)
+	}
+
+	if cmd.ExitCode() != 3 {
+		t.Error("invalid exit code", cmd.ExitCode())
+	}
+
+	if code, ok := bus.Get("server.exited"); !ok || code != 3 {
+		t.Error("invalid exit code value", code, ok)
+	}
+
+	if err := cmd.Wait(); err == nil {
+		t.Error("failed to return exit error")
+	}
+
+	if out.String() != "listening on 8080\n" {
+		t.Error("invalid output", out.String())
+	}
+}
+
+func TestCmdInvalidPattern(t *testing.T) {
+	cmd := NewCmd(nil, "test", exec.Command("true"))
+	if err := cmd.OnOutput("("); err == nil {
+		t.Error("failed to fail")
+	}
+}
+
+func TestCmdRunNilBus(t *testing.T) {
+	tr, err := exec.LookPath("true")
+	if err != nil {
+		t.Skip("true not found")
+	}
+
+	cmd := NewCmd(nil, "test", exec.Command(tr))
+	if err := cmd.Run(); err != nil || cmd.ExitCode() != 0 {
+		t.Error("failed to run", err)
+	}
+}
+
+func TestCmdOutputStreams(t *testing.T) {
+	sh, err := exec.LookPath("sh")
+	if err != nil {
+		t.Skip("sh not found")
+	}
+
+	bus := New(3 * time.Second)
+	defer bus.Close()
+
+	var out bytes.Buffer
+	execCmd := exec.Command(sh, "-c", "printf foo; printf bar >&2; echo; echo >&2; echo baz")
+	execCmd.Stdout = &out
+	execCmd.Stderr = &out
+	cmd := NewCmd(bus, "split", execCmd)
+	if err := cmd.OnOutput("^baz$"); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := cmd.Run(); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := bus.Wait("split.output.^baz$", "split.exited"); err != nil {
+		t.Fatal(err)
+	}
+
+	if execCmd.Stdout != execCmd.Stderr {
+		t.Error("failed to keep stdout and stderr the same writer")
+	}
+
+	execCmd = exec.Command(sh, "-c", "printf foo; sleep 0.01; printf bar >&2; echo; echo >&2")
+	cmd = NewCmd(bus, "separate", execCmd)
+	if err := cmd.OnOutput("^foobar$"); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := cmd.Run(); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := bus.Wait("separate.exited"); err != nil {
+		t.Fatal(err)
+	}
+
+	if bus.IsSet("separate.output.^foobar$") {
+		t.Error("mixed the partial lines of stdout and stderr")
+	}
+}
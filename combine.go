@@ -0,0 +1,106 @@
+package syncbus
+
+import "reflect"
+
+// Awaitable is a wait registered earlier, that can be composed with All and Any. It is implemented by
+// *WaitHandle and *Combined.
+type Awaitable interface {
+
+	// Wait blocks until the wait returns, and returns its result.
+	Wait() error
+
+	// Status tells the state of the wait, without blocking.
+	Status() WaitStatus
+
+	// Done returns a channel that delivers the result of the wait, and then gets closed.
+	Done() <-chan error
+
+	finished() <-chan struct{}
+}
+
+// Combined is the awaitable result of All and Any.
+type Combined struct {
+	released chan struct{}
+	done     chan error
+	err      error
+}
+
+// combine resolves the combined wait in a single goroutine, selecting on all the waits. When anyOf is false,
+// it returns the first error, or nil if all the waits were satisfied. When anyOf is true, it returns nil when
+// any of the waits was satisfied, or the first error if none of them was.
+func combine(waits []Awaitable, anyOf bool) *Combined {
+	c := &Combined{released: make(chan struct{}), done: make(chan error, 1)}
+	waits = append([]Awaitable(nil), waits...)
+	cases := make([]reflect.SelectCase, len(waits))
+	for i, w := range waits {
+		cases[i] = reflect.SelectCase{Dir: reflect.SelectRecv, Chan: reflect.ValueOf(w.finished())}
+	}
+
+	go func() {
+		var first error
+		for len(cases) > 0 {
+			i, _, _ := reflect.Select(cases)
+			err := waits[i].Wait()
+			if (err == nil) == anyOf {
+				c.finish(err)
+				return
+			}
+
+			if first == nil {
+				first = err
+			}
+
+			cases = append(cases[:i], cases[i+1:]...)
+			waits = append(waits[:i], waits[i+1:]...)
+		}
+
+		c.finish(first)
+	}()
+
+	return c
+}
+
+func (c *Combined) finish(err error) {
+	c.err = err
+	c.done <- err
+	close(c.done)
+	close(c.released)
+}
+
+// All combines the waits into a single one, that is satisfied when all of them are satisfied, and fails with
+// the first error of any of them. It allows composing readiness trees from independently registered waits,
+// e.g. All(h1, Any(h2, h3)). Without arguments, it is satisfied immediately.
+func All(waits ...Awaitable) *Combined {
+	return combine(waits, false)
+}
+
+// Any combines the waits into a single one, that is satisfied when any of them is satisfied. When all of them
+// fail, it fails with the first error. Without arguments, it is satisfied immediately.
+func Any(waits ...Awaitable) *Combined {
+	return combine(waits, true)
+}
+
+// Wait blocks until the combined wait returns, and returns its result. It can be called multiple times.
+func (c *Combined) Wait() error {
+	<-c.released
+	return c.err
+}
+
+// Status tells the state of the combined wait, without blocking.
+func (c *Combined) Status() WaitStatus {
+	select {
+	case <-c.released:
+		return statusOf(c.err)
+	default:
+		return WaitPending
+	}
+}
+
+// Done returns a channel that delivers the result of the combined wait, once it returns, and then gets closed.
+func (c *Combined) Done() <-chan error {
+	return c.done
+}
+
+func (c *Combined) finished() <-chan struct{} {
+	return c.released
+}
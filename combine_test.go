@@ -0,0 +1,65 @@
+package syncbus
+
+import (
+	"testing"
+	"time"
+)
+
+func TestCombineEmpty(t *testing.T) {
+	if err := All().Wait(); err != nil {
+		t.Error(err)
+	}
+
+	if err := Any().Wait(); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestAll(t *testing.T) {
+	bus := New(120 * time.Millisecond)
+	defer bus.Close()
+
+	c := All(bus.WaitHandle("foo"), Any(bus.WaitHandle("bar"), bus.WaitHandle("baz")))
+	bus.Signal("foo")
+	if s := c.Status(); s != WaitPending {
+		t.Fatal("unexpected status", s)
+	}
+
+	bus.Signal("baz")
+	if err := <-c.Done(); err != nil {
+		t.Fatal(err)
+	}
+
+	if s := c.Status(); s != WaitSatisfied {
+		t.Error("unexpected status", s)
+	}
+
+	if err := c.Wait(); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestAllFails(t *testing.T) {
+	bus := New(120 * time.Millisecond)
+	defer bus.Close()
+	short := New(3 * time.Millisecond)
+	defer short.Close()
+
+	c := All(bus.WaitHandle("foo"), short.WaitHandle("bar"))
+	if err := c.Wait(); err != ErrTimeout {
+		t.Error("failed to fail", err)
+	}
+
+	if s := c.Status(); s != WaitTimedOut {
+		t.Error("unexpected status", s)
+	}
+}
+
+func TestAnyFails(t *testing.T) {
+	bus := New(3 * time.Millisecond)
+	defer bus.Close()
+
+	if err := Any(bus.WaitHandle("foo"), bus.WaitHandle("bar")).Wait(); err != ErrTimeout {
+		t.Error("failed to fail", err)
+	}
+}
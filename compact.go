@@ -0,0 +1,116 @@
+package syncbus
+
+import "math/bits"
+
+// signalSet stores the state of the signals in a compact form, suitable also for huge key spaces. The keys are
+// interned on first use, mapped to small, dense IDs, and the set signals are stored in a bitmap indexed by the
+// IDs. The counters of the signals are stored in a slice indexed by the same IDs. The interned keys are kept
+// when the signals are reset, so setting them again doesn't allocate.
+type signalSet struct {
+	ids    map[string]uint32
+	keys   []string
+	bits   []uint64
+	counts []int
+	size   int
+}
+
+func newSignalSet(size int) *signalSet {
+	return &signalSet{
+		ids:    make(map[string]uint32, size),
+		keys:   make([]string, 0, size),
+		bits:   make([]uint64, 0, (size+63)/64),
+		counts: make([]int, 0, size),
+	}
+}
+
+// intern returns the ID of a key, registering it when it's not known yet.
+func (s *signalSet) intern(key string) uint32 {
+	if id, ok := s.ids[key]; ok {
+		return id
+	}
+
+	id := uint32(len(s.keys))
+	s.ids[key] = id
+	s.keys = append(s.keys, key)
+	s.counts = append(s.counts, 0)
+	if int(id/64) >= len(s.bits) {
+		s.bits = append(s.bits, 0)
+	}
+
+	return id
+}
+
+// internKeys returns the IDs of the keys, registering the ones that are not known yet.
+func (s *signalSet) internKeys(keys []string) []uint32 {
+	ids := make([]uint32, len(keys))
+	for i, key := range keys {
+		ids[i] = s.intern(key)
+	}
+
+	return ids
+}
+
+func (s *signalSet) hasID(id uint32) bool {
+	return s.bits[id/64]&(1<<(id%64)) != 0
+}
+
+func (s *signalSet) has(key string) bool {
+	id, ok := s.ids[key]
+	return ok && s.hasID(id)
+}
+
+// set sets the signal represented by key, and increments its counter.
+func (s *signalSet) set(key string) {
+	id := s.intern(key)
+	s.counts[id]++
+	if !s.hasID(id) {
+		s.bits[id/64] |= 1 << (id % 64)
+		s.size++
+	}
+}
+
+func (s *signalSet) clear(key string) {
+	id, ok := s.ids[key]
+	if !ok || !s.hasID(id) {
+		return
+	}
+
+	s.bits[id/64] &^= 1 << (id % 64)
+	s.size--
+}
+
+func (s *signalSet) clearAll() {
+	for i := range s.bits {
+		s.bits[i] = 0
+	}
+
+	s.size = 0
+}
+
+func (s *signalSet) len() int {
+	return s.size
+}
+
+// count returns how many times the signal represented by key was set.
+func (s *signalSet) count(key string) int {
+	id, ok := s.ids[key]
+	if !ok {
+		return 0
+	}
+
+	return s.counts[id]
+}
+
+// list returns the keys of the set signals, in the order of their IDs.
+func (s *signalSet) list() []string {
+	var keys []string
+	for i, w := range s.bits {
+		for w != 0 {
+			bit := bits.TrailingZeros64(w)
+			keys = append(keys, s.keys[i*64+bit])
+			w &^= 1 << uint(bit)
+		}
+	}
+
+	return keys
+}
@@ -0,0 +1,100 @@
+package syncbus
+
+import (
+	"fmt"
+	"testing"
+	"time"
+)
+
+func TestSignalSet(t *testing.T) {
+	s := newSignalSet(0)
+	for i := 0; i < 200; i++ {
+		s.set(fmt.Sprintf("key%d", i))
+	}
+
+	s.set("key0")
+	if s.len() != 200 || !s.has("key0") || !s.has("key199") || s.has("key200") || s.count("key0") != 2 {
+		t.Fatal("invalid state", s.len())
+	}
+
+	s.clear("key64")
+	s.clear("key64")
+	s.clear("unknown")
+	if s.len() != 199 || s.has("key64") || s.count("key64") != 1 {
+		t.Error("failed to clear", s.len())
+	}
+
+	l := s.list()
+	if len(l) != 199 || l[0] != "key0" || l[64] != "key65" || l[198] != "key199" {
+		t.Error("invalid list", l)
+	}
+
+	s.clearAll()
+	if s.len() != 0 || s.has("key0") || len(s.list()) != 0 || s.count("key0") != 2 {
+		t.Error("failed to clear all", s.len())
+	}
+
+	s.set("key3")
+	if l := s.list(); len(l) != 1 || l[0] != "key3" || len(s.keys) != 200 {
+		t.Error("failed to reuse the interned key", l)
+	}
+}
+
+func TestManyKeys(t *testing.T) {
+	bus := New(120 * time.Millisecond)
+	defer bus.Close()
+
+	var keys []string
+	for i := 0; i < 1<<12; i++ {
+		keys = append(keys, fmt.Sprintf("client.%d.ready", i))
+	}
+
+	bus.Signal(keys...)
+	if err := bus.Wait(keys...); err != nil {
+		t.Fatal(err)
+	}
+
+	bus.ResetSignals(keys[1:]...)
+	s := bus.SetKeys()
+	if len(s) != 1 || s[0] != "client.0.ready" {
+		t.Error("invalid keys", len(s))
+	}
+}
+
+func TestInternKeys(t *testing.T) {
+	s := newSignalSet(0)
+	s.set("foo")
+	ids := s.internKeys([]string{"bar", "foo", "bar"})
+	if len(ids) != 3 || ids[0] != 1 || ids[1] != 0 || ids[2] != 1 {
+		t.Fatal("invalid IDs", ids)
+	}
+
+	if !s.hasID(ids[1]) || s.hasID(ids[0]) || s.len() != 1 {
+		t.Error("invalid state")
+	}
+}
+
+func BenchmarkSignalManyWaiters(b *testing.B) {
+	bus := New(time.Minute)
+	defer bus.Close()
+
+	errs := make(chan error, 1<<10)
+	for i := 0; i < cap(errs); i++ {
+		go func(i int) { errs <- bus.Wait(fmt.Sprintf("client.%d.ready", i), "start") }(i)
+	}
+
+	for bus.Stats().Waiting != cap(errs) {
+		time.Sleep(time.Millisecond)
+	}
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		bus.Signal(fmt.Sprintf("other.%d", i))
+	}
+
+	b.StopTimer()
+	bus.Close()
+	for i := 0; i < cap(errs); i++ {
+		<-errs
+	}
+}
@@ -0,0 +1,92 @@
+package syncbus
+
+// condition is implemented by the wait conditions evaluated against the signals as a whole.
+type condition interface {
+	eval(isSet func(key string) bool) bool
+}
+
+// Condition is a wait condition, built as a conjunction of clauses, where each clause is satisfied when any of
+// its keys is set. It is passed to WaitCond, and it is evaluated by the run loop atomically. An empty condition
+// is satisfied immediately.
+type Condition struct {
+	clauses [][]string
+}
+
+// Cond creates an empty wait condition.
+func Cond() *Condition {
+	return &Condition{}
+}
+
+// All adds the condition that all the signals represented by the keys are set.
+func (c *Condition) All(keys ...string) *Condition {
+	for _, key := range keys {
+		c.clauses = append(c.clauses, []string{key})
+	}
+
+	return c
+}
+
+// Any adds the condition that at least one of the signals represented by the keys is set. Without keys, it is
+// a noop.
+func (c *Condition) Any(keys ...string) *Condition {
+	if len(keys) > 0 {
+		c.clauses = append(c.clauses, keys)
+	}
+
+	return c
+}
+
+func (c *Condition) keys() []string {
+	var keys []string
+	for _, clause := range c.clauses {
+		keys = append(keys, clause...)
+	}
+
+	return uniqueKeys(keys)
+}
+
+func uniqueKeys(keys []string) []string {
+	var u []string
+	seen := make(map[string]bool)
+	for _, key := range keys {
+		if !seen[key] {
+			seen[key] = true
+			u = append(u, key)
+		}
+	}
+
+	return u
+}
+
+func (c *Condition) eval(isSet func(key string) bool) bool {
+	for _, clause := range c.clauses {
+		var any bool
+		for _, key := range clause {
+			if isSet(key) {
+				any = true
+				break
+			}
+		}
+
+		if !any {
+			return false
+		}
+	}
+
+	return true
+}
+
+// WaitCond blocks until the condition is satisfied, or returns an ErrTimeout if it doesn't happen within the
+// timeout of the bus. The condition is evaluated by the run loop, whenever the signals change. It allows
+// waiting for complex readiness conditions, e.g. WaitCond(Cond().All("a", "b").Any("c", "d")), without
+// having to wait in multiple goroutines in parallel, and merging the results.
+//
+// If the receiver *SyncBus is nil, or the condition is empty, it is a noop.
+func (b *SyncBus) WaitCond(c *Condition) error {
+	if b == nil || c == nil || len(c.clauses) == 0 {
+		return nil
+	}
+
+	cc := &Condition{clauses: append([][]string(nil), c.clauses...)}
+	return b.waitItem(waitItem{keys: c.keys(), cond: cc})
+}
@@ -0,0 +1,59 @@
+package syncbus
+
+import (
+	"testing"
+	"time"
+)
+
+func TestNilWaitCond(t *testing.T) {
+	var bus *SyncBus
+	if err := bus.WaitCond(Cond().All("foo")); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestWaitCondEmpty(t *testing.T) {
+	bus := New(120 * time.Millisecond)
+	defer bus.Close()
+	if err := bus.WaitCond(Cond().Any()); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestWaitCond(t *testing.T) {
+	bus := New(120 * time.Millisecond)
+	defer bus.Close()
+
+	errs := make(chan error, 1)
+	go func() { errs <- bus.WaitCond(Cond().All("a", "b").Any("c", "d")) }()
+	for bus.Stats().Waiting != 1 {
+		time.Sleep(time.Millisecond / 10)
+	}
+
+	bus.Signal("a", "c")
+	bus.Signal("e")
+	if s := bus.Stats(); s.Waiting != 1 {
+		t.Fatal("unexpected release", s)
+	}
+
+	bus.Signal("b")
+	if err := <-errs; err != nil {
+		t.Error(err)
+	}
+
+	bus.Reset()
+	bus.Signal("a", "b", "d")
+	if err := bus.WaitCond(Cond().All("a", "b").Any("c", "d")); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestWaitCondTimeout(t *testing.T) {
+	bus := New(12 * time.Millisecond)
+	defer bus.Close()
+
+	bus.Signal("a", "b")
+	if err := bus.WaitCond(Cond().All("a", "b").Any("c", "d")); err != ErrTimeout {
+		t.Error("failed to timeout", err)
+	}
+}
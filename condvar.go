@@ -0,0 +1,95 @@
+package syncbus
+
+import "time"
+
+type signalOneItem struct {
+	key    string
+	result chan bool
+}
+
+// Broadcast sets the signal represented by key, waking all the waits that it satisfies, like Signal. The
+// signal stays set until it is reset, so the waits registered later are satisfied by it, too.
+//
+// If the receiver *SyncBus is nil, it is a noop.
+func (b *SyncBus) Broadcast(key string) {
+	b.Signal(key)
+}
+
+// satisfiedWith tells whether a keyed wait would be satisfied if the signal of key was set. Only the plain
+// waits, and the waits with conditions or expressions are considered.
+func (b *SyncBus) satisfiedWith(w waitItem, key string) bool {
+	if w.count != nil || w.predicate != nil || w.order != nil {
+		return false
+	}
+
+	if w.cond != nil {
+		return w.cond.eval(func(k string) bool { return k == key || b.signals.has(k) })
+	}
+
+	var depends bool
+	for i, id := range w.ids {
+		if w.keys[i] == key {
+			depends = true
+			continue
+		}
+
+		if !b.signals.hasID(id) {
+			return false
+		}
+	}
+
+	return depends
+}
+
+// signalOneWaiting releases a single wait depending on key, that would be satisfied by its signal. The wait is
+// chosen according to the wakeup order. It tells whether a wait was released.
+func (b *SyncBus) signalOneWaiting(now time.Time, key string) bool {
+	var candidates []released
+	for _, w := range b.waiting {
+		if b.satisfiedWith(w, key) {
+			candidates = append(candidates, released{item: w})
+		}
+	}
+
+	if len(candidates) == 0 {
+		return false
+	}
+
+	r := b.options.wakeupOrder.order(b.rand, candidates)[0]
+	for i, w := range b.waiting {
+		if w.signal == r.item.signal {
+			b.waiting = append(b.waiting[:i], b.waiting[i+1:]...)
+			break
+		}
+	}
+
+	b.releaseWaiting(now, r)
+	return true
+}
+
+// SignalOne wakes exactly one of the waits that depend on the signal represented by key, and that would be
+// satisfied by it, without setting the signal. This way, a wakeup can be handed to a single consumer among
+// many. The wait is chosen according to the wakeup order, by default the one registered first. Like with the
+// notification of a condition variable, when there is no such wait, the wakeup is lost. SignalOne considers
+// only the waits for keys, conditions and expressions. It tells whether a wait was woken.
+//
+// If the receiver *SyncBus is nil, it returns false.
+func (b *SyncBus) SignalOne(key string) bool {
+	if b == nil {
+		return false
+	}
+
+	o := signalOneItem{key: key, result: make(chan bool, 1)}
+	if b.closed() {
+		b.useAfterClose("SignalOne", []string{key})
+		return false
+	}
+
+	select {
+	case b.signalOne <- o:
+		return <-o.result
+	case <-b.done:
+		b.useAfterClose("SignalOne", []string{key})
+		return false
+	}
+}
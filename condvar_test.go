@@ -0,0 +1,83 @@
+package syncbus
+
+import (
+	"testing"
+	"time"
+)
+
+func TestNilSignalOne(t *testing.T) {
+	var bus *SyncBus
+	bus.Broadcast("foo")
+	if bus.SignalOne("foo") {
+		t.Error("unexpected wakeup")
+	}
+}
+
+func TestSignalOne(t *testing.T) {
+	bus := New(30 * time.Millisecond)
+	defer bus.Close()
+
+	results := make(chan error, 3)
+	for i := 0; i < 3; i++ {
+		go func() { results <- bus.Wait("foo") }()
+		for bus.Stats().Waiting <= i {
+			time.Sleep(time.Millisecond)
+		}
+	}
+
+	if !bus.SignalOne("foo") {
+		t.Fatal("failed to wake")
+	}
+
+	if err := <-results; err != nil {
+		t.Fatal(err)
+	}
+
+	if s := bus.Stats(); s.Waiting != 2 || s.Signals != 0 {
+		t.Fatal("unexpected state", s)
+	}
+
+	bus.Broadcast("foo")
+	for i := 0; i < 2; i++ {
+		if err := <-results; err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestSignalOneNotSatisfied(t *testing.T) {
+	bus := New(3 * time.Millisecond)
+	defer bus.Close()
+
+	if bus.SignalOne("foo") {
+		t.Error("unexpected wakeup without waits")
+	}
+
+	go bus.Wait("foo", "bar")
+	for bus.Stats().Waiting == 0 {
+		time.Sleep(time.Millisecond)
+	}
+
+	if bus.SignalOne("foo") {
+		t.Error("unexpected wakeup of unsatisfied wait")
+	}
+}
+
+func TestSignalOneCond(t *testing.T) {
+	bus := New(30 * time.Millisecond)
+	defer bus.Close()
+
+	result := make(chan error)
+	go func() { result <- bus.WaitCond(Cond().Any("foo", "bar")) }()
+	for bus.Stats().Waiting == 0 {
+		time.Sleep(time.Millisecond)
+	}
+
+	if !bus.SignalOne("bar") {
+		t.Fatal("failed to wake")
+	}
+
+	if err := <-result; err != nil {
+		t.Error(err)
+	}
+}
@@ -0,0 +1,93 @@
+package syncbus
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+)
+
+const controlHelp = `commands:
+  signal <key>...  set the signals
+  reset <key>...   clear the signals
+  resetall         clear all the signals
+  step [<key>]     release the checkpoint, or the oldest pending checkpoint when no key is provided
+  hold <key>       arm the checkpoint
+  dump             print the state of the bus
+  stats            print the stats of the bus
+  help             print this help
+`
+
+// pendingCheckpoint returns the key of the oldest checkpoint, that a process reached, and which was not
+// released yet.
+func (b *SyncBus) pendingCheckpoint() (string, bool) {
+	for _, w := range b.getSnapshot().waiting {
+		if len(w.keys) != 1 || len(w.missing) != 1 {
+			continue
+		}
+
+		if key := strings.TrimSuffix(w.keys[0], ReleaseKey("")); key != w.keys[0] {
+			return key, true
+		}
+	}
+
+	return "", false
+}
+
+func (b *SyncBus) controlCommand(w io.Writer, args []string) {
+	switch {
+	case args[0] == "signal" && len(args) > 1:
+		b.Signal(args[1:]...)
+	case args[0] == "reset" && len(args) > 1:
+		b.ResetSignals(args[1:]...)
+	case args[0] == "resetall" && len(args) == 1:
+		b.Reset()
+	case args[0] == "step" && len(args) == 2:
+		Release(b, args[1])
+	case args[0] == "step" && len(args) == 1:
+		key, ok := b.pendingCheckpoint()
+		if !ok {
+			fmt.Fprintln(w, "no pending checkpoint")
+			return
+		}
+
+		Release(b, key)
+		fmt.Fprintf(w, "released: %s\n", key)
+	case args[0] == "hold" && len(args) == 2:
+		Hold(b, args[1])
+	case args[0] == "dump" && len(args) == 1:
+		fmt.Fprint(w, b.Dump())
+	case args[0] == "stats" && len(args) == 1:
+		fmt.Fprintf(w, "%+v\n", b.Stats())
+	case args[0] == "help" && len(args) == 1:
+		fmt.Fprint(w, controlHelp)
+	default:
+		fmt.Fprintf(w, "invalid command: %s\n", strings.Join(args, " "))
+	}
+}
+
+// Control reads simple commands from r, one per line, and executes them on the bus, writing their output to
+// w. The supported commands are: signal <key>..., reset <key>..., resetall, step [<key>], hold <key>, dump,
+// stats and help. The step command releases a checkpoint (see Checkpoint), and without a key, it releases the
+// oldest pending one. It returns when r is exhausted, with the read error, if any, other than io.EOF. It
+// allows a developer attached to a hung test binary to manually drive the choreography and inspect the state,
+// e.g. by calling go bus.Control(os.Stdin, os.Stderr) in debug mode.
+//
+// If the receiver *SyncBus is nil, it is a noop.
+func (b *SyncBus) Control(r io.Reader, w io.Writer) error {
+	if b == nil {
+		return nil
+	}
+
+	s := bufio.NewScanner(r)
+	for s.Scan() {
+		args := strings.Fields(s.Text())
+		if len(args) == 0 {
+			continue
+		}
+
+		b.controlCommand(w, args)
+	}
+
+	return s.Err()
+}
@@ -0,0 +1,94 @@
+package syncbus
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestNilControl(t *testing.T) {
+	var bus *SyncBus
+	var buf bytes.Buffer
+	if err := bus.Control(strings.NewReader("signal foo\ndump\n"), &buf); err != nil || buf.Len() != 0 {
+		t.Error("unexpected result", err, buf.String())
+	}
+}
+
+func TestControl(t *testing.T) {
+	bus := New(120 * time.Millisecond)
+	defer bus.Close()
+
+	var buf bytes.Buffer
+	if err := bus.Control(strings.NewReader("signal foo bar baz\n\nreset bar\ndump\nresetall\nstats\n"), &buf); err != nil {
+		t.Fatal(err)
+	}
+
+	out := buf.String()
+	if !strings.Contains(out, "  baz\n  foo\n") || strings.Contains(out, "bar") || !strings.Contains(out, "Signals:0") {
+		t.Error("invalid output", out)
+	}
+}
+
+func TestControlInvalid(t *testing.T) {
+	bus := New(120 * time.Millisecond)
+	defer bus.Close()
+
+	var buf bytes.Buffer
+	if err := bus.Control(strings.NewReader("signal\nfoo\nhelp\n"), &buf); err != nil {
+		t.Fatal(err)
+	}
+
+	out := buf.String()
+	if !strings.Contains(out, "invalid command: signal\n") || !strings.Contains(out, "invalid command: foo\n") {
+		t.Error("failed to report invalid commands", out)
+	}
+
+	if !strings.Contains(out, "commands:") {
+		t.Error("failed to print help", out)
+	}
+}
+
+func TestControlStep(t *testing.T) {
+	bus := New(120 * time.Millisecond)
+	defer bus.Close()
+
+	var buf bytes.Buffer
+	if err := bus.Control(strings.NewReader("step\n"), &buf); err != nil {
+		t.Fatal(err)
+	}
+
+	if buf.String() != "no pending checkpoint\n" {
+		t.Error("invalid output", buf.String())
+	}
+
+	errs := make(chan error, 2)
+	go func() { errs <- Checkpoint(bus, "first") }()
+	if err := bus.Wait("first"); err != nil {
+		t.Fatal(err)
+	}
+
+	go func() { errs <- Checkpoint(bus, "second") }()
+	if err := bus.Wait("second"); err != nil {
+		t.Fatal(err)
+	}
+
+	for bus.Stats().Waiting != 2 {
+		time.Sleep(time.Millisecond / 10)
+	}
+
+	buf.Reset()
+	if err := bus.Control(strings.NewReader("step\nstep second\n"), &buf); err != nil {
+		t.Fatal(err)
+	}
+
+	if buf.String() != "released: first\n" {
+		t.Error("invalid output", buf.String())
+	}
+
+	for i := 0; i < 2; i++ {
+		if err := <-errs; err != nil {
+			t.Error(err)
+		}
+	}
+}
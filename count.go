@@ -0,0 +1,44 @@
+package syncbus
+
+import "time"
+
+type countWait struct {
+	n      int
+	within time.Duration
+	times  []time.Time
+	done   bool
+}
+
+// countSignal registers a signal of key for the pending count waits.
+func (b *SyncBus) countSignal(now time.Time, key string) {
+	for _, w := range b.waiting {
+		if w.count == nil || w.keys[0] != key {
+			continue
+		}
+
+		c := w.count
+		c.times = append(c.times, now)
+		for len(c.times) > 0 && now.Sub(c.times[0]) > c.within {
+			c.times = c.times[1:]
+		}
+
+		if len(c.times) >= c.n {
+			c.done = true
+		}
+	}
+}
+
+// WaitCount blocks until the signal represented by key is set n times within the provided window, or returns
+// an ErrTimeout if it doesn't happen within the timeout of the bus. Only the Signal calls made after calling
+// WaitCount are counted, regardless of whether the signal was already set. The window starts at the first
+// counted signal, and when the n-th signal doesn't arrive in time, it slides to start at the next one. It can
+// be used to verify batching and debouncing logic.
+//
+// If the receiver *SyncBus is nil, or n is not positive, it is a noop.
+func (b *SyncBus) WaitCount(key string, n int, within time.Duration) error {
+	if b == nil || n <= 0 {
+		return nil
+	}
+
+	return b.waitItem(waitItem{keys: []string{key}, count: &countWait{n: n, within: within}})
+}
@@ -0,0 +1,88 @@
+package syncbus
+
+import (
+	"testing"
+	"time"
+)
+
+func TestNilWaitCount(t *testing.T) {
+	var bus *SyncBus
+	if err := bus.WaitCount("flush", 3, time.Millisecond); err != nil {
+		t.Error(err)
+	}
+}
+
+func waitCount(bus *SyncBus, key string, n int, within time.Duration) <-chan error {
+	c := make(chan error, 1)
+	go func() { c <- bus.WaitCount(key, n, within) }()
+	for bus.Stats().Waiting == 0 {
+		time.Sleep(time.Millisecond / 10)
+	}
+
+	return c
+}
+
+func TestWaitCount(t *testing.T) {
+	bus := New(120 * time.Millisecond)
+	defer bus.Close()
+
+	bus.Signal("flush")
+	c := waitCount(bus, "flush", 3, 60*time.Millisecond)
+	bus.Signal("flush")
+	bus.Signal("flush")
+	select {
+	case err := <-c:
+		t.Fatal("released too early", err)
+	default:
+	}
+
+	bus.Signal("flush")
+	if err := <-c; err != nil {
+		t.Error(err)
+	}
+}
+
+func TestWaitCountIgnoresOtherKeys(t *testing.T) {
+	bus := New(12 * time.Millisecond)
+	defer bus.Close()
+
+	c := waitCount(bus, "flush", 2, 12*time.Millisecond)
+	bus.Signal("flush", "other")
+	bus.Signal("other")
+	if err := <-c; err != ErrTimeout {
+		t.Error("failed to timeout", err)
+	}
+}
+
+func TestWaitCountWindowSlides(t *testing.T) {
+	bus := New(120 * time.Millisecond)
+	defer bus.Close()
+
+	c := waitCount(bus, "flush", 2, 15*time.Millisecond)
+	bus.Signal("flush")
+	time.Sleep(30 * time.Millisecond)
+	bus.Signal("flush")
+	select {
+	case err := <-c:
+		t.Fatal("released outside of the window", err)
+	case <-time.After(3 * time.Millisecond):
+	}
+
+	bus.Signal("flush")
+	if err := <-c; err != nil {
+		t.Error(err)
+	}
+}
+
+func TestWaitCountTimeout(t *testing.T) {
+	bus := New(30 * time.Millisecond)
+	defer bus.Close()
+
+	c := waitCount(bus, "flush", 2, 3*time.Millisecond)
+	bus.Signal("flush")
+	time.Sleep(9 * time.Millisecond)
+	bus.Signal("flush")
+	if err := <-c; err != ErrTimeout {
+		t.Error("failed to timeout", err)
+	}
+}
@@ -0,0 +1,95 @@
+package syncbus
+
+import (
+	"context"
+	"time"
+)
+
+// waitDeadline returns the deadline of a wait. It is the explicitly set deadline, or the one derived from the
+// timeout of the wait, or the timeout of the bus, whichever is set, but not later than the deadline of the
+// context of the wait.
+func (b *SyncBus) waitDeadline(now time.Time, w waitItem) time.Time {
+	d := w.deadline
+	if d.IsZero() {
+		timeout := b.timeout
+		if w.timeout > 0 {
+			timeout = b.scaleTimeout(w.timeout)
+		}
+
+		d = now.Add(timeout)
+	}
+
+	if w.ctx != nil {
+		if cd, ok := w.ctx.Deadline(); ok && cd.Before(d) {
+			d = cd
+		}
+	}
+
+	return d
+}
+
+// cancelWaiting removes a wait whose context was canceled, unless it was already released.
+func (b *SyncBus) cancelWaiting(now time.Time, w waitItem) {
+	for i, wi := range b.waiting {
+		if wi.signal != w.signal {
+			continue
+		}
+
+		b.waiting = append(b.waiting[:i], b.waiting[i+1:]...)
+		if len(b.waiting) == 0 {
+			b.waiting = nil
+		}
+
+		b.accountBlocked(now, wi)
+		wi.release(w.ctx.Err())
+		b.counters.releases++
+		b.countLabels(wi, func(c *counters) { c.releases++ })
+		return
+	}
+}
+
+// receive waits for the result of a registered wait. When the context of the wait is canceled before, it asks
+// the run loop to drop the wait, and returns the error of the context, unless the wait was released meanwhile.
+func (b *SyncBus) receive(w waitItem) error {
+	if w.ctx == nil {
+		return <-w.signal
+	}
+
+	select {
+	case err := <-w.signal:
+		return err
+	case <-w.ctx.Done():
+	}
+
+	select {
+	case b.cancel <- w:
+	case <-b.done:
+	}
+
+	return <-w.signal
+}
+
+// WaitTimeout is like Wait, but it uses the provided timeout instead of the timeout of the bus. A zero or
+// negative timeout means the timeout of the bus.
+//
+// If the receiver *SyncBus is nil, or no key argument is passed to it, it is a noop.
+func (b *SyncBus) WaitTimeout(timeout time.Duration, keys ...string) error {
+	if b == nil || len(keys) == 0 {
+		return nil
+	}
+
+	return b.waitItem(waitItem{keys: keys, timeout: timeout})
+}
+
+// WaitContext is like Wait, but it also returns when the context is canceled, with the error of the context.
+// When the context has a deadline earlier than the timeout of the bus, the wait times out at the deadline of
+// the context, returning ErrTimeout.
+//
+// If the receiver *SyncBus is nil, or no key argument is passed to it, it is a noop.
+func (b *SyncBus) WaitContext(ctx context.Context, keys ...string) error {
+	if b == nil || len(keys) == 0 {
+		return nil
+	}
+
+	return b.waitItem(waitItem{keys: keys, ctx: ctx})
+}
@@ -0,0 +1,98 @@
+package syncbus
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestNilWaitTimeoutContext(t *testing.T) {
+	var bus *SyncBus
+	if err := bus.WaitTimeout(time.Millisecond, "foo"); err != nil {
+		t.Error(err)
+	}
+
+	if err := bus.WaitContext(context.Background(), "foo"); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestWaitTimeout(t *testing.T) {
+	bus := New(time.Hour)
+	defer bus.Close()
+
+	start := time.Now()
+	if err := bus.WaitTimeout(12*time.Millisecond, "foo"); err != ErrTimeout {
+		t.Error("failed to timeout", err)
+	}
+
+	if time.Since(start) > 120*time.Millisecond {
+		t.Error("failed to apply the timeout of the wait")
+	}
+}
+
+func TestWaitTimeoutIndependent(t *testing.T) {
+	bus := New(12 * time.Millisecond)
+	defer bus.Close()
+
+	long := make(chan error, 1)
+	go func() { long <- bus.WaitTimeout(time.Hour, "foo") }()
+	for bus.Stats().Waiting != 1 {
+		time.Sleep(time.Millisecond / 10)
+	}
+
+	if err := bus.Wait("bar"); err != ErrTimeout {
+		t.Error("failed to timeout", err)
+	}
+
+	if s := bus.Stats(); s.Waiting != 1 {
+		t.Error("unexpected timeout of the long wait", s)
+	}
+
+	bus.Signal("foo")
+	if err := <-long; err != nil {
+		t.Error(err)
+	}
+}
+
+func TestWaitContextCanceled(t *testing.T) {
+	bus := New(time.Hour)
+	defer bus.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	errs := make(chan error, 1)
+	go func() { errs <- bus.WaitContext(ctx, "foo") }()
+	for bus.Stats().Waiting != 1 {
+		time.Sleep(time.Millisecond / 10)
+	}
+
+	cancel()
+	if err := <-errs; err != context.Canceled {
+		t.Error("failed to cancel", err)
+	}
+
+	if s := bus.Stats(); s.Waiting != 0 {
+		t.Error("failed to remove the canceled wait", s)
+	}
+}
+
+func TestWaitContextDeadline(t *testing.T) {
+	bus := New(time.Hour)
+	defer bus.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 12*time.Millisecond)
+	defer cancel()
+	if err := bus.WaitContext(ctx, "foo"); err != ErrTimeout && err != context.DeadlineExceeded {
+		t.Error("failed to timeout", err)
+	}
+}
+
+func TestWaitContextSignaled(t *testing.T) {
+	bus := New(120 * time.Millisecond)
+	defer bus.Close()
+
+	bus.Signal("foo")
+	if err := bus.WaitContext(context.Background(), "foo"); err != nil {
+		t.Error(err)
+	}
+}
@@ -0,0 +1,151 @@
+package syncbus
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+type expectedState struct {
+	signals map[string]bool
+	counts  map[string]int
+}
+
+type diffItem struct {
+	expected State
+	result   chan string
+}
+
+// NewState creates a State, that is not backed by a bus, with the signals represented by the keys set. It is
+// meant to describe the expected state of a bus, passed to Diff.
+func NewState(keys ...string) State {
+	e := &expectedState{signals: make(map[string]bool), counts: make(map[string]int)}
+	for _, key := range keys {
+		e.signals[key] = true
+	}
+
+	return State{expected: e}
+}
+
+// WithCount returns a copy of a State created with NewState, that additionally expects the signal represented
+// by key to have been set n times. Diff compares the counts only of those keys, whose count was set this way.
+// Called on a State backed by a bus, it returns the State unchanged.
+func (s State) WithCount(key string, n int) State {
+	if s.bus != nil {
+		return s
+	}
+
+	e := &expectedState{signals: make(map[string]bool), counts: make(map[string]int)}
+	if s.expected != nil {
+		for k := range s.expected.signals {
+			e.signals[k] = true
+		}
+
+		for k, c := range s.expected.counts {
+			e.counts[k] = c
+		}
+	}
+
+	e.counts[key] = n
+	return State{expected: e}
+}
+
+func (e *expectedState) isSet(key string) bool {
+	return e != nil && e.signals[key]
+}
+
+func (e *expectedState) list() []string {
+	if e == nil {
+		return nil
+	}
+
+	var keys []string
+	for key := range e.signals {
+		keys = append(keys, key)
+	}
+
+	sort.Strings(keys)
+	return keys
+}
+
+func (e *expectedState) count(key string) int {
+	if e == nil {
+		return 0
+	}
+
+	return e.counts[key]
+}
+
+// diffStates compares the actual state to the expected one. It expects the expected state to be created with
+// NewState.
+func diffStates(expected, actual State) string {
+	var missing, unexpected []string
+	for _, key := range expected.Signals() {
+		if !actual.IsSet(key) {
+			missing = append(missing, key)
+		}
+	}
+
+	for _, key := range actual.Signals() {
+		if !expected.IsSet(key) {
+			unexpected = append(unexpected, key)
+		}
+	}
+
+	var counted []string
+	if expected.expected != nil {
+		for key := range expected.expected.counts {
+			counted = append(counted, key)
+		}
+	}
+
+	sort.Strings(counted)
+
+	var buf bytes.Buffer
+	if len(missing) > 0 {
+		fmt.Fprintf(&buf, "missing: %s\n", strings.Join(missing, ", "))
+	}
+
+	if len(unexpected) > 0 {
+		fmt.Fprintf(&buf, "unexpected: %s\n", strings.Join(unexpected, ", "))
+	}
+
+	for _, key := range counted {
+		if e, a := expected.Count(key), actual.Count(key); e != a {
+			fmt.Fprintf(&buf, "count of %s: expected %d, got %d\n", key, e, a)
+		}
+	}
+
+	return buf.String()
+}
+
+// Diff compares the current state of the bus to the expected state, created with NewState, and returns a
+// readable description of the differences: the missing and the unexpected signals, and the mismatching counts
+// set with WithCount. When the states match, it returns an empty string. It is meant for the assertions, e.g.:
+//
+//	if d := bus.Diff(NewState("a", "b").WithCount("a", 2)); d != "" {
+//		t.Error(d)
+//	}
+//
+// A State passed to the predicates of WaitFor is treated as an empty expected state. After the bus was closed,
+// it compares the expected state to an empty one.
+//
+// If the receiver *SyncBus is nil, it compares the expected state to an empty one.
+func (b *SyncBus) Diff(expected State) string {
+	if expected.bus != nil {
+		expected = NewState()
+	}
+
+	if b == nil || b.closed() {
+		return diffStates(expected, NewState())
+	}
+
+	d := diffItem{expected: expected, result: make(chan string, 1)}
+	select {
+	case b.diff <- d:
+		return <-d.result
+	case <-b.done:
+		return diffStates(expected, NewState())
+	}
+}
@@ -0,0 +1,55 @@
+package syncbus
+
+import (
+	"testing"
+	"time"
+)
+
+func TestNilDiff(t *testing.T) {
+	var bus *SyncBus
+	if d := bus.Diff(NewState("foo")); d != "missing: foo\n" {
+		t.Error("invalid diff", d)
+	}
+}
+
+func TestNewState(t *testing.T) {
+	s := NewState("foo", "bar")
+	c := s.WithCount("foo", 2)
+	if !s.IsSet("foo") || s.IsSet("baz") || s.Count("foo") != 0 || c.Count("foo") != 2 || !c.IsSet("bar") {
+		t.Error("invalid state")
+	}
+
+	if k := c.Signals(); len(k) != 2 || k[0] != "bar" || k[1] != "foo" {
+		t.Error("invalid signals", k)
+	}
+
+	if s.Stats() != (Stats{}) {
+		t.Error("unexpected stats")
+	}
+}
+
+func TestDiff(t *testing.T) {
+	bus := New(3 * time.Millisecond)
+	defer bus.Close()
+
+	bus.Signal("foo")
+	bus.Signal("foo", "bar")
+	if d := bus.Diff(NewState("bar", "foo").WithCount("foo", 2)); d != "" {
+		t.Error("unexpected diff", d)
+	}
+
+	d := bus.Diff(NewState("foo", "baz").WithCount("foo", 3).WithCount("bar", 1))
+	expected := "missing: baz\nunexpected: bar\ncount of foo: expected 3, got 2\n"
+	if d != expected {
+		t.Errorf("invalid diff, expected:\n%s\ngot:\n%s", expected, d)
+	}
+}
+
+func TestDiffClosed(t *testing.T) {
+	bus := New(3 * time.Millisecond)
+	bus.Signal("foo")
+	bus.Close()
+	if d := bus.Diff(NewState()); d != "" {
+		t.Error("unexpected diff", d)
+	}
+}
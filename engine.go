@@ -0,0 +1,236 @@
+package syncbus
+
+import (
+	"sync"
+	"time"
+)
+
+// Engine selects the implementation of the bus created by NewBus.
+type Engine int
+
+const (
+	// LoopEngine is the default engine, implemented by SyncBus. It serializes the operations in a run loop
+	// executed by a dedicated goroutine, and supports all the features of the package, like the introspection
+	// and the randomized scheduling.
+	LoopEngine Engine = iota
+
+	// MutexEngine is implemented by MutexBus. It protects the signals with a mutex, and releases the waits
+	// directly from Signal. It doesn't start any goroutines, and it provides higher throughput, but supports
+	// only the methods of the Bus interface.
+	MutexEngine
+)
+
+// WithEngine selects the engine of the bus created by NewBus. New ignores it, and always uses LoopEngine.
+func WithEngine(e Engine) Option {
+	return func(o *options) { o.engine = e }
+}
+
+// MutexBus is a minimal implementation of the Bus interface, based on a mutex. It can be created with NewBus
+// and WithEngine(MutexEngine).
+type MutexBus struct {
+	timeout time.Duration
+	scale   float64
+	mx      sync.Mutex
+	signals map[string]bool
+	waiting []*mutexWait
+	quit    chan struct{}
+	closed  bool
+}
+
+// mutexWait is a pending wait of a MutexBus. Its channel is closed by Signal, while holding the lock, once all
+// its keys are set, so that a ResetSignals following the Signal can't take back the wakeup.
+type mutexWait struct {
+	keys     []string
+	released chan struct{}
+}
+
+// NewBus creates a bus, implemented by the engine selected with WithEngine. With LoopEngine, the default, it
+// returns a *SyncBus, and with MutexEngine, a *MutexBus. MutexBus considers only the timeout, WithTimeout and
+// WithRaceScale, ignoring the other options. Its timeout is scaled the same way as the one of SyncBus, see
+// WithRaceScale and SYNCBUS_TIMEOUT_SCALE.
+func NewBus(timeout time.Duration, opts ...Option) Bus {
+	var o options
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	if o.engine != MutexEngine {
+		return New(timeout, opts...)
+	}
+
+	if o.timeout > 0 {
+		timeout = o.timeout
+	}
+
+	scale := timeoutScale(o)
+	if scale != 1 {
+		timeout = time.Duration(float64(timeout) * scale)
+	}
+
+	return &MutexBus{timeout: timeout, scale: scale, signals: make(map[string]bool), quit: make(chan struct{})}
+}
+
+// TimeoutScale returns the multiplier applied to the timeout of the bus, see WithRaceScale and
+// SYNCBUS_TIMEOUT_SCALE.
+//
+// If the receiver *MutexBus is nil, it returns 1.
+func (b *MutexBus) TimeoutScale() float64 {
+	if b == nil {
+		return 1
+	}
+
+	return b.scale
+}
+
+func (b *MutexBus) isSet(keys []string) bool {
+	for _, key := range keys {
+		if !b.signals[key] {
+			return false
+		}
+	}
+
+	return true
+}
+
+// finish removes a pending wait, and tells whether it was released in the meantime.
+func (b *MutexBus) finish(w *mutexWait) bool {
+	b.mx.Lock()
+	defer b.mx.Unlock()
+	select {
+	case <-w.released:
+		return true
+	default:
+	}
+
+	for i, wi := range b.waiting {
+		if wi == w {
+			b.waiting = append(b.waiting[:i], b.waiting[i+1:]...)
+			break
+		}
+	}
+
+	return false
+}
+
+// Wait blocks until all the signals represented by the keys are set, or returns an ErrTimeout if the timeout
+// expires. It returns ErrClosed if the bus was closed before or during the call.
+//
+// If the receiver *MutexBus is nil, or no key argument is passed to it, it is a noop.
+func (b *MutexBus) Wait(keys ...string) error {
+	if b == nil || len(keys) == 0 {
+		return nil
+	}
+
+	b.mx.Lock()
+	if b.closed {
+		b.mx.Unlock()
+		return ErrClosed
+	}
+
+	if b.isSet(keys) {
+		b.mx.Unlock()
+		return nil
+	}
+
+	w := &mutexWait{keys: keys, released: make(chan struct{})}
+	b.waiting = append(b.waiting, w)
+	b.mx.Unlock()
+
+	timer := time.NewTimer(b.timeout)
+	defer timer.Stop()
+	select {
+	case <-w.released:
+		return nil
+	case <-timer.C:
+		if b.finish(w) {
+			return nil
+		}
+
+		return ErrTimeout
+	case <-b.quit:
+		if b.finish(w) {
+			return nil
+		}
+
+		return ErrClosed
+	}
+}
+
+// Signal sets the signals represented by the keys, and releases the waits that they satisfy.
+//
+// If the receiver *MutexBus is nil, it is a noop.
+func (b *MutexBus) Signal(keys ...string) {
+	if b == nil || len(keys) == 0 {
+		return
+	}
+
+	b.mx.Lock()
+	defer b.mx.Unlock()
+	if b.closed {
+		return
+	}
+
+	for _, key := range keys {
+		b.signals[key] = true
+	}
+
+	keep := b.waiting[:0]
+	for _, w := range b.waiting {
+		if b.isSet(w.keys) {
+			close(w.released)
+			continue
+		}
+
+		keep = append(keep, w)
+	}
+
+	for i := len(keep); i < len(b.waiting); i++ {
+		b.waiting[i] = nil
+	}
+
+	b.waiting = keep
+}
+
+// ResetSignals clears the set signals represented by the keys.
+//
+// If the receiver *MutexBus is nil, it is a noop.
+func (b *MutexBus) ResetSignals(keys ...string) {
+	if b == nil {
+		return
+	}
+
+	b.mx.Lock()
+	defer b.mx.Unlock()
+	for _, key := range keys {
+		delete(b.signals, key)
+	}
+}
+
+// Reset clears all the set signals.
+//
+// If the receiver *MutexBus is nil, it is a noop.
+func (b *MutexBus) Reset() {
+	if b == nil {
+		return
+	}
+
+	b.mx.Lock()
+	defer b.mx.Unlock()
+	b.signals = make(map[string]bool)
+}
+
+// Close tears down the bus. The pending waits return ErrClosed. Calling Close multiple times is safe.
+//
+// If the receiver *MutexBus is nil, it is a noop.
+func (b *MutexBus) Close() {
+	if b == nil {
+		return
+	}
+
+	b.mx.Lock()
+	defer b.mx.Unlock()
+	if !b.closed {
+		b.closed = true
+		close(b.quit)
+	}
+}
@@ -0,0 +1,188 @@
+package syncbus
+
+import (
+	"testing"
+	"time"
+)
+
+var engines = []struct {
+	name   string
+	engine Engine
+}{
+	{"loop", LoopEngine},
+	{"mutex", MutexEngine},
+}
+
+// conformance contains the tests that every engine must pass. When set, setup is called before creating the
+// bus.
+var conformance = []struct {
+	name  string
+	setup func(t *testing.T)
+	test  func(t *testing.T, b Bus)
+}{{
+	"already set", nil, func(t *testing.T, b Bus) {
+		b.Signal("foo", "bar")
+		if err := b.Wait("foo", "bar"); err != nil {
+			t.Error(err)
+		}
+	},
+}, {
+	"signaled later", nil, func(t *testing.T, b Bus) {
+		errs := make(chan error, 1)
+		go func() { errs <- b.Wait("foo", "bar") }()
+		b.Signal("foo")
+		time.Sleep(time.Millisecond)
+		b.Signal("bar")
+		if err := <-errs; err != nil {
+			t.Error(err)
+		}
+	},
+}, {
+	"signaled then reset", nil, func(t *testing.T, b Bus) {
+		errs := make(chan error, 1)
+		go func() { errs <- b.Wait("foo") }()
+		time.Sleep(3 * time.Millisecond)
+		b.Signal("foo")
+		b.ResetSignals("foo")
+		if err := <-errs; err != nil {
+			t.Error("lost the wakeup", err)
+		}
+	},
+}, {
+	"timeout", nil, func(t *testing.T, b Bus) {
+		b.Signal("foo")
+		if err := b.Wait("foo", "bar"); err != ErrTimeout {
+			t.Error("failed to timeout", err)
+		}
+	},
+}, {
+	"reset signals", nil, func(t *testing.T, b Bus) {
+		b.Signal("foo", "bar")
+		b.ResetSignals("foo")
+		if err := b.Wait("bar"); err != nil {
+			t.Error(err)
+		}
+
+		if err := b.Wait("foo"); err != ErrTimeout {
+			t.Error("failed to reset", err)
+		}
+	},
+}, {
+	"reset", nil, func(t *testing.T, b Bus) {
+		b.Signal("foo", "bar")
+		b.Reset()
+		if err := b.Wait("bar"); err != ErrTimeout {
+			t.Error("failed to reset", err)
+		}
+	},
+}, {
+	"no keys", nil, func(t *testing.T, b Bus) {
+		if err := b.Wait(); err != nil {
+			t.Error(err)
+		}
+	},
+}, {
+	"close", nil, func(t *testing.T, b Bus) {
+		errs := make(chan error, 1)
+		go func() { errs <- b.Wait("foo") }()
+		time.Sleep(3 * time.Millisecond)
+		b.Close()
+		if err := <-errs; err != ErrClosed {
+			t.Error("failed to close", err)
+		}
+
+		if err := b.Wait("foo"); err != ErrClosed {
+			t.Error("failed to close", err)
+		}
+
+		b.Signal("foo")
+		b.Close()
+	},
+}, {
+	"happens before", nil, func(t *testing.T, b Bus) {
+		var v int
+		go func() {
+			v = 42
+			b.Signal("foo")
+		}()
+
+		if err := b.Wait("foo"); err != nil {
+			t.Fatal(err)
+		}
+
+		if v != 42 {
+			t.Error("invalid value", v)
+		}
+	},
+}, {
+	"timeout scale", func(t *testing.T) {
+		t.Setenv(TimeoutScaleEnv, "2")
+	}, func(t *testing.T, b Bus) {
+		start := time.Now()
+		if err := b.Wait("foo"); err != ErrTimeout {
+			t.Fatal("failed to timeout", err)
+		}
+
+		if d := time.Since(start); d < 24*time.Millisecond {
+			t.Error("timeout not scaled", d)
+		}
+	},
+}}
+
+func TestEngineConformance(t *testing.T) {
+	for _, e := range engines {
+		t.Run(e.name, func(t *testing.T) {
+			for _, c := range conformance {
+				t.Run(c.name, func(t *testing.T) {
+					if c.setup != nil {
+						c.setup(t)
+					}
+
+					b := NewBus(12*time.Millisecond, WithEngine(e.engine))
+					defer b.Close()
+					c.test(t, b)
+				})
+			}
+		})
+	}
+}
+
+func TestNewBusEngine(t *testing.T) {
+	if _, ok := NewBus(time.Second).(*SyncBus); !ok {
+		t.Error("invalid default engine")
+	}
+
+	b := NewBus(time.Second, WithEngine(MutexEngine), WithTimeout(time.Millisecond))
+	if mb, ok := b.(*MutexBus); !ok || mb.timeout != time.Millisecond {
+		t.Error("invalid mutex engine")
+	}
+}
+
+func TestNilMutexBus(t *testing.T) {
+	var b *MutexBus
+	b.Signal("foo")
+	b.ResetSignals("foo")
+	b.Reset()
+	b.Close()
+	if err := b.Wait("foo"); err != nil {
+		t.Error(err)
+	}
+
+	if s := b.TimeoutScale(); s != 1 {
+		t.Error("unexpected scale", s)
+	}
+}
+
+func BenchmarkEngines(b *testing.B) {
+	for _, e := range engines {
+		b.Run(e.name, func(b *testing.B) {
+			bus := NewBus(time.Second, WithEngine(e.engine))
+			defer bus.Close()
+			for i := 0; i < b.N; i++ {
+				bus.Signal("foo")
+				bus.Wait("foo")
+				bus.ResetSignals("foo")
+			}
+		})
+	}
+}
@@ -0,0 +1,141 @@
+package syncbus
+
+import (
+	"sync/atomic"
+	"time"
+)
+
+// EventType tells what kind of activity an Event represents.
+type EventType int
+
+const (
+	// EventWait is sent when a Wait call gets registered.
+	EventWait EventType = iota
+
+	// EventRelease is sent when a waiting goroutine gets released, because all the signals that it was
+	// waiting for were set.
+	EventRelease
+
+	// EventTimeout is sent when a Wait call times out.
+	EventTimeout
+
+	// EventSignal is sent when one or more signals are set.
+	EventSignal
+
+	// EventReset is sent when one or more signals are cleared.
+	EventReset
+
+	// EventResetAll is sent when all the signals are cleared.
+	EventResetAll
+
+	// EventLate is sent after EventRelease, when the wait was released within the grace period set by
+	// WithGracePeriod, after its nominal deadline.
+	EventLate
+)
+
+// DropPolicy tells which events to drop when the buffer of the event stream is full.
+type DropPolicy int
+
+const (
+	// DropNewest drops the incoming events when the buffer is full.
+	DropNewest DropPolicy = iota
+
+	// DropOldest drops the oldest events in the buffer in favor of the incoming ones.
+	DropOldest
+)
+
+// DefaultEventBuffer is the default size of the event stream buffer.
+const DefaultEventBuffer = 1024
+
+// Event represents an activity on the bus.
+type Event struct {
+
+	// Type tells what kind of activity happened.
+	Type EventType
+
+	// Keys contains the keys of the signals involved in the activity. In case of EventWait, EventRelease
+	// and EventTimeout, these are all the keys that the waiting goroutine depends on.
+	Keys []string
+
+	// Time tells when the activity happened.
+	Time time.Time
+
+	// Meta contains the metadata of the signal, in case of EventSignal, when it was set with SignalMeta.
+	Meta map[string]string
+
+	// Labels contains the labels of the wait set with WithLabel, in case of EventWait, EventRelease,
+	// EventTimeout and EventLate.
+	Labels map[string]string
+}
+
+func (t EventType) String() string {
+	switch t {
+	case EventWait:
+		return "wait"
+	case EventRelease:
+		return "release"
+	case EventTimeout:
+		return "timeout"
+	case EventSignal:
+		return "signal"
+	case EventReset:
+		return "reset"
+	case EventResetAll:
+		return "resetall"
+	case EventLate:
+		return "late"
+	default:
+		return "unknown"
+	}
+}
+
+func (b *SyncBus) emit(e Event) {
+	b.record(e)
+	b.recordRecent(e)
+	select {
+	case b.events <- e:
+		return
+	default:
+	}
+
+	if b.options.dropPolicy == DropOldest && cap(b.events) > 0 {
+		select {
+		case <-b.events:
+		default:
+		}
+
+		select {
+		case b.events <- e:
+		default:
+		}
+	}
+
+	atomic.AddUint64(&b.dropped, 1)
+}
+
+// Events returns a channel delivering a stream of all the activity on the bus. The same channel is returned on
+// every call, so there can be practically only one observer. The bus never blocks on the observer: when the
+// buffer of the channel is full, events are dropped according to the drop policy (see WithEventBuffer and
+// WithDropPolicy). The channel is closed when the bus is closed.
+//
+// If the receiver *SyncBus is nil, it returns a closed channel.
+func (b *SyncBus) Events() <-chan Event {
+	if b == nil {
+		c := make(chan Event)
+		close(c)
+		return c
+	}
+
+	return b.events
+}
+
+// DroppedEvents returns how many events were dropped, because the buffer of the event stream was full.
+//
+// If the receiver *SyncBus is nil, it returns 0.
+func (b *SyncBus) DroppedEvents() uint64 {
+	if b == nil {
+		return 0
+	}
+
+	return atomic.LoadUint64(&b.dropped)
+}
@@ -0,0 +1,118 @@
+package syncbus
+
+import (
+	"testing"
+	"time"
+)
+
+func receiveEvent(t *testing.T, events <-chan Event) Event {
+	select {
+	case e := <-events:
+		return e
+	case <-time.After(testWaitTimeout):
+		t.Fatal("failed to receive event")
+		return Event{}
+	}
+}
+
+func TestNilEvents(t *testing.T) {
+	var bus *SyncBus
+	if _, ok := <-bus.Events(); ok {
+		t.Error("unexpected event")
+	}
+
+	if bus.DroppedEvents() != 0 {
+		t.Error("unexpected dropped events")
+	}
+}
+
+func TestEvents(t *testing.T) {
+	bus := New(12 * time.Millisecond)
+	defer bus.Close()
+
+	events := bus.Events()
+
+	bus.Signal("foo")
+	if e := receiveEvent(t, events); e.Type != EventSignal || len(e.Keys) != 1 || e.Keys[0] != "foo" {
+		t.Error("invalid event", e)
+	}
+
+	if err := bus.Wait("foo"); err != nil {
+		t.Error(err)
+	}
+
+	if e := receiveEvent(t, events); e.Type != EventWait {
+		t.Error("invalid event", e)
+	}
+
+	if e := receiveEvent(t, events); e.Type != EventRelease {
+		t.Error("invalid event", e)
+	}
+
+	bus.ResetSignals("foo")
+	if e := receiveEvent(t, events); e.Type != EventReset {
+		t.Error("invalid event", e)
+	}
+
+	bus.Reset()
+	if e := receiveEvent(t, events); e.Type != EventResetAll {
+		t.Error("invalid event", e)
+	}
+
+	if err := bus.Wait("bar"); err != ErrTimeout {
+		t.Error("failed to timeout")
+	}
+
+	receiveEvent(t, events)
+	if e := receiveEvent(t, events); e.Type != EventTimeout || e.Keys[0] != "bar" {
+		t.Error("invalid event", e)
+	}
+}
+
+func TestEventsClosed(t *testing.T) {
+	bus := New(12 * time.Millisecond)
+	events := bus.Events()
+	bus.Close()
+	for range events {
+	}
+}
+
+func TestEventsDropNewest(t *testing.T) {
+	bus := New(12*time.Millisecond, WithEventBuffer(1))
+	defer bus.Close()
+
+	bus.Signal("foo")
+	bus.Signal("bar")
+	bus.Signal("baz")
+	if err := bus.Wait("foo"); err != nil {
+		t.Error(err)
+	}
+
+	if e := receiveEvent(t, bus.Events()); e.Keys[0] != "foo" {
+		t.Error("invalid event", e)
+	}
+
+	if bus.DroppedEvents() != 4 {
+		t.Error("invalid dropped events count", bus.DroppedEvents())
+	}
+}
+
+func TestEventsDropOldest(t *testing.T) {
+	bus := New(12*time.Millisecond, WithEventBuffer(1), WithDropPolicy(DropOldest))
+	defer bus.Close()
+
+	bus.Signal("foo")
+	bus.Signal("bar")
+	bus.Signal("baz")
+	if err := bus.Wait("foo"); err != nil {
+		t.Error(err)
+	}
+
+	if e := receiveEvent(t, bus.Events()); e.Type != EventRelease {
+		t.Error("invalid event", e)
+	}
+
+	if bus.DroppedEvents() != 4 {
+		t.Error("invalid dropped events count", bus.DroppedEvents())
+	}
+}
@@ -0,0 +1,97 @@
+package syncbus
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"io"
+	"strings"
+	"time"
+)
+
+type eventJSON struct {
+	Type   string            `json:"type"`
+	Keys   []string          `json:"keys,omitempty"`
+	Time   time.Time         `json:"time"`
+	Meta   map[string]string `json:"meta,omitempty"`
+	Labels map[string]string `json:"labels,omitempty"`
+	Origin string            `json:"origin,omitempty"`
+}
+
+func newEventJSON(e Event) eventJSON {
+	return eventJSON{
+		Type:   e.Type.String(),
+		Keys:   e.Keys,
+		Time:   e.Time,
+		Meta:   e.Meta,
+		Labels: e.Labels,
+	}
+}
+
+func (b *SyncBus) exportHistory() ([]Event, error) {
+	if b.options.historySize <= 0 {
+		return nil, ErrNoHistory
+	}
+
+	return b.History(), nil
+}
+
+// WriteCSV writes the recorded history to w in CSV format, with a header row, and the columns time, type,
+// keys, meta and labels. The time is formatted as RFC3339 with nanoseconds, the keys are separated by commas,
+// and the metadata and the labels are formatted as sorted name=value pairs. It allows archiving the
+// synchronization trace of the failed tests as CI artifacts. It requires the bus to be created with
+// WithHistory, otherwise it returns ErrNoHistory.
+//
+// If the receiver *SyncBus is nil, it is a noop.
+func (b *SyncBus) WriteCSV(w io.Writer) error {
+	if b == nil {
+		return nil
+	}
+
+	h, err := b.exportHistory()
+	if err != nil {
+		return err
+	}
+
+	cw := csv.NewWriter(w)
+	if err := cw.Write([]string{"time", "type", "keys", "meta", "labels"}); err != nil {
+		return err
+	}
+
+	for _, e := range h {
+		if err := cw.Write([]string{
+			e.Time.Format(time.RFC3339Nano),
+			e.Type.String(),
+			strings.Join(e.Keys, ","),
+			formatLabels(e.Meta),
+			formatLabels(e.Labels),
+		}); err != nil {
+			return err
+		}
+	}
+
+	cw.Flush()
+	return cw.Error()
+}
+
+// WriteJSON writes the recorded history to w as a JSON array of objects, with the fields type, keys, time,
+// meta and labels. It allows analyzing the synchronization trace with external tools. It requires the bus to
+// be created with WithHistory, otherwise it returns ErrNoHistory.
+//
+// If the receiver *SyncBus is nil, it is a noop.
+func (b *SyncBus) WriteJSON(w io.Writer) error {
+	if b == nil {
+		return nil
+	}
+
+	h, err := b.exportHistory()
+	if err != nil {
+		return err
+	}
+
+	j := make([]eventJSON, 0, len(h))
+	for _, e := range h {
+		j = append(j, newEventJSON(e))
+	}
+
+	return json.NewEncoder(w).Encode(j)
+}
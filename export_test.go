@@ -0,0 +1,114 @@
+package syncbus
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+func TestNilExport(t *testing.T) {
+	var (
+		bus *SyncBus
+		buf bytes.Buffer
+	)
+
+	if err := bus.WriteCSV(&buf); err != nil {
+		t.Error(err)
+	}
+
+	if err := bus.WriteJSON(&buf); err != nil {
+		t.Error(err)
+	}
+
+	if buf.Len() != 0 {
+		t.Error("unexpected output", buf.String())
+	}
+}
+
+func TestExportNoHistory(t *testing.T) {
+	bus := New(120 * time.Millisecond)
+	defer bus.Close()
+
+	var buf bytes.Buffer
+	if err := bus.WriteCSV(&buf); err != ErrNoHistory {
+		t.Error("failed to fail", err)
+	}
+
+	if err := bus.WriteJSON(&buf); err != ErrNoHistory {
+		t.Error("failed to fail", err)
+	}
+}
+
+func exportTestBus(t *testing.T) *SyncBus {
+	bus := New(120*time.Millisecond, WithHistory())
+	bus.SignalMeta(map[string]string{"origin": "test"}, "foo", "bar")
+	if err := bus.WaitWith([]WaitOpt{WithLabel("component", "ingress")}, "foo"); err != nil {
+		t.Fatal(err)
+	}
+
+	return bus
+}
+
+func TestWriteCSV(t *testing.T) {
+	bus := exportTestBus(t)
+	defer bus.Close()
+
+	var buf bytes.Buffer
+	if err := bus.WriteCSV(&buf); err != nil {
+		t.Fatal(err)
+	}
+
+	records, err := csv.NewReader(&buf).ReadAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(records) != 4 {
+		t.Fatal("invalid number of records", records)
+	}
+
+	if records[0][0] != "time" || records[0][4] != "labels" {
+		t.Error("invalid header", records[0])
+	}
+
+	if records[1][1] != "signal" || records[1][2] != "foo,bar" || records[1][3] != "origin=test" {
+		t.Error("invalid signal record", records[1])
+	}
+
+	if _, err := time.Parse(time.RFC3339Nano, records[1][0]); err != nil {
+		t.Error(err)
+	}
+
+	if records[3][1] != "release" || records[3][4] != "component=ingress" {
+		t.Error("invalid release record", records[3])
+	}
+}
+
+func TestWriteJSON(t *testing.T) {
+	bus := exportTestBus(t)
+	defer bus.Close()
+
+	var buf bytes.Buffer
+	if err := bus.WriteJSON(&buf); err != nil {
+		t.Fatal(err)
+	}
+
+	var events []eventJSON
+	if err := json.Unmarshal(buf.Bytes(), &events); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(events) != 3 {
+		t.Fatal("invalid number of events", events)
+	}
+
+	if events[0].Type != "signal" || len(events[0].Keys) != 2 || events[0].Meta["origin"] != "test" {
+		t.Error("invalid signal event", events[0])
+	}
+
+	if events[1].Type != "wait" || events[1].Labels["component"] != "ingress" {
+		t.Error("invalid wait event", events[1])
+	}
+}
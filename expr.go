@@ -0,0 +1,222 @@
+package syncbus
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+type exprKind int
+
+const (
+	exprKey exprKind = iota
+	exprNot
+	exprAnd
+	exprOr
+)
+
+type expr struct {
+	kind    exprKind
+	key     string
+	operand []*expr
+}
+
+type exprParser struct {
+	input string
+	pos   int
+}
+
+// ExprError is returned by WaitExpr when the expression is invalid.
+type ExprError struct {
+
+	// Expr is the invalid expression.
+	Expr string
+
+	// Pos is the byte offset in the expression where the error was detected.
+	Pos int
+
+	// Message describes the error.
+	Message string
+}
+
+// ErrExpr is the error that ExprError unwraps to.
+var ErrExpr = errors.New("invalid wait expression")
+
+func (err *ExprError) Error() string {
+	return fmt.Sprintf("%v: %s at %d: %q", ErrExpr, err.Message, err.Pos, err.Expr)
+}
+
+// Unwrap returns ErrExpr.
+func (err *ExprError) Unwrap() error {
+	return ErrExpr
+}
+
+func (p *exprParser) fail(message string) error {
+	return &ExprError{Expr: p.input, Pos: p.pos, Message: message}
+}
+
+func (p *exprParser) skipSpace() {
+	for p.pos < len(p.input) && unicode.IsSpace(rune(p.input[p.pos])) {
+		p.pos++
+	}
+}
+
+func (p *exprParser) accept(token string) bool {
+	p.skipSpace()
+	if strings.HasPrefix(p.input[p.pos:], token) {
+		p.pos += len(token)
+		return true
+	}
+
+	return false
+}
+
+func isKeyChar(c byte) bool {
+	return !unicode.IsSpace(rune(c)) && !strings.ContainsRune("&|!()", rune(c))
+}
+
+func (p *exprParser) parseKey() (*expr, error) {
+	p.skipSpace()
+	start := p.pos
+	for p.pos < len(p.input) && isKeyChar(p.input[p.pos]) {
+		p.pos++
+	}
+
+	if p.pos == start {
+		return nil, p.fail("expected key")
+	}
+
+	return &expr{kind: exprKey, key: p.input[start:p.pos]}, nil
+}
+
+func (p *exprParser) parseUnary() (*expr, error) {
+	if p.accept("!") {
+		e, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+
+		return &expr{kind: exprNot, operand: []*expr{e}}, nil
+	}
+
+	if p.accept("(") {
+		e, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+
+		if !p.accept(")") {
+			return nil, p.fail("expected )")
+		}
+
+		return e, nil
+	}
+
+	return p.parseKey()
+}
+
+func (p *exprParser) parseBinary(kind exprKind, op string, next func() (*expr, error)) (*expr, error) {
+	e, err := next()
+	if err != nil {
+		return nil, err
+	}
+
+	operand := []*expr{e}
+	for p.accept(op) {
+		e, err := next()
+		if err != nil {
+			return nil, err
+		}
+
+		operand = append(operand, e)
+	}
+
+	if len(operand) == 1 {
+		return operand[0], nil
+	}
+
+	return &expr{kind: kind, operand: operand}, nil
+}
+
+func (p *exprParser) parseAnd() (*expr, error) {
+	return p.parseBinary(exprAnd, "&&", p.parseUnary)
+}
+
+func (p *exprParser) parseOr() (*expr, error) {
+	return p.parseBinary(exprOr, "||", p.parseAnd)
+}
+
+func parseExpr(input string) (*expr, error) {
+	p := &exprParser{input: input}
+	e, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+
+	p.skipSpace()
+	if p.pos < len(p.input) {
+		return nil, p.fail("unexpected input")
+	}
+
+	return e, nil
+}
+
+func (e *expr) eval(isSet func(key string) bool) bool {
+	switch e.kind {
+	case exprNot:
+		return !e.operand[0].eval(isSet)
+	case exprAnd:
+		for _, o := range e.operand {
+			if !o.eval(isSet) {
+				return false
+			}
+		}
+
+		return true
+	case exprOr:
+		for _, o := range e.operand {
+			if o.eval(isSet) {
+				return true
+			}
+		}
+
+		return false
+	default:
+		return isSet(e.key)
+	}
+}
+
+func (e *expr) keys() []string {
+	if e.kind == exprKey {
+		return []string{e.key}
+	}
+
+	var keys []string
+	for _, o := range e.operand {
+		keys = append(keys, o.keys()...)
+	}
+
+	return keys
+}
+
+// WaitExpr blocks until the expression is satisfied, or returns an ErrTimeout if it doesn't happen within the
+// timeout of the bus. The expression consists of keys, combined with the operators && (and), || (or), !
+// (not) and parentheses, e.g. "db && (cacheA || cacheB) && !maintenance". The keys can contain any characters,
+// except for whitespace and the operators. The expression is evaluated by the run loop, whenever the signals
+// are set or reset. It is meant for the configuration driven test harnesses, where the conditions come from
+// data files, rather than from Go code. When the expression is invalid, it returns an ExprError.
+//
+// If the receiver *SyncBus is nil, it is a noop.
+func (b *SyncBus) WaitExpr(expression string) error {
+	if b == nil {
+		return nil
+	}
+
+	e, err := parseExpr(expression)
+	if err != nil {
+		return err
+	}
+
+	return b.waitItem(waitItem{keys: uniqueKeys(e.keys()), cond: e})
+}
@@ -0,0 +1,83 @@
+package syncbus
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestNilWaitExpr(t *testing.T) {
+	var bus *SyncBus
+	if err := bus.WaitExpr("foo && bar"); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestExprEval(t *testing.T) {
+	for _, test := range []struct {
+		expr     string
+		signals  []string
+		expected bool
+	}{
+		{"foo", []string{"foo"}, true},
+		{"foo", nil, false},
+		{"!foo", nil, true},
+		{"foo && bar", []string{"foo"}, false},
+		{"foo || bar", []string{"bar"}, true},
+		{"db && (cacheA || cacheB) && !maintenance", []string{"db", "cacheB"}, true},
+		{"db && (cacheA || cacheB) && !maintenance", []string{"db", "cacheB", "maintenance"}, false},
+		{"db&&cacheA||cacheB", []string{"cacheB"}, true},
+		{"!!foo.bar-1", []string{"foo.bar-1"}, true},
+	} {
+		t.Run(test.expr, func(t *testing.T) {
+			e, err := parseExpr(test.expr)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			signals := make(map[string]bool)
+			for _, key := range test.signals {
+				signals[key] = true
+			}
+
+			if e.eval(func(key string) bool { return signals[key] }) != test.expected {
+				t.Error("invalid result")
+			}
+		})
+	}
+}
+
+func TestExprError(t *testing.T) {
+	bus := New(120 * time.Millisecond)
+	defer bus.Close()
+
+	for _, expr := range []string{"", "foo &&", "(foo", "foo bar", "foo)", "&& foo"} {
+		err := bus.WaitExpr(expr)
+		var eerr *ExprError
+		if !errors.As(err, &eerr) || !errors.Is(err, ErrExpr) || eerr.Expr != expr {
+			t.Error("failed to fail", expr, err)
+		}
+	}
+}
+
+func TestWaitExpr(t *testing.T) {
+	bus := New(120 * time.Millisecond)
+	defer bus.Close()
+
+	bus.Signal("maintenance")
+	errs := make(chan error, 1)
+	go func() { errs <- bus.WaitExpr("db && (cacheA || cacheB) && !maintenance") }()
+	for bus.Stats().Waiting != 1 {
+		time.Sleep(time.Millisecond / 10)
+	}
+
+	bus.Signal("db", "cacheA")
+	if s := bus.Stats(); s.Waiting != 1 {
+		t.Fatal("unexpected release", s)
+	}
+
+	bus.ResetSignals("maintenance")
+	if err := <-errs; err != nil {
+		t.Error(err)
+	}
+}
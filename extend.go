@@ -0,0 +1,28 @@
+package syncbus
+
+import "time"
+
+// WithProgressExtension makes the deadline of a multi-key wait extended by d, every time one of its keys gets
+// set. This way, in a long pipeline, where each stage legitimately takes a while, the timeout of the bus can be
+// sized for a single stage, instead of the whole chain. Zero means no extension, which is the default.
+func WithProgressExtension(d time.Duration) Option {
+	return func(o *options) { o.progressExtension = d }
+}
+
+// extendWaiting extends the deadline of the pending waits depending on a key that was not set before.
+func (b *SyncBus) extendWaiting(key string) {
+	d := b.options.progressExtension
+	if d <= 0 || b.signals.has(key) {
+		return
+	}
+
+	for i := range b.waiting {
+		w := &b.waiting[i]
+		if len(w.keys) < 2 || !containsKey(w.keys, key) {
+			continue
+		}
+
+		w.deadline = w.deadline.Add(d)
+		w.nominal = w.nominal.Add(d)
+	}
+}
@@ -0,0 +1,53 @@
+package syncbus
+
+import (
+	"testing"
+	"time"
+)
+
+func TestProgressExtension(t *testing.T) {
+	bus := New(36*time.Millisecond, WithProgressExtension(36*time.Millisecond))
+	defer bus.Close()
+
+	go func() {
+		for _, key := range []string{"foo", "bar", "baz"} {
+			time.Sleep(24 * time.Millisecond)
+			bus.Signal(key)
+		}
+	}()
+
+	if err := bus.Wait("foo", "bar", "baz"); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestProgressExtensionSetOnce(t *testing.T) {
+	bus := New(24*time.Millisecond, WithProgressExtension(time.Hour))
+	defer bus.Close()
+
+	bus.Signal("foo")
+	go func() {
+		time.Sleep(6 * time.Millisecond)
+		bus.Signal("foo")
+	}()
+
+	if err := bus.Wait("foo", "bar"); err != ErrTimeout {
+		t.Error("unexpected extension", err)
+	}
+}
+
+func TestWithoutProgressExtension(t *testing.T) {
+	bus := New(36 * time.Millisecond)
+	defer bus.Close()
+
+	go func() {
+		for _, key := range []string{"foo", "bar", "baz"} {
+			time.Sleep(24 * time.Millisecond)
+			bus.Signal(key)
+		}
+	}()
+
+	if err := bus.Wait("foo", "bar", "baz"); err != ErrTimeout {
+		t.Error("failed to timeout", err)
+	}
+}
@@ -0,0 +1,45 @@
+package syncbus
+
+import "sync/atomic"
+
+// WithFastPath enables a fast path for Wait: when all the awaited signals are already set, Wait returns
+// immediately, without a round trip to the run loop, and without allocations, when the keys are passed as an
+// existing slice. The set signals are checked in a lock-free snapshot published by the run loop on every
+// change, which makes the signal operations more expensive, proportionally to the number of the set signals.
+// The waits returning on the fast path are counted by Stats, but they don't generate events. The fast path is
+// disabled when the bus operates in a randomized mode, e.g. with WithJitter or WithPCT, or when vet mode is
+// enabled with WithVet.
+func WithFastPath() Option {
+	return func(o *options) { o.fastPath = true }
+}
+
+// publishSignals publishes a copy of the set signals for the fast path.
+func (b *SyncBus) publishSignals() {
+	if !b.fastPath {
+		return
+	}
+
+	s := make(map[string]bool, b.signals.len())
+	for _, key := range b.signals.list() {
+		s[key] = true
+	}
+
+	b.fastView.Store(s)
+}
+
+// waitFast tells whether the wait can return on the fast path.
+func (b *SyncBus) waitFast(keys []string) bool {
+	if !b.fastPath || b.closed() {
+		return false
+	}
+
+	s, _ := b.fastView.Load().(map[string]bool)
+	for _, key := range keys {
+		if !s[key] {
+			return false
+		}
+	}
+
+	atomic.AddUint64(&b.fastHit, 1)
+	return true
+}
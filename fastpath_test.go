@@ -0,0 +1,64 @@
+package syncbus
+
+import (
+	"testing"
+	"time"
+)
+
+func TestFastPath(t *testing.T) {
+	bus := New(120*time.Millisecond, WithFastPath())
+	defer bus.Close()
+
+	bus.Signal("foo", "bar")
+	keys := []string{"foo", "bar"}
+	if n := testing.AllocsPerRun(100, func() {
+		if err := bus.Wait(keys...); err != nil {
+			t.Fatal(err)
+		}
+	}); n != 0 {
+		t.Error("unexpected allocations", n)
+	}
+
+	if s := bus.Stats(); s.Waits != 101 || s.Releases != 101 {
+		t.Error("failed to count the waits", s)
+	}
+
+	bus.ResetSignals("bar")
+	if err := bus.WaitTimeout(3*time.Millisecond, keys...); err != ErrTimeout {
+		t.Error("failed to timeout", err)
+	}
+
+	bus.Reset()
+	if err := bus.WaitTimeout(3*time.Millisecond, "foo"); err != ErrTimeout {
+		t.Error("failed to timeout", err)
+	}
+}
+
+func TestFastPathClosed(t *testing.T) {
+	bus := New(120*time.Millisecond, WithFastPath())
+	bus.Signal("foo")
+	bus.Close()
+	if err := bus.Wait("foo"); err != ErrClosed {
+		t.Error("failed to fail", err)
+	}
+}
+
+func TestFastPathDisabled(t *testing.T) {
+	bus := New(120*time.Millisecond, WithFastPath(), WithJitter(time.Millisecond))
+	defer bus.Close()
+	if bus.fastPath {
+		t.Error("failed to disable the fast path")
+	}
+}
+
+func BenchmarkWaitFastPath(b *testing.B) {
+	bus := New(120*time.Millisecond, WithFastPath())
+	defer bus.Close()
+
+	bus.Signal("foo")
+	keys := []string{"foo"}
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		bus.Wait(keys...)
+	}
+}
@@ -0,0 +1,61 @@
+package syncbus
+
+import (
+	"os"
+	"time"
+)
+
+// DefaultFilePollInterval is the default interval of checking the files watched by SignalOnFile.
+const DefaultFilePollInterval = 15 * time.Millisecond
+
+type fileState struct {
+	exists  bool
+	size    int64
+	modTime time.Time
+}
+
+func statFile(path string) fileState {
+	fi, err := os.Stat(path)
+	if err != nil {
+		return fileState{}
+	}
+
+	return fileState{exists: true, size: fi.Size(), modTime: fi.ModTime()}
+}
+
+// signalBackground sets the signals from background goroutines owned by the bus, without blocking when the bus
+// gets closed.
+func (b *SyncBus) signalBackground(keys ...string) {
+	b.sendSignal(signalItem{keys: keys, background: true})
+}
+
+// SignalOnFile sets the signal represented by key whenever the file at path appears, changes, or is removed,
+// compared to its state at the time of the call. The file is polled with the interval set by
+// WithFilePollInterval. Watching the file stops when the bus is closed.
+//
+// It allows integrating the marker files used by cross-process tests into the choreography of the bus.
+//
+// If the receiver *SyncBus is nil, it is a noop.
+func (b *SyncBus) SignalOnFile(key, path string) {
+	if b == nil {
+		return
+	}
+
+	last := statFile(path)
+	go func() {
+		t := time.NewTicker(b.options.filePollInterval)
+		defer t.Stop()
+		for {
+			select {
+			case <-t.C:
+				current := statFile(path)
+				if current != last {
+					last = current
+					b.signalBackground(key)
+				}
+			case <-b.done:
+				return
+			}
+		}
+	}()
+}
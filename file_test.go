@@ -0,0 +1,55 @@
+package syncbus
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestNilSignalOnFile(t *testing.T) {
+	var bus *SyncBus
+	bus.SignalOnFile("foo", "bar")
+}
+
+func TestSignalOnFile(t *testing.T) {
+	dir, err := ioutil.TempDir("", "syncbus-test")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer os.RemoveAll(dir)
+
+	bus := New(120*time.Millisecond, WithFilePollInterval(time.Millisecond))
+	defer bus.Close()
+
+	path := filepath.Join(dir, "marker")
+	bus.SignalOnFile("marker", path)
+
+	if err := ioutil.WriteFile(path, []byte("foo"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := bus.Wait("marker"); err != nil {
+		t.Fatal("failed to detect created file", err)
+	}
+
+	bus.ResetSignals("marker")
+	if err := ioutil.WriteFile(path, []byte("foobar"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := bus.Wait("marker"); err != nil {
+		t.Fatal("failed to detect changed file", err)
+	}
+
+	bus.ResetSignals("marker")
+	if err := os.Remove(path); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := bus.Wait("marker"); err != nil {
+		t.Fatal("failed to detect removed file", err)
+	}
+}
@@ -0,0 +1,100 @@
+package syncbus
+
+import (
+	"math/rand"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// Schedule is a generatable sequence of the scheduling choices of the bus, e.g. the durations of the jitter,
+// the wakeup order of the waits, or the steps of PCT scheduling. It implements the Generator interface of
+// testing/quick, and it provides shrinking with Shrink, so that property based testing frameworks can drive
+// the schedule of the bus, with WithGeneratedSchedule, like any other input of a property.
+//
+// Each choice is mapped to the range of the decision that consumes it. When the choices run out, the rest of
+// the decisions are made with zero choices, e.g. with no jitter, so the shorter schedules, and the schedules
+// with more zero choices, are simpler.
+type Schedule []uint64
+
+// WithGeneratedSchedule makes the bus take its random decisions from s, instead of its seed. Unless a wakeup
+// order is set with WithWakeupOrder, the order of releasing the simultaneously satisfied waits is also taken
+// from the schedule. The delays are taken from the schedule when the bus uses WithJitter, and the checkpoint
+// releases when it uses WithPCT.
+func WithGeneratedSchedule(s Schedule) Option {
+	return func(o *options) {
+		o.generated = true
+		if o.wakeupOrder.kind == fifoWakeup {
+			o.wakeupOrder = WakeupOrder{kind: shuffledWakeup}
+		}
+
+		o.choices = append(Schedule(nil), s...)
+	}
+}
+
+// Generate returns a random schedule, with at most size choices. It implements quick.Generator.
+func (Schedule) Generate(r *rand.Rand, size int) reflect.Value {
+	s := make(Schedule, r.Intn(size+1))
+	for i := range s {
+		s[i] = r.Uint64()
+	}
+
+	return reflect.ValueOf(s)
+}
+
+// Shrink returns simpler variants of the schedule, to be tried, in order, when a property failed with it.
+// The variants are its halves, the schedule with one of its choices removed, and the schedule with one of its
+// choices set to zero.
+func (s Schedule) Shrink() []Schedule {
+	var variants []Schedule
+	if len(s) > 1 {
+		variants = append(variants, append(Schedule(nil), s[:len(s)/2]...))
+		variants = append(variants, append(Schedule(nil), s[len(s)/2:]...))
+	}
+
+	for i := range s {
+		v := append(append(Schedule(nil), s[:i]...), s[i+1:]...)
+		variants = append(variants, v)
+	}
+
+	for i := range s {
+		if s[i] == 0 {
+			continue
+		}
+
+		v := append(Schedule(nil), s...)
+		v[i] = 0
+		variants = append(variants, v)
+	}
+
+	return variants
+}
+
+func (s Schedule) String() string {
+	c := make([]string, len(s))
+	for i, si := range s {
+		c[i] = strconv.FormatUint(si, 10)
+	}
+
+	return "[" + strings.Join(c, " ") + "]"
+}
+
+// choose returns the next decision from the generated schedule, when the random source uses one. Once the
+// schedule runs out, it returns the zero decision.
+func (r *random) choose(float bool, n int64) (decision, bool) {
+	if !r.generated {
+		return decision{}, false
+	}
+
+	var c uint64
+	if len(r.choices) > 0 {
+		c = r.choices[0]
+		r.choices = r.choices[1:]
+	}
+
+	if float {
+		return decision{float: true, floatValue: float64(c>>11) / (1 << 53)}, true
+	}
+
+	return decision{n: n, value: int64(c % uint64(n))}, true
+}
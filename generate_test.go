@@ -0,0 +1,107 @@
+package syncbus
+
+import (
+	"math/rand"
+	"testing"
+	"testing/quick"
+	"time"
+)
+
+func TestScheduleGenerate(t *testing.T) {
+	r := rand.New(rand.NewSource(42))
+	for i := 0; i < 12; i++ {
+		s := Schedule(nil).Generate(r, 5).Interface().(Schedule)
+		if len(s) > 5 {
+			t.Fatal("schedule too long", s)
+		}
+	}
+}
+
+func TestScheduleShrink(t *testing.T) {
+	if v := (Schedule{}).Shrink(); len(v) != 0 {
+		t.Error("unexpected variants", v)
+	}
+
+	v := Schedule{3, 0, 5}.Shrink()
+	if len(v) != 7 {
+		t.Fatal("unexpected variants", v)
+	}
+
+	if v[0].String() != "[3]" || v[1].String() != "[0 5]" || v[6].String() != "[3 0 0]" {
+		t.Error("unexpected variants", v)
+	}
+}
+
+func TestGeneratedScheduleExhausted(t *testing.T) {
+	r := newRandom(42, false, nil)
+	r.generated = true
+	r.choices = Schedule{7}
+	if v := r.int63n(5); v != 2 {
+		t.Error("unexpected value", v)
+	}
+
+	if v := r.int63n(5); v != 0 {
+		t.Error("unexpected value", v)
+	}
+
+	if v := r.float64(); v != 0 {
+		t.Error("unexpected value", v)
+	}
+}
+
+func releaseOrder(s Schedule) []string {
+	bus := New(120*time.Millisecond, WithGeneratedSchedule(s), WithHistory())
+	defer bus.Close()
+
+	keys := []string{"a", "b", "c", "d"}
+	done := make(chan struct{})
+	for i, key := range keys {
+		go func(key string) {
+			bus.Wait("start", key)
+			done <- struct{}{}
+		}(key)
+
+		for bus.Stats().Waiting <= i {
+			time.Sleep(time.Millisecond)
+		}
+	}
+
+	bus.Signal(keys...)
+	bus.Signal("start")
+	for range keys {
+		<-done
+	}
+
+	var order []string
+	h, _ := bus.Query(Filter{Types: []EventType{EventRelease}})
+	for _, e := range h {
+		order = append(order, e.Keys[1])
+	}
+
+	return order
+}
+
+func TestGeneratedScheduleProperty(t *testing.T) {
+	deterministic := func(s Schedule) bool {
+		o1, o2 := releaseOrder(s), releaseOrder(s)
+		if len(o1) != 4 || len(o2) != 4 {
+			return false
+		}
+
+		for i := range o1 {
+			if o1[i] != o2[i] {
+				return false
+			}
+		}
+
+		return true
+	}
+
+	if err := quick.Check(deterministic, &quick.Config{MaxCount: 12}); err != nil {
+		t.Error(err)
+	}
+
+	if o := releaseOrder(Schedule{3, 2, 1}); o[0] != "a" || o[3] != "d" {
+		t.Error("unexpected order", o)
+	}
+}
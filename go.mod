@@ -0,0 +1,3 @@
+module github.com/aryszka/syncbus
+
+go 1.20
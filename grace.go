@@ -0,0 +1,33 @@
+package syncbus
+
+import "time"
+
+// WithGracePeriod sets a grace window after the deadline of the waits, during which a late signal still
+// satisfies the wait, but the wait is recorded as late. The late waits are counted in the Report, and an
+// EventLate is emitted for them. It helps distinguishing the genuinely broken tests from the marginal timing
+// flakes. Zero means no grace period, which is the default.
+func WithGracePeriod(d time.Duration) Option {
+	return func(o *options) { o.gracePeriod = d }
+}
+
+// applyGrace extends the deadline of a new wait with the grace period, keeping the nominal deadline.
+func (b *SyncBus) applyGrace(w *waitItem) {
+	w.nominal = w.deadline
+	if b.options.gracePeriod > 0 {
+		w.deadline = w.deadline.Add(b.options.gracePeriod)
+	}
+}
+
+// checkLate records a successfully released wait as late, when it was released after its nominal deadline.
+func (b *SyncBus) checkLate(now time.Time, w waitItem) {
+	if b.options.gracePeriod <= 0 || !now.After(w.nominal) {
+		return
+	}
+
+	b.blocked.late++
+	if r, ok := b.blocked.waiters[waiterName(w)]; ok {
+		r.Late++
+	}
+
+	b.emit(Event{Type: EventLate, Keys: w.keys, Labels: w.labels, Time: now})
+}
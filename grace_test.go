@@ -0,0 +1,59 @@
+package syncbus
+
+import (
+	"testing"
+	"time"
+)
+
+func TestGracePeriod(t *testing.T) {
+	bus := New(12*time.Millisecond, WithGracePeriod(120*time.Millisecond))
+	defer bus.Close()
+
+	go func() {
+		time.Sleep(36 * time.Millisecond)
+		bus.Signal("foo")
+	}()
+
+	events := bus.Events()
+	if err := bus.Wait("foo"); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := bus.Wait("foo"); err != nil {
+		t.Fatal(err)
+	}
+
+	r := bus.Report()
+	if r.Late != 1 || len(r.Waiters) != 1 || r.Waiters[0].Late != 1 || r.Waiters[0].Waits != 2 {
+		t.Error("failed to record the late wait", r)
+	}
+
+	var late int
+	for len(events) > 0 {
+		if e := <-events; e.Type == EventLate {
+			late++
+		}
+	}
+
+	if late != 1 {
+		t.Error("failed to emit the late event", late)
+	}
+}
+
+func TestGracePeriodTimeout(t *testing.T) {
+	bus := New(12*time.Millisecond, WithGracePeriod(12*time.Millisecond))
+	defer bus.Close()
+
+	start := time.Now()
+	if err := bus.Wait("foo"); err != ErrTimeout {
+		t.Fatal("failed to timeout", err)
+	}
+
+	if time.Since(start) < 24*time.Millisecond {
+		t.Error("failed to apply the grace period")
+	}
+
+	if r := bus.Report(); r.Late != 0 {
+		t.Error("unexpected late wait", r)
+	}
+}
@@ -0,0 +1,330 @@
+package syncbus
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"sort"
+	"strings"
+	"time"
+)
+
+type resetInfo struct {
+	namespace string
+	all       bool
+	time      time.Time
+}
+
+// GraphWaiter describes a pending wait in a WaitGraph.
+type GraphWaiter struct {
+
+	// Keys contains all the keys that the wait depends on.
+	Keys []string
+
+	// Missing contains the keys that are not set.
+	Missing []string
+
+	// Namespace is the name of the namespace in which the wait was called, if any.
+	Namespace string
+
+	// Name is the name of the wait set by WithName, if any.
+	Name string
+
+	// Labels contains the labels of the wait set by WithLabel, if any.
+	Labels map[string]string
+
+	// Remaining is the time left until the deadline of the wait.
+	Remaining time.Duration
+}
+
+// GraphReset describes the last reset of a key in a WaitGraph.
+type GraphReset struct {
+
+	// Key is the key that was reset.
+	Key string
+
+	// Namespace is the name of the namespace through which the key was reset, if any.
+	Namespace string
+
+	// All tells whether the key was cleared by a Reset call, rather than by ResetSignals.
+	All bool
+
+	// Time is the time of the reset.
+	Time time.Time
+}
+
+// WaitGraph is a snapshot of the dependencies between the pending waits and the signals of a bus.
+type WaitGraph struct {
+
+	// Waiters contains the pending waits, including the ones that timed out when the graph was taken.
+	Waiters []GraphWaiter
+
+	// Signals contains the keys of the set signals.
+	Signals []string
+
+	// Resets contains the last reset of the keys that were reset at least once, ordered by the key. It is
+	// recorded only when the bus was created with WithWaitGraph or WithWaitGraphFile.
+	Resets []GraphReset
+}
+
+// TimeoutError is returned by Wait when the bus was created with WithWaitGraph, WithWaitGraphFile or
+// WithPartialResults, WithErrorContext or WithSourceAttribution, and the wait timed out.
+type TimeoutError struct {
+
+	// Keys contains the keys of the timed out wait.
+	Keys []string
+
+	// Arrived contains those keys of the wait, whose signals were set at the time of the timeout, in the order
+	// of the keys, with the time when they were set.
+	Arrived []Arrival
+
+	// Missing contains those keys of the wait, whose signals were not set at the time of the timeout.
+	Missing []string
+
+	// Sources tells for each missing key, whether it was never signaled, or it was reset after being set, when
+	// the bus was created with WithSourceAttribution.
+	Sources []KeySource
+
+	// Time tells when the wait timed out.
+	Time time.Time
+
+	// Recent contains the last events of the bus before the timeout, when the bus was created with
+	// WithErrorContext.
+	Recent []Event
+
+	// Graph is the state of the bus at the time of the timeout. It is set only when the bus was created with
+	// WithWaitGraph or WithWaitGraphFile.
+	Graph *WaitGraph
+}
+
+// WithWaitGraph makes the timed out Wait calls return a TimeoutError, that contains a snapshot of the wait
+// graph of the bus.
+func WithWaitGraph() Option {
+	return func(o *options) { o.waitGraph = true }
+}
+
+// WithWaitGraphFile makes the bus write the wait graph in DOT format to the file at path, whenever a Wait
+// call times out. The file is overwritten on every timeout. It implies WithWaitGraph.
+func WithWaitGraphFile(path string) Option {
+	return func(o *options) {
+		o.waitGraph = true
+		o.waitGraphFile = path
+	}
+}
+
+func (err *TimeoutError) Error() string {
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "%v: %s", ErrTimeout, strings.Join(err.Keys, ", "))
+	if len(err.Arrived) > 0 {
+		fmt.Fprintf(&buf, "; arrived: %s; missing: %s", formatArrived(err.Arrived), strings.Join(err.Missing, ", "))
+	}
+
+	for _, s := range err.Sources {
+		fmt.Fprintf(&buf, "; %v", s)
+	}
+
+	if len(err.Recent) > 0 {
+		fmt.Fprintf(&buf, "; recent: %s", formatRecent(err.Recent, err.Time))
+	}
+
+	return buf.String()
+}
+
+// Unwrap returns ErrTimeout.
+func (err *TimeoutError) Unwrap() error {
+	return ErrTimeout
+}
+
+func (b *SyncBus) recordReset(now time.Time, namespace string, all bool, keys []string) {
+	if !b.options.waitGraph {
+		return
+	}
+
+	for _, key := range keys {
+		b.resets[key] = resetInfo{namespace: namespace, all: all, time: now}
+	}
+}
+
+func (b *SyncBus) createWaitGraph(now time.Time, timedOut []waitItem) *WaitGraph {
+	s := b.createSnapshot(now)
+	g := &WaitGraph{Signals: s.signals}
+	for _, w := range timedOut {
+		g.Waiters = append(g.Waiters, GraphWaiter{
+			Keys:      w.keys,
+			Missing:   b.missing(w.keys),
+			Namespace: w.namespace,
+			Name:      w.name,
+			Labels:    w.labels,
+			Remaining: w.deadline.Sub(now),
+		})
+	}
+
+	for _, w := range s.waiting {
+		g.Waiters = append(g.Waiters, GraphWaiter{
+			Keys:      w.keys,
+			Missing:   w.missing,
+			Namespace: w.namespace,
+			Name:      w.name,
+			Labels:    w.labels,
+			Remaining: w.remaining,
+		})
+	}
+
+	for key, r := range b.resets {
+		g.Resets = append(g.Resets, GraphReset{Key: key, Namespace: r.namespace, All: r.all, Time: r.time})
+	}
+
+	sort.Slice(g.Resets, func(i, j int) bool { return g.Resets[i].Key < g.Resets[j].Key })
+	return g
+}
+
+func (b *SyncBus) writeWaitGraph(g *WaitGraph) {
+	if b.options.waitGraphFile == "" {
+		return
+	}
+
+	ioutil.WriteFile(b.options.waitGraphFile, []byte(g.DOT()), 0644)
+}
+
+func (g *WaitGraph) lastReset(key string) (GraphReset, bool) {
+	for _, r := range g.Resets {
+		if r.Key == key {
+			return r, true
+		}
+	}
+
+	return GraphReset{}, false
+}
+
+func (r GraphReset) String() string {
+	f := "ResetSignals"
+	if r.All {
+		f = "Reset"
+	}
+
+	if r.Namespace != "" {
+		return fmt.Sprintf("%s in %s", f, r.Namespace)
+	}
+
+	return f
+}
+
+// String returns a human readable description of the wait graph.
+func (g *WaitGraph) String() string {
+	var buf bytes.Buffer
+	fmt.Fprintln(&buf, "signals:")
+	for _, key := range g.Signals {
+		fmt.Fprintf(&buf, "  %s\n", key)
+	}
+
+	fmt.Fprintln(&buf, "waiting:")
+	for _, w := range g.Waiters {
+		fmt.Fprintf(&buf, "  keys: %s; missing: %s", strings.Join(w.Keys, ", "), strings.Join(w.Missing, ", "))
+		if w.Namespace != "" {
+			fmt.Fprintf(&buf, "; namespace: %s", w.Namespace)
+		}
+
+		if w.Name != "" {
+			fmt.Fprintf(&buf, "; name: %s", w.Name)
+		}
+
+		fmt.Fprintf(&buf, "; remaining: %v\n", w.Remaining)
+	}
+
+	fmt.Fprintln(&buf, "resets:")
+	for _, r := range g.Resets {
+		fmt.Fprintf(&buf, "  %s: %v\n", r.Key, r)
+	}
+
+	return buf.String()
+}
+
+// DOT returns the wait graph in the format of Graphviz. The waits point to the keys that they depend on, the
+// missing keys are drawn with dashed edges, and the set signals are filled.
+func (g *WaitGraph) DOT() string {
+	var buf bytes.Buffer
+	fmt.Fprintln(&buf, "digraph syncbus {")
+	keys := make(map[string]bool)
+	for _, key := range g.Signals {
+		keys[key] = true
+	}
+
+	for _, w := range g.Waiters {
+		for _, key := range w.Keys {
+			if _, ok := keys[key]; !ok {
+				keys[key] = false
+			}
+		}
+	}
+
+	var sorted []string
+	for key := range keys {
+		sorted = append(sorted, key)
+	}
+
+	sort.Strings(sorted)
+	for _, key := range sorted {
+		label := key
+		if r, ok := g.lastReset(key); ok {
+			label = fmt.Sprintf("%s\nlast reset: %v", key, r)
+		}
+
+		style := "solid"
+		if keys[key] {
+			style = "filled"
+		}
+
+		fmt.Fprintf(&buf, "  %q [shape=box, style=%s, label=%q];\n", "key:"+key, style, label)
+	}
+
+	for i, w := range g.Waiters {
+		label := fmt.Sprintf("wait %d", i)
+		if w.Name != "" {
+			label = w.Name
+		}
+
+		if w.Namespace != "" {
+			label = fmt.Sprintf("%s\n%s", label, w.Namespace)
+		}
+
+		node := fmt.Sprintf("wait:%d", i)
+		fmt.Fprintf(&buf, "  %q [label=%q];\n", node, label)
+		missing := make(map[string]bool)
+		for _, key := range w.Missing {
+			missing[key] = true
+		}
+
+		for _, key := range w.Keys {
+			style := "solid"
+			if missing[key] {
+				style = "dashed"
+			}
+
+			fmt.Fprintf(&buf, "  %q -> %q [style=%s];\n", node, "key:"+key, style)
+		}
+	}
+
+	fmt.Fprintln(&buf, "}")
+	return buf.String()
+}
+
+// WaitGraph returns the current wait graph of the bus.
+//
+// If the receiver *SyncBus is nil, it returns an empty graph.
+func (b *SyncBus) WaitGraph() *WaitGraph {
+	if b == nil {
+		return &WaitGraph{}
+	}
+
+	c := make(chan *WaitGraph, 1)
+	if b.closed() {
+		return &WaitGraph{}
+	}
+
+	select {
+	case b.graph <- c:
+		return <-c
+	case <-b.done:
+		return &WaitGraph{}
+	}
+}
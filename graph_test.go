@@ -0,0 +1,127 @@
+package syncbus
+
+import (
+	"errors"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestNilWaitGraph(t *testing.T) {
+	var bus *SyncBus
+	g := bus.WaitGraph()
+	if len(g.Waiters) != 0 || len(g.Signals) != 0 || len(g.Resets) != 0 {
+		t.Error("unexpected graph")
+	}
+}
+
+func TestTimeoutWithoutWaitGraph(t *testing.T) {
+	bus := New(12 * time.Millisecond)
+	defer bus.Close()
+	if err := bus.Wait("foo"); err != ErrTimeout {
+		t.Error("failed to timeout with the plain error")
+	}
+}
+
+func TestTimeoutWithWaitGraph(t *testing.T) {
+	bus := New(12*time.Millisecond, WithWaitGraph())
+	defer bus.Close()
+
+	bus.Signal("foo", "bar")
+	bus.ResetSignals("bar")
+	bus.Namespace("test").Signal("baz")
+	bus.Namespace("test").Reset()
+
+	err := bus.Wait("foo", "bar")
+	if !errors.Is(err, ErrTimeout) {
+		t.Fatal("failed to timeout", err)
+	}
+
+	var terr *TimeoutError
+	if !errors.As(err, &terr) {
+		t.Fatal("failed to return a timeout error", err)
+	}
+
+	if len(terr.Keys) != 2 || terr.Keys[0] != "foo" || terr.Keys[1] != "bar" {
+		t.Error("invalid keys", terr.Keys)
+	}
+
+	g := terr.Graph
+	if len(g.Signals) != 1 || g.Signals[0] != "foo" {
+		t.Error("invalid signals", g.Signals)
+	}
+
+	if len(g.Waiters) != 1 || len(g.Waiters[0].Missing) != 1 || g.Waiters[0].Missing[0] != "bar" {
+		t.Error("invalid waiters", g.Waiters)
+	}
+
+	if len(g.Resets) != 2 ||
+		g.Resets[0].Key != "bar" || g.Resets[0].All ||
+		g.Resets[1].Key != "baz" || !g.Resets[1].All || g.Resets[1].Namespace != "test" {
+		t.Error("invalid resets", g.Resets)
+	}
+
+	s := g.String()
+	for _, expected := range []string{"foo", "missing: bar", "bar: ResetSignals", "baz: Reset in test"} {
+		if !strings.Contains(s, expected) {
+			t.Errorf("missing from the graph: %q\n%s", expected, s)
+		}
+	}
+}
+
+func TestWaitGraphIncludesPendingWaits(t *testing.T) {
+	bus := New(120*time.Millisecond, WithWaitGraph())
+	defer bus.Close()
+
+	tw := newTestWait(1)
+	go func() {
+		bus.Wait("foo")
+		tw.done()
+	}()
+
+	for bus.Stats().Waiting != 1 {
+		time.Sleep(time.Millisecond / 10)
+	}
+
+	g := bus.WaitGraph()
+	if len(g.Waiters) != 1 || g.Waiters[0].Keys[0] != "foo" || g.Waiters[0].Remaining <= 0 {
+		t.Error("invalid waiters", g.Waiters)
+	}
+
+	bus.Signal("foo")
+	if err := tw.wait(); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestWaitGraphFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "graph.dot")
+	bus := New(12*time.Millisecond, WithWaitGraphFile(path))
+	defer bus.Close()
+
+	bus.Signal("foo")
+	if err := bus.Namespace("test").Wait("foo", "bar"); !errors.Is(err, ErrTimeout) {
+		t.Fatal("failed to timeout", err)
+	}
+
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	dot := string(b)
+	for _, expected := range []string{
+		"digraph syncbus {",
+		`"key:foo" [shape=box, style=filled`,
+		`"key:bar" [shape=box, style=solid`,
+		`"wait:0" -> "key:foo" [style=solid]`,
+		`"wait:0" -> "key:bar" [style=dashed]`,
+		`test`,
+	} {
+		if !strings.Contains(dot, expected) {
+			t.Errorf("missing from the graph: %q\n%s", expected, dot)
+		}
+	}
+}
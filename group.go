@@ -0,0 +1,114 @@
+package syncbus
+
+import (
+	"bytes"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// BusGroup owns a set of named buses, and allows managing them together, e.g. in TestMain fixtures using a
+// separate bus for each component.
+type BusGroup struct {
+	mx    sync.Mutex
+	names []string
+	buses map[string]*SyncBus
+}
+
+// NewGroup creates an empty group of buses.
+func NewGroup() *BusGroup {
+	return &BusGroup{buses: make(map[string]*SyncBus)}
+}
+
+// Add adds a bus to the group with the provided name. If a bus was already registered with the same name, it
+// gets replaced, but it is not closed.
+func (g *BusGroup) Add(name string, b *SyncBus) {
+	g.mx.Lock()
+	defer g.mx.Unlock()
+	if _, ok := g.buses[name]; !ok {
+		g.names = append(g.names, name)
+	}
+
+	g.buses[name] = b
+}
+
+// New creates a new bus with the provided timeout and options, and adds it to the group.
+func (g *BusGroup) New(name string, timeout time.Duration, opts ...Option) *SyncBus {
+	b := New(timeout, opts...)
+	g.Add(name, b)
+	return b
+}
+
+// Bus returns the bus registered with the provided name, or nil if no such bus exists in the group.
+func (g *BusGroup) Bus(name string) *SyncBus {
+	g.mx.Lock()
+	defer g.mx.Unlock()
+	return g.buses[name]
+}
+
+func (g *BusGroup) each(f func(name string, b *SyncBus)) {
+	g.mx.Lock()
+	names := append([]string(nil), g.names...)
+	buses := make(map[string]*SyncBus)
+	for name, b := range g.buses {
+		buses[name] = b
+	}
+
+	g.mx.Unlock()
+	for _, name := range names {
+		f(name, buses[name])
+	}
+}
+
+// ResetAll clears all the signals on every bus in the group.
+func (g *BusGroup) ResetAll() {
+	g.each(func(_ string, b *SyncBus) { b.Reset() })
+}
+
+// CloseAll tears down every bus in the group, and removes them from the group.
+func (g *BusGroup) CloseAll() {
+	g.each(func(_ string, b *SyncBus) { b.Close() })
+	g.mx.Lock()
+	defer g.mx.Unlock()
+	g.names = nil
+	g.buses = make(map[string]*SyncBus)
+}
+
+func addStats(s, o Stats) Stats {
+	s.Signals += o.Signals
+	s.Waiting += o.Waiting
+	s.Waits += o.Waits
+	s.Releases += o.Releases
+	s.Timeouts += o.Timeouts
+	s.SignalCalls += o.SignalCalls
+	s.DroppedEvents += o.DroppedEvents
+	s.ThrottledSignals += o.ThrottledSignals
+	s.DroppedHistory += o.DroppedHistory
+	s.ProducerWaits += o.ProducerWaits
+	return s
+}
+
+// Stats returns the sum of the stats of the buses in the group.
+func (g *BusGroup) Stats() Stats {
+	var s Stats
+	g.each(func(_ string, b *SyncBus) { s = addStats(s, b.Stats()) })
+	return s
+}
+
+// BusStats returns the stats of every bus in the group, by name.
+func (g *BusGroup) BusStats() map[string]Stats {
+	s := make(map[string]Stats)
+	g.each(func(name string, b *SyncBus) { s[name] = b.Stats() })
+	return s
+}
+
+// Dump returns the dump of every bus in the group, in the order they were added.
+func (g *BusGroup) Dump() string {
+	var buf bytes.Buffer
+	g.each(func(name string, b *SyncBus) {
+		fmt.Fprintf(&buf, "%s:\n", name)
+		buf.WriteString(b.Dump())
+	})
+
+	return buf.String()
+}
@@ -0,0 +1,55 @@
+package syncbus
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestGroup(t *testing.T) {
+	g := NewGroup()
+	db := g.New("db", 12*time.Millisecond)
+	api := New(12 * time.Millisecond)
+	g.Add("api", api)
+
+	if g.Bus("db") != db || g.Bus("api") != api || g.Bus("foo") != nil {
+		t.Error("invalid buses")
+	}
+
+	db.Signal("ready")
+	api.Signal("ready", "listening")
+
+	if s := g.Stats(); s.Signals != 3 || s.SignalCalls != 2 {
+		t.Error("invalid stats", s)
+	}
+
+	s := g.BusStats()
+	if len(s) != 2 || s["db"].Signals != 1 || s["api"].Signals != 2 {
+		t.Error("invalid stats", s)
+	}
+
+	d := g.Dump()
+	if !strings.HasPrefix(d, "db:\nsignals:\n  ready\n") || !strings.Contains(d, "api:\nsignals:\n  listening\n  ready\n") {
+		t.Error("invalid dump", d)
+	}
+
+	g.ResetAll()
+	if err := db.Wait("ready"); err != ErrTimeout {
+		t.Error("failed to reset")
+	}
+
+	if err := api.Wait("ready"); err != ErrTimeout {
+		t.Error("failed to reset")
+	}
+
+	g.CloseAll()
+	if g.Bus("db") != nil || len(g.BusStats()) != 0 || g.Stats() != (Stats{}) {
+		t.Error("failed to remove buses")
+	}
+
+	for range db.Events() {
+	}
+
+	for range api.Events() {
+	}
+}
@@ -0,0 +1,160 @@
+/*
+Package grpcsync provides gRPC interceptors that report the lifecycle of the RPC calls through a syncbus.Bus, so
+that integration tests can orchestrate the order of the calls.
+
+For every call, the interceptors signal the key of the start event, and then, depending on the outcome, the key
+of the done or the error event. The keys are scoped by the full method name, and whether the interceptor was
+installed on the client or on the server side, see ServerKey and ClientKey.
+
+The package is a separate module, so that the users of the syncbus module don't depend on gRPC.
+*/
+package grpcsync
+
+import (
+	"context"
+	"io"
+
+	"github.com/aryszka/syncbus"
+	"google.golang.org/grpc"
+)
+
+// Lifecycle events of an RPC call.
+const (
+	Start = "start"
+	Done  = "done"
+	Error = "error"
+)
+
+// ServerKey returns the key signaled by the server interceptors, when the event happens during handling a call
+// to the method. The method is the full method name, e.g. /package.Service/Method.
+func ServerKey(method, event string) string {
+	return "grpc.server." + method + "." + event
+}
+
+// ClientKey returns the key signaled by the client interceptors, when the event happens during a call to the
+// method. The method is the full method name, e.g. /package.Service/Method.
+func ClientKey(method, event string) string {
+	return "grpc.client." + method + "." + event
+}
+
+func signalResult(bus syncbus.Bus, key func(string, string) string, method string, err error) {
+	if err != nil {
+		bus.Signal(key(method, Error))
+		return
+	}
+
+	bus.Signal(key(method, Done))
+}
+
+// UnaryServerInterceptor returns a server interceptor for unary calls.
+func UnaryServerInterceptor(bus syncbus.Bus) grpc.UnaryServerInterceptor {
+	return func(
+		ctx context.Context,
+		req interface{},
+		info *grpc.UnaryServerInfo,
+		handler grpc.UnaryHandler,
+	) (interface{}, error) {
+		if bus == nil {
+			return handler(ctx, req)
+		}
+
+		bus.Signal(ServerKey(info.FullMethod, Start))
+		rsp, err := handler(ctx, req)
+		signalResult(bus, ServerKey, info.FullMethod, err)
+		return rsp, err
+	}
+}
+
+// StreamServerInterceptor returns a server interceptor for streaming calls. The done or error events are
+// signaled when the handler returns.
+func StreamServerInterceptor(bus syncbus.Bus) grpc.StreamServerInterceptor {
+	return func(
+		srv interface{},
+		ss grpc.ServerStream,
+		info *grpc.StreamServerInfo,
+		handler grpc.StreamHandler,
+	) error {
+		if bus == nil {
+			return handler(srv, ss)
+		}
+
+		bus.Signal(ServerKey(info.FullMethod, Start))
+		err := handler(srv, ss)
+		signalResult(bus, ServerKey, info.FullMethod, err)
+		return err
+	}
+}
+
+// UnaryClientInterceptor returns a client interceptor for unary calls.
+func UnaryClientInterceptor(bus syncbus.Bus) grpc.UnaryClientInterceptor {
+	return func(
+		ctx context.Context,
+		method string,
+		req, reply interface{},
+		cc *grpc.ClientConn,
+		invoker grpc.UnaryInvoker,
+		opts ...grpc.CallOption,
+	) error {
+		if bus == nil {
+			return invoker(ctx, method, req, reply, cc, opts...)
+		}
+
+		bus.Signal(ClientKey(method, Start))
+		err := invoker(ctx, method, req, reply, cc, opts...)
+		signalResult(bus, ClientKey, method, err)
+		return err
+	}
+}
+
+type clientStream struct {
+	grpc.ClientStream
+	bus           syncbus.Bus
+	method        string
+	serverStreams bool
+}
+
+func (s *clientStream) RecvMsg(m interface{}) error {
+	err := s.ClientStream.RecvMsg(m)
+	switch err {
+	case nil:
+		// without server streaming, e.g. in CloseAndRecv, the single response completes the call, and io.EOF
+		// is not received:
+		if !s.serverStreams {
+			signalResult(s.bus, ClientKey, s.method, nil)
+		}
+	case io.EOF:
+		signalResult(s.bus, ClientKey, s.method, nil)
+	default:
+		signalResult(s.bus, ClientKey, s.method, err)
+	}
+
+	return err
+}
+
+// StreamClientInterceptor returns a client interceptor for streaming calls. The done event is signaled when
+// receiving from the stream returns io.EOF, or, when the server doesn't stream, when the single response was
+// received. The error event is signaled when receiving returns any other error, or when the stream could not be
+// created.
+func StreamClientInterceptor(bus syncbus.Bus) grpc.StreamClientInterceptor {
+	return func(
+		ctx context.Context,
+		desc *grpc.StreamDesc,
+		cc *grpc.ClientConn,
+		method string,
+		streamer grpc.Streamer,
+		opts ...grpc.CallOption,
+	) (grpc.ClientStream, error) {
+		if bus == nil {
+			return streamer(ctx, desc, cc, method, opts...)
+		}
+
+		bus.Signal(ClientKey(method, Start))
+		s, err := streamer(ctx, desc, cc, method, opts...)
+		if err != nil {
+			signalResult(bus, ClientKey, method, err)
+			return nil, err
+		}
+
+		return &clientStream{ClientStream: s, bus: bus, method: method, serverStreams: desc.ServerStreams}, nil
+	}
+}
@@ -0,0 +1,166 @@
+package grpcsync
+
+import (
+	"context"
+	"errors"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/aryszka/syncbus"
+	"google.golang.org/grpc"
+)
+
+const testMethod = "/test.Service/Method"
+
+var errTest = errors.New("test")
+
+func TestUnaryServer(t *testing.T) {
+	bus := syncbus.New(12 * time.Millisecond)
+	defer bus.Close()
+
+	i := UnaryServerInterceptor(bus)
+	info := &grpc.UnaryServerInfo{FullMethod: testMethod}
+	rsp, err := i(context.Background(), "foo", info, func(ctx context.Context, req interface{}) (interface{}, error) {
+		if err := bus.Wait(ServerKey(testMethod, Start)); err != nil {
+			t.Error(err)
+		}
+
+		return req, nil
+	})
+
+	if rsp != "foo" || err != nil {
+		t.Error("invalid response", rsp, err)
+	}
+
+	if err := bus.Wait(ServerKey(testMethod, Done)); err != nil {
+		t.Error(err)
+	}
+
+	i(context.Background(), "foo", info, func(context.Context, interface{}) (interface{}, error) {
+		return nil, errTest
+	})
+
+	if err := bus.Wait(ServerKey(testMethod, Error)); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestStreamServer(t *testing.T) {
+	bus := syncbus.New(12 * time.Millisecond)
+	defer bus.Close()
+
+	i := StreamServerInterceptor(bus)
+	info := &grpc.StreamServerInfo{FullMethod: testMethod}
+	if err := i(nil, nil, info, func(interface{}, grpc.ServerStream) error { return errTest }); err != errTest {
+		t.Error("invalid error", err)
+	}
+
+	if err := bus.Wait(ServerKey(testMethod, Start), ServerKey(testMethod, Error)); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestUnaryClient(t *testing.T) {
+	bus := syncbus.New(12 * time.Millisecond)
+	defer bus.Close()
+
+	i := UnaryClientInterceptor(bus)
+	invoker := func(context.Context, string, interface{}, interface{}, *grpc.ClientConn, ...grpc.CallOption) error {
+		return nil
+	}
+
+	if err := i(context.Background(), testMethod, nil, nil, nil, invoker); err != nil {
+		t.Error(err)
+	}
+
+	if err := bus.Wait(ClientKey(testMethod, Start), ClientKey(testMethod, Done)); err != nil {
+		t.Error(err)
+	}
+}
+
+type testStream struct {
+	grpc.ClientStream
+	messages int
+}
+
+func (s *testStream) RecvMsg(interface{}) error {
+	if s.messages == 0 {
+		return io.EOF
+	}
+
+	s.messages--
+	return nil
+}
+
+func TestStreamClient(t *testing.T) {
+	bus := syncbus.New(12 * time.Millisecond)
+	defer bus.Close()
+
+	i := StreamClientInterceptor(bus)
+	streamer := func(context.Context, *grpc.StreamDesc, *grpc.ClientConn, string, ...grpc.CallOption) (grpc.ClientStream, error) {
+		return &testStream{messages: 1}, nil
+	}
+
+	s, err := i(context.Background(), &grpc.StreamDesc{ServerStreams: true}, nil, testMethod, streamer)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := s.RecvMsg(nil); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := bus.Wait(ClientKey(testMethod, Done)); err != syncbus.ErrTimeout {
+		t.Error("unexpected done")
+	}
+
+	if err := s.RecvMsg(nil); err != io.EOF {
+		t.Fatal("unexpected error", err)
+	}
+
+	if err := bus.Wait(ClientKey(testMethod, Start), ClientKey(testMethod, Done)); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestStreamClientNoServerStreams(t *testing.T) {
+	bus := syncbus.New(12 * time.Millisecond)
+	defer bus.Close()
+
+	i := StreamClientInterceptor(bus)
+	streamer := func(context.Context, *grpc.StreamDesc, *grpc.ClientConn, string, ...grpc.CallOption) (grpc.ClientStream, error) {
+		return &testStream{messages: 1}, nil
+	}
+
+	s, err := i(context.Background(), &grpc.StreamDesc{ClientStreams: true}, nil, testMethod, streamer)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := s.RecvMsg(nil); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := bus.Wait(ClientKey(testMethod, Start), ClientKey(testMethod, Done)); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestStreamClientError(t *testing.T) {
+	bus := syncbus.New(12 * time.Millisecond)
+	defer bus.Close()
+
+	i := StreamClientInterceptor(bus)
+	streamer := func(context.Context, *grpc.StreamDesc, *grpc.ClientConn, string, ...grpc.CallOption) (grpc.ClientStream, error) {
+		return nil, errTest
+	}
+
+	if _, err := i(context.Background(), &grpc.StreamDesc{}, nil, testMethod, streamer); err != errTest {
+		t.Error("invalid error", err)
+	}
+
+	if err := bus.Wait(ClientKey(testMethod, Error)); err != nil {
+		t.Error(err)
+	}
+}
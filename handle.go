@@ -0,0 +1,226 @@
+package syncbus
+
+import (
+	"errors"
+	"sync"
+)
+
+// ErrNotPending is returned by the methods of WaitHandle changing the keys of the wait, when the wait is not
+// pending anymore.
+var ErrNotPending = errors.New("wait not pending")
+
+// WaitStatus tells the state of a wait represented by a WaitHandle.
+type WaitStatus int
+
+const (
+	// WaitPending means that the wait didn't return yet.
+	WaitPending WaitStatus = iota
+
+	// WaitSatisfied means that the wait was released, because the signals that it was waiting for were set.
+	WaitSatisfied
+
+	// WaitTimedOut means that the wait returned with a timeout, or with an exhausted timeout budget.
+	WaitTimedOut
+
+	// WaitCancelled means that the wait returned with any other error, e.g. because the bus was closed.
+	WaitCancelled
+)
+
+type modifyItem struct {
+	signal  chan error
+	require []string
+	drop    []string
+	result  chan bool
+}
+
+// WaitHandle represents a wait registered by WaitHandle, whose required keys can be changed while it is
+// pending.
+type WaitHandle struct {
+	bus     *SyncBus
+	item    waitItem
+	pending bool
+	once    sync.Once
+	err     error
+	lock    sync.Mutex
+	keys    []string
+}
+
+func (s WaitStatus) String() string {
+	switch s {
+	case WaitPending:
+		return "pending"
+	case WaitSatisfied:
+		return "satisfied"
+	case WaitTimedOut:
+		return "timed-out"
+	case WaitCancelled:
+		return "cancelled"
+	default:
+		return "unknown"
+	}
+}
+
+// modifyWaiting changes the keys of a pending wait. It tells whether the wait was found.
+func (b *SyncBus) modifyWaiting(m modifyItem) bool {
+	for i := range b.waiting {
+		w := &b.waiting[i]
+		if w.signal != m.signal {
+			continue
+		}
+
+		var keys []string
+		for _, key := range w.keys {
+			if !containsKey(m.drop, key) {
+				keys = append(keys, key)
+			}
+		}
+
+		w.keys = uniqueKeys(append(keys, m.require...))
+		w.ids = b.signals.internKeys(w.keys)
+		return true
+	}
+
+	return false
+}
+
+// WaitHandle registers a wait for the signals represented by the keys, like Wait, but it doesn't block.
+// Instead, it returns a handle, that can be used to change the required keys while the wait is pending, and to
+// wait for the result. It allows dynamic test scenarios, that discover new dependencies mid-flight, to avoid
+// canceling and registering the waits again. The timeout of the wait is counted from the call to WaitHandle.
+//
+// If the receiver *SyncBus is nil, the returned handle is a noop.
+func (b *SyncBus) WaitHandle(keys ...string) *WaitHandle {
+	keys = uniqueKeys(keys)
+	h := &WaitHandle{
+		bus:  b,
+		keys: keys,
+		item: waitItem{
+			keys:     keys,
+			released: make(chan struct{}),
+			done:     make(chan error, 1),
+		},
+	}
+
+	if b != nil {
+		h.pending, h.err = b.registerWait(&h.item)
+	}
+
+	if !h.pending {
+		h.item.notify(h.err)
+	}
+
+	return h
+}
+
+func (h *WaitHandle) modify(require, drop []string) error {
+	if !h.pending {
+		return ErrNotPending
+	}
+
+	m := modifyItem{signal: h.item.signal, require: require, drop: drop, result: make(chan bool, 1)}
+	if h.bus.closed() {
+		return h.bus.useAfterClose("WaitHandle", require)
+	}
+
+	select {
+	case h.bus.modify <- m:
+		if !<-m.result {
+			return ErrNotPending
+		}
+
+		h.lock.Lock()
+		defer h.lock.Unlock()
+		var keys []string
+		for _, key := range h.keys {
+			if !containsKey(drop, key) {
+				keys = append(keys, key)
+			}
+		}
+
+		h.keys = uniqueKeys(append(keys, require...))
+		return nil
+	case <-h.bus.done:
+		return h.bus.useAfterClose("WaitHandle", require)
+	}
+}
+
+// Require adds keys to the pending wait. It returns ErrNotPending, when the wait already returned.
+func (h *WaitHandle) Require(keys ...string) error {
+	return h.modify(keys, nil)
+}
+
+// Drop removes keys from the pending wait. When the remaining keys are all set, the wait gets released. It
+// returns ErrNotPending, when the wait already returned.
+func (h *WaitHandle) Drop(keys ...string) error {
+	return h.modify(nil, keys)
+}
+
+// Wait blocks until the wait represented by the handle returns, and returns its result. It can be called
+// multiple times, returning the same result.
+func (h *WaitHandle) Wait() error {
+	h.once.Do(func() {
+		if !h.pending {
+			return
+		}
+
+		h.err = h.bus.receive(h.item)
+		if !h.item.noBlockIfSet {
+			h.bus.jitter()
+		}
+	})
+
+	return h.err
+}
+
+// Status tells the state of the wait, without blocking.
+func (h *WaitHandle) Status() WaitStatus {
+	select {
+	case <-h.item.released:
+	default:
+		return WaitPending
+	}
+
+	return statusOf(h.Wait())
+}
+
+func statusOf(err error) WaitStatus {
+	switch {
+	case err == nil:
+		return WaitSatisfied
+	case errors.Is(err, ErrTimeout), errors.Is(err, ErrBudget):
+		return WaitTimedOut
+	default:
+		return WaitCancelled
+	}
+}
+
+// SatisfiedKeys returns those required keys of the wait, whose signals are set. Once the wait was satisfied,
+// it returns all its keys.
+func (h *WaitHandle) SatisfiedKeys() []string {
+	h.lock.Lock()
+	keys := h.keys
+	h.lock.Unlock()
+	if h.Status() == WaitSatisfied {
+		return keys
+	}
+
+	set := h.bus.SetKeys()
+	var satisfied []string
+	for _, key := range keys {
+		if containsKey(set, key) {
+			satisfied = append(satisfied, key)
+		}
+	}
+
+	return satisfied
+}
+
+// Done returns a channel that delivers the result of the wait, once it returns, and then gets closed. It
+// allows selecting on multiple waits started earlier, without dedicating a goroutine to each of them.
+func (h *WaitHandle) Done() <-chan error {
+	return h.item.done
+}
+
+func (h *WaitHandle) finished() <-chan struct{} {
+	return h.item.released
+}
@@ -0,0 +1,198 @@
+package syncbus
+
+import (
+	"testing"
+	"time"
+)
+
+func TestNilWaitHandle(t *testing.T) {
+	var bus *SyncBus
+	h := bus.WaitHandle("foo")
+	if err := h.Require("bar"); err != ErrNotPending {
+		t.Error("unexpected result", err)
+	}
+
+	if err := h.Wait(); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestWaitHandleRequire(t *testing.T) {
+	bus := New(120 * time.Millisecond)
+	defer bus.Close()
+
+	h := bus.WaitHandle("foo")
+	if err := h.Require("bar", "foo"); err != nil {
+		t.Fatal(err)
+	}
+
+	bus.Signal("foo")
+	if err := bus.WaitTimeout(3*time.Millisecond, "never"); err != ErrTimeout {
+		t.Fatal(err)
+	}
+
+	if s := bus.Stats(); s.Waiting != 1 {
+		t.Fatal("released too early", s)
+	}
+
+	bus.Signal("bar")
+	if err := h.Wait(); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := h.Wait(); err != nil {
+		t.Error(err)
+	}
+
+	if err := h.Require("baz"); err != ErrNotPending {
+		t.Error("failed to fail", err)
+	}
+}
+
+func TestWaitHandleDrop(t *testing.T) {
+	bus := New(120 * time.Millisecond)
+	defer bus.Close()
+
+	bus.Signal("foo")
+	h := bus.WaitHandle("foo", "bar")
+	if err := h.Drop("bar"); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := h.Wait(); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestWaitHandleTimeout(t *testing.T) {
+	bus := New(3 * time.Millisecond)
+	defer bus.Close()
+
+	h := bus.WaitHandle("foo")
+	if err := h.Wait(); err != ErrTimeout {
+		t.Error("failed to timeout", err)
+	}
+}
+
+func TestWaitHandleClosed(t *testing.T) {
+	bus := New(120 * time.Millisecond)
+	h := bus.WaitHandle("foo")
+	bus.Close()
+	if err := h.Require("bar"); err != ErrClosed {
+		t.Error("failed to fail", err)
+	}
+
+	if err := h.Wait(); err != ErrClosed {
+		t.Error("failed to fail", err)
+	}
+
+	if err := bus.WaitHandle("foo").Wait(); err != ErrClosed {
+		t.Error("failed to fail", err)
+	}
+}
+
+func TestWaitHandleStatus(t *testing.T) {
+	bus := New(120 * time.Millisecond)
+	defer bus.Close()
+
+	bus.Signal("foo")
+	h := bus.WaitHandle("foo", "bar")
+	if s := h.Status(); s != WaitPending {
+		t.Fatal("unexpected status", s)
+	}
+
+	if k := h.SatisfiedKeys(); len(k) != 1 || k[0] != "foo" {
+		t.Fatal("unexpected satisfied keys", k)
+	}
+
+	bus.Signal("bar")
+	select {
+	case err := <-h.Done():
+		if err != nil {
+			t.Fatal(err)
+		}
+	case <-time.After(120 * time.Millisecond):
+		t.Fatal("failed to release")
+	}
+
+	if _, ok := <-h.Done(); ok {
+		t.Error("failed to close done")
+	}
+
+	if s := h.Status(); s != WaitSatisfied {
+		t.Error("unexpected status", s)
+	}
+
+	bus.ResetSignals("foo")
+	if k := h.SatisfiedKeys(); len(k) != 2 {
+		t.Error("unexpected satisfied keys", k)
+	}
+}
+
+func TestWaitHandleStatusTimeout(t *testing.T) {
+	bus := New(3 * time.Millisecond)
+	defer bus.Close()
+
+	h := bus.WaitHandle("foo")
+	if err := <-h.Done(); err != ErrTimeout {
+		t.Fatal("failed to timeout", err)
+	}
+
+	if s := h.Status(); s != WaitTimedOut {
+		t.Error("unexpected status", s)
+	}
+
+	if err := h.Wait(); err != ErrTimeout {
+		t.Error("failed to timeout", err)
+	}
+}
+
+func TestWaitHandleStatusCancelled(t *testing.T) {
+	bus := New(120 * time.Millisecond)
+	h := bus.WaitHandle("foo")
+	bus.Close()
+	if err := <-h.Done(); err != ErrClosed {
+		t.Fatal("failed to fail", err)
+	}
+
+	if s := h.Status(); s != WaitCancelled {
+		t.Error("unexpected status", s)
+	}
+
+	if s := WaitCancelled.String(); s != "cancelled" {
+		t.Error("unexpected string", s)
+	}
+}
+
+func TestWaitHandleSignalSync(t *testing.T) {
+	bus := New(120 * time.Millisecond)
+	defer bus.Close()
+
+	h := bus.WaitHandle("foo")
+	bus.SignalSync("foo")
+	if err := h.Wait(); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestWaitHandleDoneSignalSync(t *testing.T) {
+	bus := New(120 * time.Millisecond)
+	defer bus.Close()
+
+	h := bus.WaitHandle("foo")
+	synced := make(chan struct{})
+	go func() {
+		bus.SignalSync("foo")
+		close(synced)
+	}()
+
+	if err := <-h.Done(); err != nil {
+		t.Fatal(err)
+	}
+
+	select {
+	case <-synced:
+	case <-time.After(120 * time.Millisecond):
+		t.Error("SignalSync blocked by the handle")
+	}
+}
@@ -0,0 +1,29 @@
+package syncbus
+
+type checkItem struct {
+	key    string
+	result chan bool
+}
+
+// SynchronizeWith tells whether the signal represented by key is set, without blocking. When it returns
+// true, the call happens after the Signal call that set the signal, in terms of the Go memory model, so the
+// values written by the signaling goroutine before calling Signal can be safely read by the caller.
+//
+// If the receiver *SyncBus is nil, it returns false.
+func (b *SyncBus) SynchronizeWith(key string) bool {
+	if b == nil {
+		return false
+	}
+
+	c := checkItem{key: key, result: make(chan bool, 1)}
+	if b.closed() {
+		return false
+	}
+
+	select {
+	case b.check <- c:
+		return <-c.result
+	case <-b.done:
+		return false
+	}
+}
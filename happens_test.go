@@ -0,0 +1,79 @@
+package syncbus
+
+import (
+	"testing"
+	"time"
+)
+
+// these tests are meaningful when running with -race
+
+func TestHappensBeforeWait(t *testing.T) {
+	bus := New(120 * time.Millisecond)
+	defer bus.Close()
+
+	var value int
+	go func() {
+		value = 42
+		bus.Signal("published")
+	}()
+
+	if err := bus.Wait("published"); err != nil {
+		t.Fatal(err)
+	}
+
+	if value != 42 {
+		t.Error("invalid value", value)
+	}
+}
+
+func TestHappensBeforeWaitMultipleSignalers(t *testing.T) {
+	bus := New(120 * time.Millisecond)
+	defer bus.Close()
+
+	var foo, bar int
+	go func() {
+		foo = 1
+		bus.Signal("foo")
+	}()
+
+	go func() {
+		bar = 2
+		bus.Namespace("test").Signal("bar")
+	}()
+
+	if err := bus.Wait("foo", "bar"); err != nil {
+		t.Fatal(err)
+	}
+
+	if foo != 1 || bar != 2 {
+		t.Error("invalid values", foo, bar)
+	}
+}
+
+func TestSynchronizeWith(t *testing.T) {
+	var nilBus *SyncBus
+	if nilBus.SynchronizeWith("foo") {
+		t.Error("unexpected signal")
+	}
+
+	bus := New(120 * time.Millisecond)
+	defer bus.Close()
+
+	if bus.SynchronizeWith("published") {
+		t.Error("unexpected signal")
+	}
+
+	var value int
+	go func() {
+		value = 42
+		bus.Signal("published")
+	}()
+
+	for !bus.SynchronizeWith("published") {
+		time.Sleep(time.Millisecond / 10)
+	}
+
+	if value != 42 {
+		t.Error("invalid value", value)
+	}
+}
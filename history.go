@@ -0,0 +1,153 @@
+package syncbus
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// RateError is returned by AssertRate when a key was signaled more often than allowed.
+type RateError struct {
+
+	// Key is the key of the signal.
+	Key string
+
+	// Max is the allowed number of signals per period.
+	Max int
+
+	// Per is the length of the period.
+	Per time.Duration
+
+	// Count is the number of signals within the violating period.
+	Count int
+
+	// From is the time of the first signal in the violating period.
+	From time.Time
+}
+
+// ErrRate is the error that RateError unwraps to.
+var ErrRate = errors.New("rate exceeded")
+
+// ErrNoHistory is returned by AssertRate when the bus was created without WithHistory.
+var ErrNoHistory = errors.New("history not enabled")
+
+// ErrInvalidRate is returned by AssertRate when the period is not positive, or the maximum is negative.
+var ErrInvalidRate = errors.New("invalid rate")
+
+// DefaultHistorySize is the default capacity of the history enabled by WithHistory.
+const DefaultHistorySize = 1 << 16
+
+type history struct {
+	events  []Event
+	start   int
+	dropped uint64
+}
+
+// WithHistory enables recording the history of the events on the bus. The history can be retrieved by the
+// History method, and it is used by the assertions like AssertRate. It keeps the most recent DefaultHistorySize
+// events, see also WithHistorySize.
+func WithHistory() Option {
+	return func(o *options) {
+		if o.historySize <= 0 {
+			o.historySize = DefaultHistorySize
+		}
+	}
+}
+
+// WithHistorySize enables recording the history of the events on the bus, keeping at most the n most recent
+// events. The number of the events dropped from the history is reported by Stats. It allows long running
+// tests to use the history without unbounded memory growth.
+func WithHistorySize(n int) Option {
+	return func(o *options) { o.historySize = n }
+}
+
+func (err *RateError) Error() string {
+	return fmt.Sprintf(
+		"%v: key %q was signaled %d times within %v, max: %d",
+		ErrRate,
+		err.Key,
+		err.Count,
+		err.Per,
+		err.Max,
+	)
+}
+
+// Unwrap returns ErrRate.
+func (err *RateError) Unwrap() error {
+	return ErrRate
+}
+
+func (h *history) add(size int, e Event) {
+	if len(h.events) < size {
+		h.events = append(h.events, e)
+		return
+	}
+
+	h.events[h.start] = e
+	h.start = (h.start + 1) % size
+	h.dropped++
+}
+
+func (b *SyncBus) record(e Event) {
+	if b.options.historySize > 0 {
+		b.history.add(b.options.historySize, e)
+	}
+}
+
+// History returns the recorded events, in the order they happened, when the bus was created with WithHistory.
+// Unlike the event stream, the history doesn't drop events, except for the oldest ones exceeding the capacity
+// of the history.
+//
+// If the receiver *SyncBus is nil, or the history is not enabled, it returns nil.
+func (b *SyncBus) History() []Event {
+	if b == nil || b.options.historySize <= 0 {
+		return nil
+	}
+
+	return b.queryHistory(Filter{})
+}
+
+// AssertRate checks the recorded history, and returns a *RateError if the signal represented by key was set
+// more than max times within any period of the length per. It requires the bus to be created with WithHistory,
+// otherwise it returns ErrNoHistory. When per is not positive, or max is negative, it returns ErrInvalidRate.
+//
+// If the receiver *SyncBus is nil, it returns nil.
+func (b *SyncBus) AssertRate(key string, max int, per time.Duration) error {
+	if b == nil {
+		return nil
+	}
+
+	if per <= 0 || max < 0 {
+		return ErrInvalidRate
+	}
+
+	if b.options.historySize <= 0 {
+		return ErrNoHistory
+	}
+
+	var times []time.Time
+	for _, e := range b.History() {
+		if e.Type != EventSignal {
+			continue
+		}
+
+		for _, k := range e.Keys {
+			if k == key {
+				times = append(times, e.Time)
+				break
+			}
+		}
+	}
+
+	for i, j := 0, 0; j < len(times); j++ {
+		for times[j].Sub(times[i]) >= per {
+			i++
+		}
+
+		if j-i+1 > max {
+			return &RateError{Key: key, Max: max, Per: per, Count: j - i + 1, From: times[i]}
+		}
+	}
+
+	return nil
+}
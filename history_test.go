@@ -0,0 +1,134 @@
+package syncbus
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestNilHistory(t *testing.T) {
+	var bus *SyncBus
+	if bus.History() != nil {
+		t.Error("unexpected history")
+	}
+
+	if err := bus.AssertRate("foo", 1, time.Second); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestHistoryDisabled(t *testing.T) {
+	bus := New(12 * time.Millisecond)
+	defer bus.Close()
+
+	bus.Signal("foo")
+	if bus.History() != nil {
+		t.Error("unexpected history")
+	}
+
+	if err := bus.AssertRate("foo", 1, time.Second); err != ErrNoHistory {
+		t.Error("failed to fail without history", err)
+	}
+}
+
+func TestHistory(t *testing.T) {
+	bus := New(12*time.Millisecond, WithHistory(), WithEventBuffer(0))
+	defer bus.Close()
+
+	bus.Signal("foo")
+	if err := bus.Wait("foo"); err != nil {
+		t.Error(err)
+	}
+
+	bus.ResetSignals("foo")
+	h := bus.History()
+	expected := []EventType{EventSignal, EventWait, EventRelease, EventReset}
+	if len(h) != len(expected) {
+		t.Fatal("invalid history", h)
+	}
+
+	for i := range h {
+		if h[i].Type != expected[i] || h[i].Keys[0] != "foo" {
+			t.Error("invalid event", h[i])
+		}
+	}
+}
+
+func TestAssertRate(t *testing.T) {
+	bus := New(12*time.Millisecond, WithHistory())
+	defer bus.Close()
+
+	bus.Signal("retry")
+	bus.Signal("other")
+	bus.Signal("retry", "other")
+	bus.Signal("retry")
+
+	if err := bus.AssertRate("retry", 3, time.Hour); err != nil {
+		t.Error(err)
+	}
+
+	err := bus.AssertRate("retry", 2, time.Hour)
+	var rerr *RateError
+	if !errors.As(err, &rerr) || !errors.Is(err, ErrRate) {
+		t.Fatal("failed to detect the rate violation", err)
+	}
+
+	if rerr.Key != "retry" || rerr.Count != 3 || rerr.Max != 2 {
+		t.Error("invalid error", rerr)
+	}
+}
+
+func TestAssertRateSpaced(t *testing.T) {
+	bus := New(12*time.Millisecond, WithHistory())
+	defer bus.Close()
+
+	for i := 0; i < 3; i++ {
+		bus.Signal("retry")
+		time.Sleep(3 * time.Millisecond)
+	}
+
+	if err := bus.AssertRate("retry", 1, time.Millisecond); err != nil {
+		t.Error(err)
+	}
+
+	if err := bus.AssertRate("retry", 1, time.Hour); !errors.Is(err, ErrRate) {
+		t.Error("failed to detect the rate violation", err)
+	}
+}
+
+func TestAssertRateInvalid(t *testing.T) {
+	bus := New(12*time.Millisecond, WithHistory())
+	defer bus.Close()
+
+	bus.Signal("retry")
+	for _, test := range []struct {
+		max int
+		per time.Duration
+	}{
+		{1, 0},
+		{1, -time.Second},
+		{-1, time.Second},
+	} {
+		if err := bus.AssertRate("retry", test.max, test.per); err != ErrInvalidRate {
+			t.Error("failed to reject the invalid rate", test.max, test.per, err)
+		}
+	}
+}
+
+func TestHistorySize(t *testing.T) {
+	bus := New(12*time.Millisecond, WithHistorySize(3))
+	defer bus.Close()
+
+	for _, key := range []string{"a", "b", "c", "d", "e"} {
+		bus.Signal(key)
+	}
+
+	h := bus.History()
+	if len(h) != 3 || h[0].Keys[0] != "c" || h[1].Keys[0] != "d" || h[2].Keys[0] != "e" {
+		t.Error("invalid history", h)
+	}
+
+	if s := bus.Stats(); s.DroppedHistory != 2 {
+		t.Error("invalid stats", s)
+	}
+}
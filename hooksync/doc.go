@@ -0,0 +1,24 @@
+/*
+Package hooksync provides synchronization hooks for permanently instrumented production code, that cost nothing
+in the release builds.
+
+By default, the Bus of the package is a stub: all its methods are empty, they compile to no-ops, without
+allocations and goroutines. When building with the syncbus build tag, e.g. go test -tags syncbus ./..., the Bus
+is backed by a real *syncbus.SyncBus, and the tests can access it with the SyncBus method. The functions and
+methods of the package have the same signatures with and without the build tag, so the code using them
+compiles either way. The stub refers to the syncbus package only in these signatures, and it ignores the
+arguments of New and Wrap.
+
+The production code holds a *Bus, typically nil outside of the tests, and calls its methods at the
+synchronization points:
+
+	type Service struct {
+		Hooks *hooksync.Bus
+	}
+
+	func (s *Service) process() {
+		// ...
+		s.Hooks.Signal("processed")
+	}
+*/
+package hooksync
@@ -0,0 +1,74 @@
+//go:build syncbus
+// +build syncbus
+
+package hooksync
+
+import (
+	"time"
+
+	"github.com/aryszka/syncbus"
+)
+
+// Enabled tells whether the package was built with the syncbus build tag.
+const Enabled = true
+
+// Bus is backed by a *syncbus.SyncBus, when the package is built with the syncbus build tag.
+type Bus struct {
+	bus *syncbus.SyncBus
+}
+
+// New creates a Bus backed by a new *syncbus.SyncBus.
+func New(timeout time.Duration, opts ...syncbus.Option) *Bus {
+	return Wrap(syncbus.New(timeout, opts...))
+}
+
+// Wrap creates a Bus backed by an existing *syncbus.SyncBus.
+func Wrap(b *syncbus.SyncBus) *Bus {
+	return &Bus{bus: b}
+}
+
+// SyncBus returns the *syncbus.SyncBus backing the Bus.
+//
+// If the receiver *Bus is nil, it returns nil.
+func (b *Bus) SyncBus() *syncbus.SyncBus {
+	if b == nil {
+		return nil
+	}
+
+	return b.bus
+}
+
+// Wait calls the Wait method of the backing bus.
+//
+// If the receiver *Bus is nil, it is a noop.
+func (b *Bus) Wait(keys ...string) error {
+	return b.SyncBus().Wait(keys...)
+}
+
+// Signal calls the Signal method of the backing bus.
+//
+// If the receiver *Bus is nil, it is a noop.
+func (b *Bus) Signal(keys ...string) {
+	b.SyncBus().Signal(keys...)
+}
+
+// ResetSignals calls the ResetSignals method of the backing bus.
+//
+// If the receiver *Bus is nil, it is a noop.
+func (b *Bus) ResetSignals(keys ...string) {
+	b.SyncBus().ResetSignals(keys...)
+}
+
+// Reset calls the Reset method of the backing bus.
+//
+// If the receiver *Bus is nil, it is a noop.
+func (b *Bus) Reset() {
+	b.SyncBus().Reset()
+}
+
+// Close calls the Close method of the backing bus.
+//
+// If the receiver *Bus is nil, it is a noop.
+func (b *Bus) Close() {
+	b.SyncBus().Close()
+}
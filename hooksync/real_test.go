@@ -0,0 +1,42 @@
+//go:build syncbus
+// +build syncbus
+
+package hooksync
+
+import (
+	"testing"
+	"time"
+
+	"github.com/aryszka/syncbus"
+)
+
+func TestReal(t *testing.T) {
+	if !Enabled {
+		t.Fatal("unexpected stub")
+	}
+
+	var nilBus *Bus
+	nilBus.Signal("foo")
+	if err := nilBus.Wait("foo"); err != nil {
+		t.Fatal(err)
+	}
+
+	b := New(3 * time.Millisecond)
+	defer b.Close()
+
+	b.Signal("foo")
+	if err := b.Wait("foo"); err != nil {
+		t.Fatal(err)
+	}
+
+	b.ResetSignals("foo")
+	if err := b.Wait("foo"); err != syncbus.ErrTimeout {
+		t.Fatal("failed to timeout", err)
+	}
+
+	b.Signal("bar")
+	b.Reset()
+	if b.SyncBus().IsSet("bar") {
+		t.Error("failed to reset")
+	}
+}
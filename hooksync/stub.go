@@ -0,0 +1,40 @@
+//go:build !syncbus
+// +build !syncbus
+
+package hooksync
+
+import (
+	"time"
+
+	"github.com/aryszka/syncbus"
+)
+
+// Enabled tells whether the package was built with the syncbus build tag.
+const Enabled = false
+
+// Bus is a stub, whose methods are no-ops. Build with the syncbus build tag to get the real implementation.
+type Bus struct{}
+
+// New returns a nil *Bus, whose methods are no-ops. The options are ignored.
+func New(timeout time.Duration, opts ...syncbus.Option) *Bus { return nil }
+
+// Wrap returns a nil *Bus, whose methods are no-ops. The provided bus is ignored.
+func Wrap(b *syncbus.SyncBus) *Bus { return nil }
+
+// SyncBus returns nil.
+func (b *Bus) SyncBus() *syncbus.SyncBus { return nil }
+
+// Wait is a no-op.
+func (b *Bus) Wait(keys ...string) error { return nil }
+
+// Signal is a no-op.
+func (b *Bus) Signal(keys ...string) {}
+
+// ResetSignals is a no-op.
+func (b *Bus) ResetSignals(keys ...string) {}
+
+// Reset is a no-op.
+func (b *Bus) Reset() {}
+
+// Close is a no-op.
+func (b *Bus) Close() {}
@@ -0,0 +1,40 @@
+//go:build !syncbus
+// +build !syncbus
+
+package hooksync
+
+import (
+	"testing"
+	"time"
+
+	"github.com/aryszka/syncbus"
+)
+
+func TestStub(t *testing.T) {
+	if Enabled {
+		t.Fatal("unexpected real implementation")
+	}
+
+	sb := syncbus.New(time.Second)
+	defer sb.Close()
+
+	b := New(time.Second, syncbus.WithHistory())
+	if b != nil || Wrap(sb) != nil || b.SyncBus() != nil {
+		t.Fatal("unexpected bus")
+	}
+
+	allocs := testing.AllocsPerRun(12, func() {
+		b.Signal("foo")
+		b.ResetSignals("foo")
+		b.Reset()
+		if err := b.Wait("foo"); err != nil {
+			t.Fatal(err)
+		}
+
+		b.Close()
+	})
+
+	if allocs != 0 {
+		t.Error("unexpected allocations", allocs)
+	}
+}
@@ -0,0 +1,133 @@
+package httpsync
+
+import (
+	"encoding/json"
+	"fmt"
+	"html/template"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/aryszka/syncbus"
+)
+
+// DebugEvents is the maximum number of the recent events displayed by the DebugHandler.
+const DebugEvents = 100
+
+type debugEvent struct {
+	Type   string            `json:"type"`
+	Keys   []string          `json:"keys,omitempty"`
+	Time   time.Time         `json:"time"`
+	Meta   map[string]string `json:"meta,omitempty"`
+	Labels map[string]string `json:"labels,omitempty"`
+}
+
+type debugWaiter struct {
+	Keys      []string          `json:"keys"`
+	Missing   []string          `json:"missing,omitempty"`
+	Namespace string            `json:"namespace,omitempty"`
+	Name      string            `json:"name,omitempty"`
+	Labels    map[string]string `json:"labels,omitempty"`
+	Remaining time.Duration     `json:"remaining"`
+}
+
+type debugState struct {
+	Signals []string      `json:"signals"`
+	Waiting []debugWaiter `json:"waiting"`
+	Stats   syncbus.Stats `json:"stats"`
+	Events  []debugEvent  `json:"events,omitempty"`
+}
+
+func formatLabels(labels map[string]string) string {
+	var l []string
+	for name, value := range labels {
+		l = append(l, fmt.Sprintf("%s=%s", name, value))
+	}
+
+	sort.Strings(l)
+	return strings.Join(l, ", ")
+}
+
+var debugTemplate = template.Must(template.New("debug").Funcs(template.FuncMap{
+	"join":   func(s []string) string { return strings.Join(s, ", ") },
+	"labels": formatLabels,
+}).Parse(`<!DOCTYPE html>
+<html>
+<head><title>syncbus</title></head>
+<body>
+<h1>signals</h1>
+<ul>{{range .Signals}}<li>{{.}}</li>{{end}}</ul>
+<h1>waiting</h1>
+<table>
+<tr><th>keys</th><th>missing</th><th>namespace</th><th>name</th><th>labels</th><th>remaining</th></tr>
+{{range .Waiting}}<tr><td>{{join .Keys}}</td><td>{{join .Missing}}</td><td>{{.Namespace}}</td><td>{{.Name}}</td>
+<td>{{labels .Labels}}</td><td>{{.Remaining}}</td></tr>
+{{end}}</table>
+<h1>stats</h1>
+<pre>{{printf "%+v" .Stats}}</pre>
+<h1>events</h1>
+<table>
+<tr><th>time</th><th>type</th><th>keys</th><th>meta</th><th>labels</th></tr>
+{{range .Events}}<tr><td>{{.Time.Format "15:04:05.000000"}}</td><td>{{.Type}}</td><td>{{join .Keys}}</td>
+<td>{{labels .Meta}}</td><td>{{labels .Labels}}</td></tr>
+{{end}}</table>
+</body>
+</html>
+`))
+
+func newDebugState(b *syncbus.SyncBus) debugState {
+	g := b.WaitGraph()
+	d := debugState{Signals: g.Signals, Stats: b.Stats()}
+	for _, w := range g.Waiters {
+		d.Waiting = append(d.Waiting, debugWaiter(w))
+	}
+
+	h := b.History()
+	if len(h) > DebugEvents {
+		h = h[len(h)-DebugEvents:]
+	}
+
+	for _, e := range h {
+		d.Events = append(d.Events, debugEvent{
+			Type:   e.Type.String(),
+			Keys:   e.Keys,
+			Time:   e.Time,
+			Meta:   e.Meta,
+			Labels: e.Labels,
+		})
+	}
+
+	return d
+}
+
+func wantsJSON(r *http.Request) bool {
+	return r.URL.Query().Get("format") == "json" ||
+		strings.Contains(r.Header.Get("Accept"), "application/json")
+}
+
+// DebugHandler returns an HTTP handler serving a live view of the bus: the currently set signals, the pending
+// waits, the stats, and when the bus was created with syncbus.WithHistory, the most recent events, at most
+// DebugEvents. By default, it serves an HTML page. When the request has the query parameter format=json, or
+// it accepts application/json, it serves the same data as JSON. It allows attaching a browser to a long
+// running soak test built on the bus.
+//
+// If bus is nil, the handler responds with 404 Not Found.
+func DebugHandler(bus *syncbus.SyncBus) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if bus == nil {
+			http.NotFound(w, r)
+			return
+		}
+
+		d := newDebugState(bus)
+		if wantsJSON(r) {
+			w.Header().Set("Content-Type", "application/json")
+			json.NewEncoder(w).Encode(d)
+			return
+		}
+
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		debugTemplate.Execute(w, d)
+	})
+}
@@ -0,0 +1,94 @@
+package httpsync
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aryszka/syncbus"
+)
+
+func TestNilDebugHandler(t *testing.T) {
+	rsp := httptest.NewRecorder()
+	DebugHandler(nil).ServeHTTP(rsp, httptest.NewRequest("GET", "/", nil))
+	if rsp.Code != http.StatusNotFound {
+		t.Error("unexpected status", rsp.Code)
+	}
+}
+
+func debugTestBus(t *testing.T) (*syncbus.SyncBus, chan error) {
+	bus := syncbus.New(120*time.Millisecond, syncbus.WithHistory())
+	bus.Signal("foo")
+	errs := make(chan error, 1)
+	opts := []syncbus.WaitOpt{syncbus.WithName("<ready>"), syncbus.WithLabel("component", "ingress")}
+	go func() { errs <- bus.WaitWith(opts, "bar") }()
+	for bus.Stats().Waiting != 1 {
+		time.Sleep(time.Millisecond / 10)
+	}
+
+	return bus, errs
+}
+
+func TestDebugHandlerHTML(t *testing.T) {
+	bus, errs := debugTestBus(t)
+	defer bus.Close()
+
+	rsp := httptest.NewRecorder()
+	DebugHandler(bus).ServeHTTP(rsp, httptest.NewRequest("GET", "/", nil))
+	if !strings.HasPrefix(rsp.Header().Get("Content-Type"), "text/html") {
+		t.Error("invalid content type", rsp.Header())
+	}
+
+	body := rsp.Body.String()
+	for _, s := range []string{"<li>foo</li>", "&lt;ready&gt;", "component=ingress", "<td>signal</td>"} {
+		if !strings.Contains(body, s) {
+			t.Error("missing from the page", s, body)
+		}
+	}
+
+	bus.Signal("bar")
+	if err := <-errs; err != nil {
+		t.Error(err)
+	}
+}
+
+func TestDebugHandlerJSON(t *testing.T) {
+	bus, errs := debugTestBus(t)
+	defer bus.Close()
+
+	for _, req := range []*http.Request{
+		httptest.NewRequest("GET", "/?format=json", nil),
+		func() *http.Request {
+			r := httptest.NewRequest("GET", "/", nil)
+			r.Header.Set("Accept", "application/json")
+			return r
+		}(),
+	} {
+		rsp := httptest.NewRecorder()
+		DebugHandler(bus).ServeHTTP(rsp, req)
+		var d debugState
+		if err := json.Unmarshal(rsp.Body.Bytes(), &d); err != nil {
+			t.Fatal(err)
+		}
+
+		if len(d.Signals) != 1 || d.Signals[0] != "foo" {
+			t.Error("invalid signals", d.Signals)
+		}
+
+		if len(d.Waiting) != 1 || d.Waiting[0].Name != "<ready>" || d.Waiting[0].Missing[0] != "bar" {
+			t.Error("invalid waiting", d.Waiting)
+		}
+
+		if len(d.Events) != 2 || d.Stats.Waiting != 1 {
+			t.Error("invalid state", d)
+		}
+	}
+
+	bus.Signal("bar")
+	if err := <-errs; err != nil {
+		t.Error(err)
+	}
+}
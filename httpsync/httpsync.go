@@ -0,0 +1,48 @@
+/*
+Package httpsync provides helpers for synchronizing tests of HTTP servers through a syncbus.Bus, and an HTTP
+handler serving a live view of a bus.
+*/
+package httpsync
+
+import (
+	"net/http"
+
+	"github.com/aryszka/syncbus"
+)
+
+// KeyFunc returns the route of a request, used as part of the signal keys.
+type KeyFunc func(*http.Request) string
+
+// ReceivedKey returns the key signaled when a request with the provided route was received.
+func ReceivedKey(route string) string {
+	return "req." + route + ".received"
+}
+
+// DoneKey returns the key signaled when the handler of a request with the provided route returned.
+func DoneKey(route string) string {
+	return "req." + route + ".done"
+}
+
+// Middleware returns an HTTP middleware that signals req.<route>.received before calling the wrapped handler,
+// and req.<route>.done after it returned. The route is determined by keyFn. If keyFn is nil, the path of the
+// request URL is used as the route.
+//
+// If bus is nil, the middleware only calls the wrapped handler.
+func Middleware(bus syncbus.Bus, keyFn KeyFunc) func(http.Handler) http.Handler {
+	if keyFn == nil {
+		keyFn = func(r *http.Request) string { return r.URL.Path }
+	}
+
+	return func(next http.Handler) http.Handler {
+		if bus == nil {
+			return next
+		}
+
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			route := keyFn(r)
+			bus.Signal(ReceivedKey(route))
+			defer bus.Signal(DoneKey(route))
+			next.ServeHTTP(w, r)
+		})
+	}
+}
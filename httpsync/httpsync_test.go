@@ -0,0 +1,63 @@
+package httpsync
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/aryszka/syncbus"
+)
+
+func TestMiddleware(t *testing.T) {
+	bus := syncbus.New(120 * time.Millisecond)
+	defer bus.Close()
+
+	proceed := make(chan struct{})
+	h := Middleware(bus, nil)(http.HandlerFunc(func(http.ResponseWriter, *http.Request) {
+		<-proceed
+	}))
+
+	done := make(chan struct{})
+	go func() {
+		h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/foo", nil))
+		close(done)
+	}()
+
+	if err := bus.Wait("req./foo.received"); err != nil {
+		t.Fatal(err)
+	}
+
+	close(proceed)
+	if err := bus.Wait("req./foo.done"); err != nil {
+		t.Fatal(err)
+	}
+
+	<-done
+}
+
+func TestMiddlewareKeyFunc(t *testing.T) {
+	bus := syncbus.New(120 * time.Millisecond)
+	defer bus.Close()
+
+	h := Middleware(bus, func(r *http.Request) string {
+		return r.Method + ".users"
+	})(http.NotFoundHandler())
+
+	h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("POST", "/users/42", nil))
+	if err := bus.Wait(ReceivedKey("POST.users"), DoneKey("POST.users")); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestMiddlewareNilBus(t *testing.T) {
+	var called bool
+	h := Middleware(nil, nil)(http.HandlerFunc(func(http.ResponseWriter, *http.Request) {
+		called = true
+	}))
+
+	h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/", nil))
+	if !called {
+		t.Error("failed to call handler")
+	}
+}
@@ -0,0 +1,147 @@
+package httpsync
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+
+	"github.com/aryszka/syncbus"
+)
+
+// ListeningKey is signaled when the server was started.
+const ListeningKey = "server.listening"
+
+// ClosedKey is signaled when the server was closed.
+const ClosedKey = "server.closed"
+
+// Server wraps an httptest.Server, and reports its state through a bus. Requests to held paths are paused
+// at a checkpoint until they get released by the test.
+type Server struct {
+	*httptest.Server
+	bus      syncbus.Bus
+	mx       sync.Mutex
+	held     map[string]bool
+	inFlight int
+}
+
+// HoldKey returns the key signaled when a request to a held path reached the checkpoint.
+func HoldKey(path string) string {
+	return "server.hold." + path
+}
+
+// InFlightKey returns the key that is set while the number of in-flight requests equals n.
+func InFlightKey(n int) string {
+	return "server.inflight." + strconv.Itoa(n)
+}
+
+// NewUnstartedServer creates a server wrapping the handler, without starting it.
+func NewUnstartedServer(bus syncbus.Bus, h http.Handler) *Server {
+	s := &Server{
+		bus:  bus,
+		held: make(map[string]bool),
+	}
+
+	s.Server = httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		s.serve(h, w, r)
+	}))
+
+	return s
+}
+
+// NewServer creates and starts a server wrapping the handler.
+func NewServer(bus syncbus.Bus, h http.Handler) *Server {
+	s := NewUnstartedServer(bus, h)
+	s.Start()
+	return s
+}
+
+func (s *Server) signal(keys ...string) {
+	if s.bus != nil {
+		s.bus.Signal(keys...)
+	}
+}
+
+func (s *Server) updateInFlight(delta int) {
+	s.mx.Lock()
+	defer s.mx.Unlock()
+	if s.bus != nil {
+		s.bus.ResetSignals(InFlightKey(s.inFlight))
+	}
+
+	s.inFlight += delta
+	s.signal(InFlightKey(s.inFlight))
+}
+
+func (s *Server) serve(h http.Handler, w http.ResponseWriter, r *http.Request) {
+	s.updateInFlight(1)
+	defer s.updateInFlight(-1)
+
+	s.mx.Lock()
+	held := s.held[r.URL.Path]
+	s.mx.Unlock()
+
+	if held {
+		if err := syncbus.Checkpoint(s.bus, HoldKey(r.URL.Path)); err != nil {
+			http.Error(w, err.Error(), http.StatusServiceUnavailable)
+			return
+		}
+	}
+
+	h.ServeHTTP(w, r)
+}
+
+// Start starts the server, and signals ListeningKey.
+func (s *Server) Start() {
+	s.Server.Start()
+	s.signal(InFlightKey(0), ListeningKey)
+}
+
+// StartTLS starts the server with TLS, and signals ListeningKey.
+func (s *Server) StartTLS() {
+	s.Server.StartTLS()
+	s.signal(InFlightKey(0), ListeningKey)
+}
+
+// Hold makes the requests to the path pause before calling the handler, until the path is released. When a
+// request reaches the checkpoint, HoldKey(path) gets signaled. When the checkpoint fails, e.g. because the
+// release timed out or the bus was closed, the request is answered with 503 Service Unavailable, without
+// calling the handler.
+func (s *Server) Hold(path string) {
+	s.mx.Lock()
+	defer s.mx.Unlock()
+	s.held[path] = true
+	syncbus.Hold(s.bus, HoldKey(path))
+}
+
+// Release lets the paused requests to the path continue, and stops holding the new ones.
+func (s *Server) Release(path string) {
+	s.mx.Lock()
+	defer s.mx.Unlock()
+	delete(s.held, path)
+	syncbus.Release(s.bus, HoldKey(path))
+}
+
+// InFlight returns the number of requests currently being served.
+func (s *Server) InFlight() int {
+	s.mx.Lock()
+	defer s.mx.Unlock()
+	return s.inFlight
+}
+
+// Close releases all the held paths, shuts down the server, and signals ClosedKey.
+func (s *Server) Close() {
+	s.mx.Lock()
+	var held []string
+	for path := range s.held {
+		held = append(held, path)
+	}
+
+	s.mx.Unlock()
+	for _, path := range held {
+		s.Release(path)
+	}
+
+	s.Server.Close()
+	s.signal(ClosedKey)
+}
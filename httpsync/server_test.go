@@ -0,0 +1,115 @@
+package httpsync
+
+import (
+	"io/ioutil"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/aryszka/syncbus"
+)
+
+func get(t *testing.T, url string, done chan<- string) {
+	rsp, err := http.Get(url)
+	if err != nil {
+		t.Error(err)
+		done <- ""
+		return
+	}
+
+	defer rsp.Body.Close()
+	b, err := ioutil.ReadAll(rsp.Body)
+	if err != nil {
+		t.Error(err)
+	}
+
+	done <- string(b)
+}
+
+func TestServer(t *testing.T) {
+	bus := syncbus.New(120 * time.Millisecond)
+	defer bus.Close()
+
+	s := NewServer(bus, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(r.URL.Path))
+	}))
+
+	if err := bus.Wait(ListeningKey, InFlightKey(0)); err != nil {
+		t.Fatal(err)
+	}
+
+	s.Hold("/foo")
+	done := make(chan string, 2)
+	go get(t, s.URL+"/foo", done)
+	go get(t, s.URL+"/foo", done)
+
+	if err := bus.Wait(HoldKey("/foo"), InFlightKey(2)); err != nil {
+		t.Fatal(err)
+	}
+
+	if s.InFlight() != 2 {
+		t.Error("invalid in-flight count", s.InFlight())
+	}
+
+	go get(t, s.URL+"/bar", done)
+	if body := <-done; body != "/bar" {
+		t.Error("invalid response", body)
+	}
+
+	s.Release("/foo")
+	for i := 0; i < 2; i++ {
+		if body := <-done; body != "/foo" {
+			t.Error("invalid response", body)
+		}
+	}
+
+	if err := bus.Wait(InFlightKey(0)); err != nil {
+		t.Error(err)
+	}
+
+	s.Close()
+	if err := bus.Wait(ClosedKey); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestServerCloseHeld(t *testing.T) {
+	bus := syncbus.New(120 * time.Millisecond)
+	defer bus.Close()
+
+	s := NewServer(bus, http.NotFoundHandler())
+	s.Hold("/foo")
+	done := make(chan string, 1)
+	go get(t, s.URL+"/foo", done)
+
+	if err := bus.Wait(HoldKey("/foo")); err != nil {
+		t.Fatal(err)
+	}
+
+	s.Close()
+	<-done
+}
+
+func TestServerHoldTimeout(t *testing.T) {
+	bus := syncbus.New(30 * time.Millisecond)
+	defer bus.Close()
+
+	var called bool
+	s := NewServer(bus, http.HandlerFunc(func(http.ResponseWriter, *http.Request) { called = true }))
+	defer s.Close()
+
+	s.Hold("/foo")
+	rsp, err := http.Get(s.URL + "/foo")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rsp.Body.Close()
+	if rsp.StatusCode != http.StatusServiceUnavailable {
+		t.Error("invalid status", rsp.StatusCode)
+	}
+
+	if called {
+		t.Error("handler called after the checkpoint failed")
+	}
+}
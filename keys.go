@@ -0,0 +1,152 @@
+package syncbus
+
+import (
+	"strconv"
+	"sync"
+)
+
+// maxCachedKeys is the maximum number of the cached integer and byte slice keys, each. The keys beyond it are
+// converted on every call.
+const maxCachedKeys = 1 << 12
+
+// keyTable caches the string form of the integer and byte slice keys, so that the repeated calls with the same
+// keys don't need to convert them. The caches are capped at maxCachedKeys, so that the instrumentation using
+// unbounded key spaces, e.g. request IDs, doesn't grow them without limit.
+type keyTable struct {
+	lock  sync.RWMutex
+	ints  map[int64]string
+	bytes map[string]string
+}
+
+func (t *keyTable) intKey(k int64) string {
+	t.lock.RLock()
+	key, ok := t.ints[k]
+	t.lock.RUnlock()
+	if ok {
+		return key
+	}
+
+	key = strconv.FormatInt(k, 10)
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	if t.ints == nil {
+		t.ints = make(map[int64]string)
+	}
+
+	if len(t.ints) < maxCachedKeys {
+		t.ints[k] = key
+	}
+
+	return key
+}
+
+func (t *keyTable) bytesKey(k []byte) string {
+	t.lock.RLock()
+	key, ok := t.bytes[string(k)]
+	t.lock.RUnlock()
+	if ok {
+		return key
+	}
+
+	key = string(k)
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	if t.bytes == nil {
+		t.bytes = make(map[string]string)
+	}
+
+	if len(t.bytes) < maxCachedKeys {
+		t.bytes[key] = key
+	}
+
+	return key
+}
+
+func (t *keyTable) intKeys(k []int64) []string {
+	keys := make([]string, len(k))
+	for i := range k {
+		keys[i] = t.intKey(k[i])
+	}
+
+	return keys
+}
+
+func (t *keyTable) bytesKeys(k [][]byte) []string {
+	keys := make([]string, len(k))
+	for i := range k {
+		keys[i] = t.bytesKey(k[i])
+	}
+
+	return keys
+}
+
+// SignalInt is like Signal, but it accepts integer keys. An integer key is equivalent to its decimal string
+// form, e.g. SignalInt(42) sets the same signal as Signal("42"). The string form of the keys is cached, up to
+// 4096 distinct keys, so the repeated calls with the same keys don't need to convert them, only the slice
+// holding the converted keys is allocated. It is meant for the instrumentation placed in hot loops.
+//
+// If the receiver *SyncBus is nil, or no key argument is passed to it, it is a noop.
+func (b *SyncBus) SignalInt(keys ...int64) {
+	if b == nil || len(keys) == 0 {
+		return
+	}
+
+	b.Signal(b.keyTable.intKeys(keys)...)
+}
+
+// WaitInt is like Wait, but it accepts integer keys, like SignalInt.
+//
+// If the receiver *SyncBus is nil, or no key argument is passed to it, it is a noop.
+func (b *SyncBus) WaitInt(keys ...int64) error {
+	if b == nil || len(keys) == 0 {
+		return nil
+	}
+
+	return b.Wait(b.keyTable.intKeys(keys)...)
+}
+
+// ResetInt is like ResetSignals, but it accepts integer keys, like SignalInt.
+//
+// If the receiver *SyncBus is nil, or no key argument is passed to it, it is a noop.
+func (b *SyncBus) ResetInt(keys ...int64) {
+	if b == nil || len(keys) == 0 {
+		return
+	}
+
+	b.ResetSignals(b.keyTable.intKeys(keys)...)
+}
+
+// SignalBytes is like Signal, but it accepts byte slice keys. A byte slice key is equivalent to its string form.
+// The string form of the keys is cached, like with SignalInt. It is meant for the instrumentation placed in
+// parsing or networking loops. The byte slices are not retained by the bus.
+//
+// If the receiver *SyncBus is nil, or no key argument is passed to it, it is a noop.
+func (b *SyncBus) SignalBytes(keys ...[]byte) {
+	if b == nil || len(keys) == 0 {
+		return
+	}
+
+	b.Signal(b.keyTable.bytesKeys(keys)...)
+}
+
+// WaitBytes is like Wait, but it accepts byte slice keys, like SignalBytes.
+//
+// If the receiver *SyncBus is nil, or no key argument is passed to it, it is a noop.
+func (b *SyncBus) WaitBytes(keys ...[]byte) error {
+	if b == nil || len(keys) == 0 {
+		return nil
+	}
+
+	return b.Wait(b.keyTable.bytesKeys(keys)...)
+}
+
+// ResetBytes is like ResetSignals, but it accepts byte slice keys, like SignalBytes.
+//
+// If the receiver *SyncBus is nil, or no key argument is passed to it, it is a noop.
+func (b *SyncBus) ResetBytes(keys ...[]byte) {
+	if b == nil || len(keys) == 0 {
+		return
+	}
+
+	b.ResetSignals(b.keyTable.bytesKeys(keys)...)
+}
@@ -0,0 +1,95 @@
+package syncbus
+
+import (
+	"strconv"
+	"testing"
+	"time"
+)
+
+func TestNilIntKeys(t *testing.T) {
+	var bus *SyncBus
+	bus.SignalInt(42)
+	bus.ResetInt(42)
+	if err := bus.WaitInt(42); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestNilBytesKeys(t *testing.T) {
+	var bus *SyncBus
+	bus.SignalBytes([]byte("foo"))
+	bus.ResetBytes([]byte("foo"))
+	if err := bus.WaitBytes([]byte("foo")); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestIntKeys(t *testing.T) {
+	bus := New(120 * time.Millisecond)
+	defer bus.Close()
+
+	bus.SignalInt(42, -1)
+	if err := bus.Wait("42", "-1"); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := bus.WaitInt(42); err != nil {
+		t.Fatal(err)
+	}
+
+	bus.ResetInt(42)
+	if err := bus.WaitTimeout(time.Millisecond, "42"); err != ErrTimeout {
+		t.Error("failed to reset", err)
+	}
+}
+
+func TestBytesKeys(t *testing.T) {
+	bus := New(120 * time.Millisecond)
+	defer bus.Close()
+
+	key := []byte("foo")
+	bus.SignalBytes(key)
+	key[0] = 'b'
+	if err := bus.Wait("foo"); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := bus.WaitBytes([]byte("foo")); err != nil {
+		t.Fatal(err)
+	}
+
+	bus.ResetBytes([]byte("foo"))
+	if err := bus.WaitTimeout(time.Millisecond, "foo"); err != ErrTimeout {
+		t.Error("failed to reset", err)
+	}
+}
+
+func TestKeyTableCache(t *testing.T) {
+	var kt keyTable
+	kt.intKey(42)
+	kt.bytesKey([]byte("foo"))
+	key := []byte("foo")
+	if n := testing.AllocsPerRun(100, func() {
+		kt.intKey(42)
+		kt.bytesKey(key)
+	}); n != 0 {
+		t.Error("unexpected allocations", n)
+	}
+}
+
+func TestKeyTableCap(t *testing.T) {
+	var kt keyTable
+	for i := 0; i < 2*maxCachedKeys; i++ {
+		if key := kt.intKey(int64(i)); key != strconv.Itoa(i) {
+			t.Fatal("invalid key", key)
+		}
+
+		if key := kt.bytesKey([]byte(strconv.Itoa(i))); key != strconv.Itoa(i) {
+			t.Fatal("invalid key", key)
+		}
+	}
+
+	if len(kt.ints) != maxCachedKeys || len(kt.bytes) != maxCachedKeys {
+		t.Error("failed to cap the cache", len(kt.ints), len(kt.bytes))
+	}
+}
@@ -0,0 +1,89 @@
+package syncbus
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+type label struct {
+	name  string
+	value string
+}
+
+// WithLabel tags the wait with a label, e.g. WithLabel("component", "ingress"). A wait can have multiple labels
+// with different names. The labels are displayed by Dump, they are attached to the events of the wait, and the
+// counters of the waits can be queried per label with LabelStats. They allow slicing the diagnostics of large
+// suites by subsystem.
+func WithLabel(name, value string) WaitOpt {
+	return func(w *waitItem) {
+		l := make(map[string]string, len(w.labels)+1)
+		for n, v := range w.labels {
+			l[n] = v
+		}
+
+		l[name] = value
+		w.labels = l
+	}
+}
+
+// countLabels updates the counters of the labels of a wait.
+func (b *SyncBus) countLabels(w waitItem, count func(*counters)) {
+	for name, value := range w.labels {
+		l := label{name: name, value: value}
+		c, ok := b.labeled[l]
+		if !ok {
+			c = &counters{}
+			b.labeled[l] = c
+		}
+
+		count(c)
+	}
+}
+
+func (b *SyncBus) createLabelStats() map[label]Stats {
+	s := make(map[label]Stats)
+	for l, c := range b.labeled {
+		s[l] = Stats{Waits: c.waits, Releases: c.releases, Timeouts: c.timeouts}
+	}
+
+	for _, w := range b.waiting {
+		for name, value := range w.labels {
+			l := label{name: name, value: value}
+			ls := s[l]
+			ls.Waiting++
+			s[l] = ls
+		}
+	}
+
+	return s
+}
+
+func formatLabels(labels map[string]string) string {
+	var l []string
+	for name, value := range labels {
+		l = append(l, fmt.Sprintf("%s=%s", name, value))
+	}
+
+	sort.Strings(l)
+	return strings.Join(l, ", ")
+}
+
+// LabelStats returns the counters of the waits tagged with the label name, by the values of the label. Only the
+// Waiting, Waits, Releases and Timeouts fields of the returned stats are set.
+//
+// If the receiver *SyncBus is nil, it returns nil.
+func (b *SyncBus) LabelStats(name string) map[string]Stats {
+	if b == nil {
+		return nil
+	}
+
+	s := make(map[string]Stats)
+	for l, ls := range b.getSnapshot().labels {
+		if l.name == name {
+			s[l.value] = ls
+		}
+	}
+
+	return s
+}
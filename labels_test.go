@@ -0,0 +1,79 @@
+package syncbus
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestNilLabelStats(t *testing.T) {
+	var bus *SyncBus
+	if s := bus.LabelStats("component"); s != nil {
+		t.Error("unexpected stats", s)
+	}
+}
+
+func TestWithLabel(t *testing.T) {
+	bus := New(12*time.Millisecond, WithHistory())
+	defer bus.Close()
+
+	ingress := []WaitOpt{WithLabel("component", "ingress"), WithLabel("phase", "setup")}
+	errs := make(chan error, 1)
+	go func() { errs <- bus.WaitWith(ingress, "foo") }()
+	for bus.Stats().Waiting != 1 {
+		time.Sleep(time.Millisecond / 10)
+	}
+
+	if d := bus.Dump(); !strings.Contains(d, "labels: component=ingress, phase=setup") {
+		t.Error("failed to display the labels", d)
+	}
+
+	bus.Signal("foo")
+	if err := <-errs; err != nil {
+		t.Fatal(err)
+	}
+
+	if err := bus.WaitWith([]WaitOpt{WithLabel("component", "egress")}, "bar"); err != ErrTimeout {
+		t.Fatal("failed to timeout", err)
+	}
+
+	s := bus.LabelStats("component")
+	if len(s) != 2 || s["ingress"] != (Stats{Waits: 1, Releases: 1}) || s["egress"] != (Stats{Waits: 1, Timeouts: 1}) {
+		t.Error("invalid label stats", s)
+	}
+
+	if s := bus.LabelStats("phase"); len(s) != 1 || s["setup"].Waits != 1 {
+		t.Error("invalid label stats", s)
+	}
+
+	var labeled int
+	for _, e := range bus.History() {
+		if e.Labels["component"] != "" {
+			labeled++
+		}
+	}
+
+	if labeled != 4 {
+		t.Error("invalid number of labeled events", bus.History())
+	}
+}
+
+func TestLabelStatsWaiting(t *testing.T) {
+	bus := New(120 * time.Millisecond)
+	defer bus.Close()
+
+	errs := make(chan error, 1)
+	go func() { errs <- bus.WaitWith([]WaitOpt{WithLabel("component", "ingress")}, "foo") }()
+	for bus.Stats().Waiting != 1 {
+		time.Sleep(time.Millisecond / 10)
+	}
+
+	if s := bus.LabelStats("component"); s["ingress"] != (Stats{Waiting: 1, Waits: 1}) {
+		t.Error("invalid label stats", s)
+	}
+
+	bus.Signal("foo")
+	if err := <-errs; err != nil {
+		t.Error(err)
+	}
+}
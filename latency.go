@@ -0,0 +1,157 @@
+package syncbus
+
+import (
+	"fmt"
+	"time"
+)
+
+// LoopOp identifies an operation of the run loop, whose latency is recorded with WithLoopTracing.
+type LoopOp int
+
+const (
+	// OpWait is the registration of a wait.
+	OpWait LoopOp = iota
+
+	// OpSignal is the processing of a signal.
+	OpSignal
+
+	// OpReset is the processing of a reset, of individual signals, or of all of them.
+	OpReset
+
+	// OpScan is the scan of the pending waits, checking which of them can be released.
+	OpScan
+
+	// OpTimeout is the sweep of the pending waits, checking which of them timed out.
+	OpTimeout
+)
+
+// OpLatency contains the accumulated latency of an operation of the run loop.
+type OpLatency struct {
+
+	// Count is the number of times the operation was executed.
+	Count uint64
+
+	// Total is the total time spent executing the operation.
+	Total time.Duration
+
+	// Max is the longest single execution of the operation.
+	Max time.Duration
+}
+
+// SlowOp describes an operation of the run loop that took longer than the threshold set with WithLoopTracing.
+type SlowOp struct {
+
+	// Op is the slow operation.
+	Op LoopOp
+
+	// Duration is how long the operation took.
+	Duration time.Duration
+
+	// Waiting is the number of the pending waits at the end of the operation.
+	Waiting int
+
+	// Time tells when the operation finished.
+	Time time.Time
+}
+
+type loopTracing struct {
+	threshold time.Duration
+	handler   func(SlowOp)
+	latency   map[LoopOp]*OpLatency
+}
+
+// WithLoopTracing makes the bus record how long each operation of its run loop takes, e.g. the processing of
+// the signals, the scan of the pending waits, or the sweep of the timeouts. The recorded latencies are
+// returned by LoopLatency. With a large number of pending waits, the scans can become a hidden bottleneck, as
+// their cost is proportional to the number of the waits and their keys. When threshold is positive, and h is
+// not nil, h is called with every operation that took at least threshold. The handler is called synchronously
+// by the run loop of the bus, and it must not call the methods of the bus.
+func WithLoopTracing(threshold time.Duration, h func(SlowOp)) Option {
+	return func(o *options) {
+		o.loopTracing = true
+		o.slowThreshold = threshold
+		o.slowHandler = h
+	}
+}
+
+func newLoopTracing(o options) *loopTracing {
+	if !o.loopTracing {
+		return nil
+	}
+
+	return &loopTracing{
+		threshold: o.slowThreshold,
+		handler:   o.slowHandler,
+		latency:   make(map[LoopOp]*OpLatency),
+	}
+}
+
+func (op LoopOp) String() string {
+	switch op {
+	case OpWait:
+		return "wait"
+	case OpSignal:
+		return "signal"
+	case OpReset:
+		return "reset"
+	case OpScan:
+		return "scan"
+	case OpTimeout:
+		return "timeout"
+	default:
+		return fmt.Sprintf("op(%d)", int(op))
+	}
+}
+
+func (s SlowOp) String() string {
+	return fmt.Sprintf("slow %v: %v with %d waiting", s.Op, s.Duration, s.Waiting)
+}
+
+// traceOp records the latency of an operation of the run loop, started at start. It is called only when the
+// tracing is enabled.
+func (b *SyncBus) traceOp(op LoopOp, start time.Time) {
+	now := time.Now()
+	d := now.Sub(start)
+	l, ok := b.tracing.latency[op]
+	if !ok {
+		l = &OpLatency{}
+		b.tracing.latency[op] = l
+	}
+
+	l.Count++
+	l.Total += d
+	if d > l.Max {
+		l.Max = d
+	}
+
+	if b.tracing.threshold <= 0 || b.tracing.handler == nil || d < b.tracing.threshold {
+		return
+	}
+
+	b.tracing.handler(SlowOp{Op: op, Duration: d, Waiting: len(b.waiting), Time: now})
+}
+
+func (b *SyncBus) createLatency() map[LoopOp]OpLatency {
+	if b.tracing == nil {
+		return nil
+	}
+
+	l := make(map[LoopOp]OpLatency)
+	for op, li := range b.tracing.latency {
+		l[op] = *li
+	}
+
+	return l
+}
+
+// LoopLatency returns the latencies of the operations of the run loop, recorded with WithLoopTracing. Without
+// the tracing, it returns nil.
+//
+// If the receiver *SyncBus is nil, it returns nil.
+func (b *SyncBus) LoopLatency() map[LoopOp]OpLatency {
+	if b == nil {
+		return nil
+	}
+
+	return b.getSnapshot().latency
+}
@@ -0,0 +1,53 @@
+package syncbus
+
+import (
+	"testing"
+	"time"
+)
+
+func TestLoopLatencyDisabled(t *testing.T) {
+	var bus *SyncBus
+	if l := bus.LoopLatency(); l != nil {
+		t.Error("unexpected latency", l)
+	}
+
+	bus = New(time.Second)
+	defer bus.Close()
+	bus.Signal("foo")
+	if l := bus.LoopLatency(); l != nil {
+		t.Error("unexpected latency", l)
+	}
+}
+
+func TestLoopLatency(t *testing.T) {
+	bus := New(3*time.Millisecond, WithLoopTracing(0, nil))
+	defer bus.Close()
+
+	bus.Signal("foo")
+	bus.Wait("foo")
+	bus.Wait("bar")
+	bus.ResetSignals("foo")
+	l := bus.LoopLatency()
+	for _, op := range []LoopOp{OpWait, OpSignal, OpReset, OpScan, OpTimeout} {
+		if l[op].Count == 0 || l[op].Max > l[op].Total {
+			t.Error("invalid latency", op, l[op])
+		}
+	}
+}
+
+func TestSlowLoopOp(t *testing.T) {
+	var slow []SlowOp
+	bus := New(time.Second, WithLoopTracing(time.Nanosecond, func(s SlowOp) {
+		slow = append(slow, s)
+	}))
+
+	bus.Signal("foo")
+	bus.Close()
+	if len(slow) == 0 || slow[0].Op != OpSignal {
+		t.Fatal("failed to report slow operation", slow)
+	}
+
+	if s := slow[0].String(); s == "" {
+		t.Error("empty string")
+	}
+}
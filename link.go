@@ -0,0 +1,218 @@
+package syncbus
+
+import (
+	"errors"
+	"math/rand"
+	"sync"
+	"time"
+)
+
+// ErrPartitioned is returned by the Wait calls of a LinkBus, while the link is partitioned.
+var ErrPartitioned = errors.New("link partitioned")
+
+// LinkBus is a decorator that simulates the network link to a bridged bus, typically to a bus in another
+// process, accessed through a wiresync.Client. It can simulate network partitions, and delayed and reordered
+// delivery of the signals and the resets, so that distributed system tests can verify the behavior of the
+// tested code under coordination channel failures. Initially, the link is healthy, and it delegates the calls
+// to the wrapped bus directly.
+type LinkBus struct {
+	bus         Bus
+	mx          sync.Mutex
+	partitioned bool
+	minDelay    time.Duration
+	maxDelay    time.Duration
+	rand        *rand.Rand
+	inFlight    sync.WaitGroup
+	dropped     int
+	closed      bool
+}
+
+// Link creates a link to the bridged bus b.
+func Link(b Bus) *LinkBus {
+	return &LinkBus{bus: b}
+}
+
+// Partition cuts the link. While partitioned, the signals and the resets sent over the link are lost,
+// including the delayed ones arriving during the partition, and Wait returns ErrPartitioned. The waits that
+// were already pending on the bridged bus keep waiting.
+//
+// If the receiver is nil, it is a noop.
+func (l *LinkBus) Partition() {
+	if l == nil {
+		return
+	}
+
+	l.mx.Lock()
+	defer l.mx.Unlock()
+	l.partitioned = true
+}
+
+// Delay makes the link deliver every signal and reset after a random delay between min and max, chosen by a
+// source initialized with seed. When the delays of consecutive calls differ, they arrive in a different order
+// than they were sent. A zero max turns off the delay.
+//
+// If the receiver is nil, it is a noop.
+func (l *LinkBus) Delay(min, max time.Duration, seed int64) {
+	if l == nil {
+		return
+	}
+
+	if max < min {
+		max = min
+	}
+
+	l.mx.Lock()
+	defer l.mx.Unlock()
+	l.minDelay, l.maxDelay = min, max
+	l.rand = rand.New(rand.NewSource(seed))
+}
+
+// Heal restores the link: it ends the partition, and turns off the delay for the subsequent calls. The
+// signals and the resets that are already in flight are still delivered after their delay.
+//
+// If the receiver is nil, it is a noop.
+func (l *LinkBus) Heal() {
+	if l == nil {
+		return
+	}
+
+	l.mx.Lock()
+	defer l.mx.Unlock()
+	l.partitioned = false
+	l.minDelay, l.maxDelay = 0, 0
+}
+
+// Flush blocks until all the delayed signals and resets in flight are delivered or lost.
+//
+// If the receiver is nil, it is a noop.
+func (l *LinkBus) Flush() {
+	if l == nil {
+		return
+	}
+
+	l.inFlight.Wait()
+}
+
+// Dropped returns the number of the signal and reset calls lost due to partitions.
+//
+// If the receiver is nil, it returns zero.
+func (l *LinkBus) Dropped() int {
+	if l == nil {
+		return 0
+	}
+
+	l.mx.Lock()
+	defer l.mx.Unlock()
+	return l.dropped
+}
+
+// deliverable tells whether a call can be delivered to the bridged bus, and counts it as dropped, when it
+// can't. It expects the link to be locked.
+func (l *LinkBus) deliverable() bool {
+	if l.closed {
+		return false
+	}
+
+	if l.partitioned {
+		l.dropped++
+		return false
+	}
+
+	return true
+}
+
+// send delivers a call to the bridged bus, directly, or after the configured delay.
+func (l *LinkBus) send(f func()) {
+	if l == nil || l.bus == nil {
+		return
+	}
+
+	l.mx.Lock()
+	if !l.deliverable() {
+		l.mx.Unlock()
+		return
+	}
+
+	if l.maxDelay <= 0 {
+		l.mx.Unlock()
+		f()
+		return
+	}
+
+	d := l.minDelay
+	if l.maxDelay > l.minDelay {
+		d += time.Duration(l.rand.Int63n(int64(l.maxDelay - l.minDelay)))
+	}
+
+	l.inFlight.Add(1)
+	l.mx.Unlock()
+	time.AfterFunc(d, func() {
+		defer l.inFlight.Done()
+		l.mx.Lock()
+		ok := l.deliverable()
+		l.mx.Unlock()
+		if ok {
+			f()
+		}
+	})
+}
+
+// Wait blocks until all the signals represented by the keys are set on the bridged bus, or the timeout of the
+// bridged bus expires. While the link is partitioned, it returns ErrPartitioned.
+//
+// If the receiver or the wrapped bus is nil, or no key argument is passed to it, it is a noop.
+func (l *LinkBus) Wait(keys ...string) error {
+	if l == nil || l.bus == nil || len(keys) == 0 {
+		return nil
+	}
+
+	l.mx.Lock()
+	partitioned := l.partitioned
+	l.mx.Unlock()
+	if partitioned {
+		return ErrPartitioned
+	}
+
+	return l.bus.Wait(keys...)
+}
+
+// Signal sets the signals represented by the keys on the bridged bus, subject to the simulated conditions of
+// the link.
+//
+// If the receiver or the wrapped bus is nil, or no key argument is passed to it, it is a noop.
+func (l *LinkBus) Signal(keys ...string) {
+	if len(keys) > 0 {
+		l.send(func() { l.bus.Signal(keys...) })
+	}
+}
+
+// ResetSignals clears the signals represented by the keys on the bridged bus, subject to the simulated
+// conditions of the link.
+//
+// If the receiver or the wrapped bus is nil, or no key argument is passed to it, it is a noop.
+func (l *LinkBus) ResetSignals(keys ...string) {
+	if len(keys) > 0 {
+		l.send(func() { l.bus.ResetSignals(keys...) })
+	}
+}
+
+// Reset clears all the signals of the bridged bus, subject to the simulated conditions of the link.
+//
+// If the receiver or the wrapped bus is nil, it is a noop.
+func (l *LinkBus) Reset() {
+	l.send(func() { l.bus.Reset() })
+}
+
+// Close drops the signals and the resets in flight, and tears down the wrapped bus.
+//
+// If the receiver or the wrapped bus is nil, it is a noop.
+func (l *LinkBus) Close() {
+	if l == nil || l.bus == nil {
+		return
+	}
+
+	l.mx.Lock()
+	l.closed = true
+	l.mx.Unlock()
+	l.bus.Close()
+}
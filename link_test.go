@@ -0,0 +1,107 @@
+package syncbus
+
+import (
+	"testing"
+	"time"
+)
+
+func TestNilLink(t *testing.T) {
+	var l *LinkBus
+	l.Partition()
+	l.Delay(time.Millisecond, 2*time.Millisecond, 42)
+	l.Heal()
+	l.Signal("foo")
+	l.ResetSignals("foo")
+	l.Reset()
+	l.Flush()
+	if err := l.Wait("foo"); err != nil || l.Dropped() != 0 {
+		t.Error("unexpected result", err)
+	}
+
+	l.Close()
+	Link(nil).Signal("foo")
+}
+
+func TestLinkPartition(t *testing.T) {
+	bus := New(3 * time.Millisecond)
+	defer bus.Close()
+
+	var _ Bus = Link(bus)
+	l := Link(bus)
+	l.Partition()
+	l.Signal("foo")
+	if err := l.Wait("foo"); err != ErrPartitioned {
+		t.Fatal("failed to fail", err)
+	}
+
+	if err := bus.Wait("foo"); err != ErrTimeout {
+		t.Fatal("signal crossed the partition", err)
+	}
+
+	l.Heal()
+	l.Signal("foo")
+	if err := l.Wait("foo"); err != nil {
+		t.Fatal(err)
+	}
+
+	if n := l.Dropped(); n != 1 {
+		t.Error("invalid number of dropped calls", n)
+	}
+}
+
+func TestLinkDelay(t *testing.T) {
+	bus := New(120 * time.Millisecond)
+	defer bus.Close()
+
+	l := Link(bus)
+	l.Delay(15*time.Millisecond, 15*time.Millisecond, 42)
+	start := time.Now()
+	l.Signal("foo")
+	if err := bus.Wait("foo"); err != nil {
+		t.Fatal(err)
+	}
+
+	if d := time.Since(start); d < 15*time.Millisecond {
+		t.Error("failed to delay the signal", d)
+	}
+}
+
+func TestLinkReorder(t *testing.T) {
+	bus := New(3 * time.Millisecond)
+	defer bus.Close()
+
+	// with some of the seeds, the reset overtakes the signal, and the signal stays set:
+	l := Link(bus)
+	for seed := int64(0); ; seed++ {
+		l.Delay(0, 30*time.Millisecond, seed)
+		l.Signal("foo")
+		l.ResetSignals("foo")
+		l.Flush()
+		if bus.Wait("foo") == nil {
+			break
+		}
+
+		if seed == 100 {
+			t.Fatal("failed to reorder")
+		}
+	}
+}
+
+func TestLinkPartitionInFlight(t *testing.T) {
+	bus := New(3 * time.Millisecond)
+	defer bus.Close()
+
+	l := Link(bus)
+	l.Delay(15*time.Millisecond, 15*time.Millisecond, 42)
+	l.Signal("foo")
+	l.Partition()
+	l.Flush()
+	l.Heal()
+	if err := bus.Wait("foo"); err != ErrTimeout {
+		t.Error("signal in flight crossed the partition", err)
+	}
+
+	if n := l.Dropped(); n != 1 {
+		t.Error("invalid number of dropped calls", n)
+	}
+}
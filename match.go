@@ -0,0 +1,37 @@
+package syncbus
+
+import "path"
+
+// countMatching returns the number of the set signals whose keys match the pattern.
+func (b *SyncBus) countMatching(pattern string) int {
+	var n int
+	for _, key := range b.signals.list() {
+		if m, _ := path.Match(pattern, key); m {
+			n++
+		}
+	}
+
+	return n
+}
+
+// WaitAllMatching blocks until at least minCount signals are set, whose keys match the pattern, or returns an
+// ErrTimeout if it doesn't happen within the timeout of the bus. The pattern syntax is the same as of
+// path.Match, e.g. "worker.*.ready". The condition is re-evaluated whenever signals are set or reset, so it
+// can be used when the number of the workers or connections is not known at the time of registering the wait.
+// When the pattern is malformed, it returns path.ErrBadPattern.
+//
+// If the receiver *SyncBus is nil, or minCount is not positive, it is a noop.
+func (b *SyncBus) WaitAllMatching(pattern string, minCount int) error {
+	if b == nil || minCount <= 0 {
+		return nil
+	}
+
+	if _, err := path.Match(pattern, ""); err != nil {
+		return err
+	}
+
+	return b.waitItem(waitItem{
+		keys:      []string{pattern},
+		predicate: func(s State) bool { return s.bus.countMatching(pattern) >= minCount },
+	})
+}
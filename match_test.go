@@ -0,0 +1,61 @@
+package syncbus
+
+import (
+	"fmt"
+	"path"
+	"testing"
+	"time"
+)
+
+func TestNilWaitAllMatching(t *testing.T) {
+	var bus *SyncBus
+	if err := bus.WaitAllMatching("worker.*", 3); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestWaitAllMatchingBadPattern(t *testing.T) {
+	bus := New(120 * time.Millisecond)
+	defer bus.Close()
+	if err := bus.WaitAllMatching("[", 1); err != path.ErrBadPattern {
+		t.Error("failed to fail", err)
+	}
+}
+
+func TestWaitAllMatching(t *testing.T) {
+	bus := New(120 * time.Millisecond)
+	defer bus.Close()
+
+	bus.Signal("worker.0.ready", "other.1.ready")
+	errs := make(chan error, 1)
+	go func() { errs <- bus.WaitAllMatching("worker.*.ready", 3) }()
+	for bus.Stats().Waiting != 1 {
+		time.Sleep(time.Millisecond / 10)
+	}
+
+	bus.Signal("worker.1.ready")
+	bus.ResetSignals("worker.0.ready")
+	for i := 2; i < 4; i++ {
+		select {
+		case err := <-errs:
+			t.Fatal("released too early", err)
+		default:
+		}
+
+		bus.Signal(fmt.Sprintf("worker.%d.ready", i))
+	}
+
+	if err := <-errs; err != nil {
+		t.Error(err)
+	}
+}
+
+func TestWaitAllMatchingTimeout(t *testing.T) {
+	bus := New(3 * time.Millisecond)
+	defer bus.Close()
+
+	bus.Signal("worker.0.ready")
+	if err := bus.WaitAllMatching("worker.*.ready", 2); err != ErrTimeout {
+		t.Error("failed to timeout", err)
+	}
+}
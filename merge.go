@@ -0,0 +1,77 @@
+package syncbus
+
+import (
+	"encoding/json"
+	"io"
+	"sort"
+)
+
+// TraceEvent is an event of a merged trace, labeled with the bus that it originates from.
+type TraceEvent struct {
+	Event
+
+	// Origin is the label of the bus that recorded the event.
+	Origin string
+}
+
+// Trace is a time-ordered sequence of events recorded by one or more buses, created by MergeTraces.
+type Trace []TraceEvent
+
+// MergeTraces merges the traces recorded by several buses, local and remote, into a single time-ordered trace,
+// where each event is labeled with the origin of the trace that it comes from. The traces are passed mapped
+// by the origin labels, e.g. the history of a local bus, returned by History, and the trace of a remote bus,
+// read by ReadTrace. The events of the same origin keep their recorded order, even when their timestamps are
+// not monotonic, so the causality observed by each bus is preserved. The events with equal timestamps are
+// ordered by the origin labels. It gives distributed test harnesses a single timeline of the whole system.
+func MergeTraces(traces map[string][]Event) Trace {
+	var (
+		origins []string
+		n       int
+	)
+
+	for origin, events := range traces {
+		origins = append(origins, origin)
+		n += len(events)
+	}
+
+	sort.Strings(origins)
+	next := make([]int, len(origins))
+	t := make(Trace, 0, n)
+	for len(t) < n {
+		pick := -1
+		for i, origin := range origins {
+			if next[i] == len(traces[origin]) {
+				continue
+			}
+
+			if pick < 0 || traces[origin][next[i]].Time.Before(traces[origins[pick]][next[pick]].Time) {
+				pick = i
+			}
+		}
+
+		origin := origins[pick]
+		t = append(t, TraceEvent{Event: traces[origin][next[pick]], Origin: origin})
+		next[pick]++
+	}
+
+	return t
+}
+
+// WriteJSON writes the trace to w in the same format as SyncBus.WriteJSON, extended with the field origin.
+func (t Trace) WriteJSON(w io.Writer) error {
+	j := make([]eventJSON, 0, len(t))
+	for _, e := range t {
+		ej := newEventJSON(e.Event)
+		ej.Origin = e.Origin
+		j = append(j, ej)
+	}
+
+	return json.NewEncoder(w).Encode(j)
+}
+
+// CompareTrace compares the trace to a golden trace, written earlier by Trace.WriteJSON, like
+// SyncBus.CompareTrace. Besides the type, the keys, the metadata and the labels of the events, it compares
+// their origin, too.
+func (t Trace) CompareTrace(golden io.Reader, opts ...TraceOpt) error {
+	return compareTrace(golden, t, opts)
+}
@@ -0,0 +1,71 @@
+package syncbus
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestMergeTracesEmpty(t *testing.T) {
+	if tr := MergeTraces(nil); len(tr) != 0 {
+		t.Error("unexpected events", tr)
+	}
+}
+
+func TestMergeTraces(t *testing.T) {
+	t0 := time.Now()
+	at := func(d time.Duration, key string) Event {
+		return Event{Type: EventSignal, Keys: []string{key}, Time: t0.Add(d)}
+	}
+
+	tr := MergeTraces(map[string][]Event{
+		"remote": {at(1, "r1"), at(3, "r2"), at(2, "r3")},
+		"local":  {at(0, "l1"), at(3, "l2"), at(4, "l3")},
+	})
+
+	expected := []string{"local/l1", "remote/r1", "local/l2", "remote/r2", "remote/r3", "local/l3"}
+	if len(tr) != len(expected) {
+		t.Fatal("invalid number of events", len(tr))
+	}
+
+	for i, e := range tr {
+		if got := e.Origin + "/" + e.Keys[0]; got != expected[i] {
+			t.Errorf("invalid event at %d, expected: %s, got: %s", i, expected[i], got)
+		}
+	}
+}
+
+func TestMergedTraceGolden(t *testing.T) {
+	record := func() Trace {
+		local := New(3*time.Millisecond, WithHistory())
+		defer local.Close()
+		remote := New(3*time.Millisecond, WithHistory())
+		defer remote.Close()
+
+		local.Signal("foo")
+		remote.Signal("bar")
+		return MergeTraces(map[string][]Event{"local": local.History(), "remote": remote.History()})
+	}
+
+	var golden bytes.Buffer
+	if err := record().WriteJSON(&golden); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := record().CompareTrace(bytes.NewReader(golden.Bytes())); err != nil {
+		t.Fatal(err)
+	}
+
+	tr := record()
+	tr[1].Origin = "other"
+	err := tr.CompareTrace(bytes.NewReader(golden.Bytes()))
+	var terr *TraceError
+	if !errors.As(err, &terr) || len(terr.Diffs) != 1 {
+		t.Fatal("failed to detect the origin mismatch", err)
+	}
+
+	if m := terr.Diffs[0].Message; m != "expected remote: signal bar, got other: signal bar" {
+		t.Error("invalid difference", m)
+	}
+}
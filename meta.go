@@ -0,0 +1,27 @@
+package syncbus
+
+// SignalMeta is like Signal, but it attaches metadata to the signal, e.g. the originating component or a
+// request ID. The metadata is preserved in the events and in the history, and it is reported by Watch. The map
+// is copied, so it can be reused by the caller.
+//
+// If the receiver *SyncBus is nil, or no key argument is passed to it, it is a noop.
+func (b *SyncBus) SignalMeta(meta map[string]string, keys ...string) {
+	if b == nil || len(keys) == 0 {
+		return
+	}
+
+	b.sendSignal(signalItem{keys: keys, meta: copyMeta(meta)})
+}
+
+func copyMeta(meta map[string]string) map[string]string {
+	if len(meta) == 0 {
+		return nil
+	}
+
+	c := make(map[string]string, len(meta))
+	for k, v := range meta {
+		c[k] = v
+	}
+
+	return c
+}
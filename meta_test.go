@@ -0,0 +1,62 @@
+package syncbus
+
+import (
+	"testing"
+	"time"
+)
+
+func TestNilSignalMeta(t *testing.T) {
+	var bus *SyncBus
+	bus.SignalMeta(map[string]string{"origin": "test"}, "foo")
+}
+
+func TestSignalMetaHistory(t *testing.T) {
+	bus := New(120*time.Millisecond, WithHistory())
+	defer bus.Close()
+
+	meta := map[string]string{"origin": "test", "request": "42"}
+	bus.SignalMeta(meta, "foo")
+	meta["origin"] = "changed"
+	if err := bus.Wait("foo"); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, e := range bus.History() {
+		if e.Type != EventSignal {
+			continue
+		}
+
+		if e.Meta["origin"] != "test" || e.Meta["request"] != "42" {
+			t.Error("invalid metadata", e.Meta)
+		}
+
+		return
+	}
+
+	t.Error("signal event not found")
+}
+
+func TestSignalMetaWatch(t *testing.T) {
+	bus := New(120 * time.Millisecond)
+	c := bus.Watch("foo")
+	bus.SignalMeta(map[string]string{"origin": "test"}, "foo")
+	bus.Signal("foo")
+	bus.Close()
+
+	var changes []Change
+	for ch := range c {
+		changes = append(changes, ch)
+	}
+
+	if len(changes) != 2 {
+		t.Fatal("invalid number of changes", changes)
+	}
+
+	if changes[0].Meta["origin"] != "test" {
+		t.Error("invalid metadata", changes[0].Meta)
+	}
+
+	if changes[1].Meta != nil {
+		t.Error("unexpected metadata", changes[1].Meta)
+	}
+}
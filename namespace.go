@@ -0,0 +1,130 @@
+package syncbus
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Namespace is a view of a SyncBus that associates the signals set through it with a name, typically the
+// name of the test using it. When the bus is created with WithLeakageGuard, a Wait called through a namespace
+// fails fast if any of its keys was set through a different namespace. Signals set directly on the bus, outside
+// of any namespace, are considered shared, and they can satisfy the waits in every namespace.
+type Namespace struct {
+	bus  *SyncBus
+	name string
+}
+
+// LeakError is returned by Wait when it would be satisfied by a signal set through a different namespace.
+type LeakError struct {
+
+	// Key is the key of the leaked signal.
+	Key string
+
+	// Namespace is the name of the namespace in which Wait was called.
+	Namespace string
+
+	// Source is the name of the namespace that set the signal.
+	Source string
+}
+
+// ErrLeak is the error that LeakError unwraps to.
+var ErrLeak = errors.New("signal leaked from another namespace")
+
+func (err *LeakError) Error() string {
+	return fmt.Sprintf(
+		"%v: key %q waited in %q was set in %q",
+		ErrLeak,
+		err.Key,
+		err.Namespace,
+		err.Source,
+	)
+}
+
+// Unwrap returns ErrLeak.
+func (err *LeakError) Unwrap() error {
+	return ErrLeak
+}
+
+func (b *SyncBus) checkLeak(w waitItem) error {
+	if !b.options.leakageGuard || w.namespace == "" {
+		return nil
+	}
+
+	for _, key := range w.keys {
+		if !b.signals.has(key) {
+			continue
+		}
+
+		if source, ok := b.owners[key]; ok && source != w.namespace {
+			return &LeakError{Key: key, Namespace: w.namespace, Source: source}
+		}
+	}
+
+	return nil
+}
+
+// Namespace returns a view of the bus, that associates the signals set through it with the provided name.
+//
+// If the receiver *SyncBus is nil, the returned namespace is a noop.
+func (b *SyncBus) Namespace(name string) *Namespace {
+	return &Namespace{bus: b, name: name}
+}
+
+// Name returns the name of the namespace.
+func (n *Namespace) Name() string {
+	if n == nil {
+		return ""
+	}
+
+	return n.name
+}
+
+// Wait blocks until all the signals represented by the keys are set, or the timeout expires. When the bus was
+// created with WithLeakageGuard, it returns a *LeakError as soon as any of the keys gets set through a
+// different namespace.
+//
+// If the receiver or the underlying bus is nil, or no key argument is passed to it, it is a noop.
+func (n *Namespace) Wait(keys ...string) error {
+	if n == nil || n.bus == nil || len(keys) == 0 {
+		return nil
+	}
+
+	return n.bus.waitItem(waitItem{keys: keys, namespace: n.name})
+}
+
+// Signal sets one or more signals represented by the keys, and associates them with the namespace.
+//
+// If the receiver or the underlying bus is nil, or no key argument is passed to it, it is a noop.
+func (n *Namespace) Signal(keys ...string) {
+	if n == nil || n.bus == nil || len(keys) == 0 {
+		return
+	}
+
+	n.bus.sendSignal(signalItem{keys: keys, namespace: n.name})
+}
+
+// ResetSignals clears the signals defined by the provided keys, regardless of which namespace set them. The
+// keys declared with OwnedBy are cleared only when the namespace is their owner.
+//
+// If the receiver or the underlying bus is nil, or no key argument is passed to it, it is a noop.
+func (n *Namespace) ResetSignals(keys ...string) {
+	if n == nil || n.bus == nil || len(keys) == 0 {
+		return
+	}
+
+	n.bus.sendReset(resetItem{keys: keys, namespace: n.name})
+}
+
+// Reset clears all the signals that were set through the namespace.
+//
+// If the receiver or the underlying bus is nil, it is a noop.
+func (n *Namespace) Reset() {
+	if n == nil || n.bus == nil {
+		return
+	}
+
+	n.bus.sendReset(resetItem{namespace: n.name, all: true})
+}
+
+// Close is a noop, the namespace doesn't own the underlying bus.
+func (n *Namespace) Close() {}
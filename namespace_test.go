@@ -0,0 +1,118 @@
+package syncbus
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+var _ Bus = (*Namespace)(nil)
+
+func TestNilNamespace(t *testing.T) {
+	var n *Namespace
+	if err := n.Wait("foo"); err != nil {
+		t.Error(err)
+	}
+
+	n.Signal("foo")
+	n.ResetSignals("foo")
+	n.Reset()
+	n.Close()
+	if n.Name() != "" {
+		t.Error("unexpected name")
+	}
+
+	var b *SyncBus
+	n = b.Namespace("test")
+	if err := n.Wait("foo"); err != nil {
+		t.Error(err)
+	}
+
+	n.Signal("foo")
+	n.Reset()
+}
+
+func TestNamespaceWithoutGuard(t *testing.T) {
+	bus := New(12 * time.Millisecond)
+	defer bus.Close()
+
+	bus.Namespace("test1").Signal("foo")
+	if err := bus.Namespace("test2").Wait("foo"); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestNamespaceLeak(t *testing.T) {
+	bus := New(120*time.Millisecond, WithLeakageGuard())
+	defer bus.Close()
+
+	ns1 := bus.Namespace("test1")
+	ns2 := bus.Namespace("test2")
+
+	ns1.Signal("foo")
+	err := ns2.Wait("foo", "bar")
+	var lerr *LeakError
+	if !errors.As(err, &lerr) || !errors.Is(err, ErrLeak) {
+		t.Fatal("failed to detect leak", err)
+	}
+
+	if lerr.Key != "foo" || lerr.Namespace != "test2" || lerr.Source != "test1" {
+		t.Error("invalid leak error", lerr)
+	}
+
+	if err := ns1.Wait("foo"); err != nil {
+		t.Error(err)
+	}
+
+	if err := bus.Wait("foo"); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestNamespaceLeakWhileWaiting(t *testing.T) {
+	bus := New(120*time.Millisecond, WithLeakageGuard())
+	defer bus.Close()
+
+	done := make(chan error)
+	go func() { done <- bus.Namespace("test2").Wait("foo") }()
+
+	bus.Namespace("test1").Signal("foo")
+	if err := <-done; !errors.Is(err, ErrLeak) {
+		t.Error("failed to detect leak", err)
+	}
+}
+
+func TestNamespaceSharedSignal(t *testing.T) {
+	bus := New(120*time.Millisecond, WithLeakageGuard())
+	defer bus.Close()
+
+	bus.Namespace("test1").Signal("foo")
+	bus.Signal("foo")
+	if err := bus.Namespace("test2").Wait("foo"); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestNamespaceReset(t *testing.T) {
+	bus := New(12*time.Millisecond, WithLeakageGuard())
+	defer bus.Close()
+
+	ns := bus.Namespace("test")
+	ns.Signal("foo")
+	bus.Signal("bar")
+	ns.Reset()
+
+	if err := ns.Wait("bar"); err != nil {
+		t.Error(err)
+	}
+
+	if err := ns.Wait("foo"); err != ErrTimeout {
+		t.Error("failed to reset")
+	}
+
+	ns.Signal("foo")
+	ns.ResetSignals("foo")
+	if err := ns.Wait("foo"); err != ErrTimeout {
+		t.Error("failed to reset")
+	}
+}
@@ -0,0 +1,176 @@
+/*
+Package netsync provides net.Conn and net.Listener wrappers that report the connection events through a
+syncbus.Bus, and can hold reads or writes until the test releases them.
+*/
+package netsync
+
+import (
+	"net"
+	"strconv"
+	"sync"
+	"sync/atomic"
+
+	"github.com/aryszka/syncbus"
+)
+
+// Events reported by the wrappers.
+const (
+	Accept = "accept"
+	Read   = "read"
+	Write  = "write"
+	Close  = "close"
+)
+
+// Options configure the wrappers.
+type Options struct {
+
+	// Bus receives the signals. When nil, the wrappers only delegate.
+	Bus syncbus.Bus
+
+	// Name is used as the prefix of the keys.
+	Name string
+
+	// HoldReads makes the reads block initially, until they are released.
+	HoldReads bool
+
+	// HoldWrites makes the writes block initially, until they are released.
+	HoldWrites bool
+}
+
+// Conn wraps a net.Conn. It signals the read and write events after each successful read or write, and the
+// close event when it was closed. When reads or writes are held, they block before calling the underlying
+// connection, at the checkpoint of the read or write event, see CheckpointKey, until they get released.
+type Conn struct {
+	net.Conn
+	options    Options
+	mx         sync.Mutex
+	holdReads  bool
+	holdWrites bool
+}
+
+// Listener wraps a net.Listener, and signals the accept event for every accepted connection. The accepted
+// connections are wrapped with the same options, except for their name, that is suffixed with the index of the
+// connection, starting from 0, e.g. server.0, so that the events and the checkpoints of the connections are
+// independent.
+type Listener struct {
+	net.Listener
+	options Options
+	next    uint64
+}
+
+// Key returns the key signaled when the event happens.
+func (o Options) Key(event string) string {
+	return o.Name + "." + event
+}
+
+// CheckpointKey returns the key of the checkpoint, where the held reads or writes block, signaled when a read
+// or write reaches it. The checkpoint can be released with syncbus.Release, using this key.
+func (o Options) CheckpointKey(event string) string {
+	return o.Key(event) + ".checkpoint"
+}
+
+func (o Options) signal(event string) {
+	if o.Bus != nil {
+		o.Bus.Signal(o.Key(event))
+	}
+}
+
+// WrapConn wraps a connection.
+func WrapConn(c net.Conn, o Options) *Conn {
+	return &Conn{
+		Conn:       c,
+		options:    o,
+		holdReads:  o.HoldReads,
+		holdWrites: o.HoldWrites,
+	}
+}
+
+func (c *Conn) hold(event string, held *bool) {
+	c.mx.Lock()
+	defer c.mx.Unlock()
+	*held = true
+	syncbus.Hold(c.options.Bus, c.options.CheckpointKey(event))
+}
+
+func (c *Conn) release(event string, held *bool) {
+	c.mx.Lock()
+	defer c.mx.Unlock()
+	*held = false
+	syncbus.Release(c.options.Bus, c.options.CheckpointKey(event))
+}
+
+func (c *Conn) checkpoint(event string, held *bool) error {
+	c.mx.Lock()
+	h := *held
+	c.mx.Unlock()
+	if !h {
+		return nil
+	}
+
+	return syncbus.Checkpoint(c.options.Bus, c.options.CheckpointKey(event))
+}
+
+// HoldReads makes the subsequent reads block until ReleaseReads is called.
+func (c *Conn) HoldReads() { c.hold(Read, &c.holdReads) }
+
+// ReleaseReads releases the blocked reads, and stops holding the subsequent ones.
+func (c *Conn) ReleaseReads() { c.release(Read, &c.holdReads) }
+
+// HoldWrites makes the subsequent writes block until ReleaseWrites is called.
+func (c *Conn) HoldWrites() { c.hold(Write, &c.holdWrites) }
+
+// ReleaseWrites releases the blocked writes, and stops holding the subsequent ones.
+func (c *Conn) ReleaseWrites() { c.release(Write, &c.holdWrites) }
+
+// Read reads from the underlying connection.
+func (c *Conn) Read(p []byte) (int, error) {
+	if err := c.checkpoint(Read, &c.holdReads); err != nil {
+		return 0, err
+	}
+
+	n, err := c.Conn.Read(p)
+	if n > 0 {
+		c.options.signal(Read)
+	}
+
+	return n, err
+}
+
+// Write writes to the underlying connection.
+func (c *Conn) Write(p []byte) (int, error) {
+	if err := c.checkpoint(Write, &c.holdWrites); err != nil {
+		return 0, err
+	}
+
+	n, err := c.Conn.Write(p)
+	if n > 0 {
+		c.options.signal(Write)
+	}
+
+	return n, err
+}
+
+// Close closes the underlying connection, and signals the close event.
+func (c *Conn) Close() error {
+	err := c.Conn.Close()
+	c.options.signal(Close)
+	return err
+}
+
+// WrapListener wraps a listener.
+func WrapListener(l net.Listener, o Options) *Listener {
+	return &Listener{Listener: l, options: o}
+}
+
+// Accept accepts a connection from the underlying listener, and wraps it.
+func (l *Listener) Accept() (net.Conn, error) {
+	c, err := l.Listener.Accept()
+	if err != nil {
+		return nil, err
+	}
+
+	l.options.signal(Accept)
+	o := l.options
+	o.Name += "." + strconv.FormatUint(atomic.AddUint64(&l.next, 1)-1, 10)
+	return WrapConn(c, o), nil
+}
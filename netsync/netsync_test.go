@@ -0,0 +1,171 @@
+package netsync
+
+import (
+	"io"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/aryszka/syncbus"
+)
+
+func TestConnNilBus(t *testing.T) {
+	c1, c2 := net.Pipe()
+	c := WrapConn(c1, Options{HoldReads: true})
+	go c2.Write([]byte("foo"))
+	p := make([]byte, 3)
+	if _, err := io.ReadFull(c, p); err != nil || string(p) != "foo" {
+		t.Error("failed to read", err)
+	}
+
+	c.Close()
+	c2.Close()
+}
+
+func TestListener(t *testing.T) {
+	bus := syncbus.New(120 * time.Millisecond)
+	defer bus.Close()
+
+	nl, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	l := WrapListener(nl, Options{Bus: bus, Name: "server", HoldWrites: true})
+	defer l.Close()
+
+	go func() {
+		c, err := l.Accept()
+		if err != nil {
+			t.Error(err)
+			return
+		}
+
+		defer c.Close()
+		p := make([]byte, 3)
+		if _, err := io.ReadFull(c, p); err != nil {
+			t.Error(err)
+			return
+		}
+
+		c.Write(p)
+	}()
+
+	c, err := net.Dial("tcp", nl.Addr().String())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer c.Close()
+	if err := bus.Wait("server.accept"); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := c.Write([]byte("foo")); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := bus.Wait("server.0.read", "server.0.write.checkpoint"); err != nil {
+		t.Fatal(err)
+	}
+
+	syncbus.Release(bus, "server.0.write.checkpoint")
+	p := make([]byte, 3)
+	if _, err := io.ReadFull(c, p); err != nil || string(p) != "foo" {
+		t.Error("failed to read", err)
+	}
+
+	if err := bus.Wait("server.0.write", "server.0.close"); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestConnHoldRelease(t *testing.T) {
+	bus := syncbus.New(120 * time.Millisecond)
+	defer bus.Close()
+
+	c1, c2 := net.Pipe()
+	defer c2.Close()
+
+	c := WrapConn(c1, Options{Bus: bus, Name: "client"})
+	defer c.Close()
+
+	c.HoldReads()
+	done := make(chan error)
+	go func() {
+		p := make([]byte, 3)
+		_, err := io.ReadFull(c, p)
+		done <- err
+	}()
+
+	if err := bus.Wait("client.read.checkpoint"); err != nil {
+		t.Fatal(err)
+	}
+
+	go c2.Write([]byte("foo"))
+	select {
+	case <-done:
+		t.Fatal("failed to hold read")
+	case <-time.After(3 * time.Millisecond):
+	}
+
+	c.ReleaseReads()
+	if err := <-done; err != nil {
+		t.Error(err)
+	}
+
+	c.HoldWrites()
+	c.ReleaseWrites()
+	go io.ReadFull(c2, make([]byte, 3))
+	if _, err := c.Write([]byte("bar")); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestListenerConnsIndependent(t *testing.T) {
+	bus := syncbus.New(120 * time.Millisecond)
+	defer bus.Close()
+
+	nl, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	l := WrapListener(nl, Options{Bus: bus, Name: "server"})
+	defer l.Close()
+
+	for i := 0; i < 2; i++ {
+		c, err := net.Dial("tcp", nl.Addr().String())
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		defer c.Close()
+	}
+
+	var conns []*Conn
+	for i := 0; i < 2; i++ {
+		c, err := l.Accept()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		defer c.Close()
+		conns = append(conns, c.(*Conn))
+	}
+
+	conns[0].HoldWrites()
+	conns[1].HoldWrites()
+	conns[1].ReleaseWrites()
+	if _, err := conns[1].Write([]byte("foo")); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := bus.Wait("server.1.write"); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := bus.Wait("server.0.write.checkpoint.release"); err != syncbus.ErrTimeout {
+		t.Error("released the checkpoint of another connection", err)
+	}
+}
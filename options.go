@@ -0,0 +1,140 @@
+package syncbus
+
+import "time"
+
+type options struct {
+	timeout            time.Duration
+	eventBuffer        int
+	dropPolicy         DropPolicy
+	leakageGuard       bool
+	filePollInterval   time.Duration
+	seed               int64
+	jitter             time.Duration
+	sampling           float64
+	wakeupOrder        WakeupOrder
+	pctDepth           int
+	pctSteps           int
+	recordSchedule     string
+	keepSchedule       bool
+	scheduleDir        string
+	replay             []decision
+	waitGraph          bool
+	waitGraphFile      string
+	historySize        int
+	throttles          map[string]throttle
+	maxWaiting         int
+	maxWaitingPerKey   int
+	violationHandler   func(Violation)
+	repanic            bool
+	closeGuard         bool
+	closeGuardHandler  func(error)
+	vet                bool
+	timeoutBudget      time.Duration
+	gracePeriod        time.Duration
+	progressExtension  time.Duration
+	fastPath           bool
+	readMostly         bool
+	expectedKeys       int
+	expectedWaiters    int
+	signalBuffer       int
+	engine             Engine
+	watchdog           time.Duration
+	watchdogHandler    func(string)
+	generated          bool
+	choices            Schedule
+	simulated          bool
+	settle             time.Duration
+	loopTracing        bool
+	slowThreshold      time.Duration
+	slowHandler        func(SlowOp)
+	timeoutOrder       TimeoutOrder
+	partialResults     bool
+	lateSignalTracking time.Duration
+	errorContext       int
+	attribution        bool
+	raceScale          float64
+	auditLog           bool
+}
+
+// Option can be used to customize a SyncBus when creating it with New.
+type Option func(*options)
+
+// WithTimeout overrides the timeout of the Wait calls. It is useful with the helpers that create the buses
+// themselves, like Stress.
+func WithTimeout(timeout time.Duration) Option {
+	return func(o *options) { o.timeout = timeout }
+}
+
+// WithEventBuffer sets the size of the buffer of the channel returned by Events(). When the buffer is full, the
+// events are dropped according to the drop policy. Defaults to DefaultEventBuffer.
+func WithEventBuffer(size int) Option {
+	return func(o *options) {
+		if size < 0 {
+			size = 0
+		}
+
+		o.eventBuffer = size
+	}
+}
+
+// WithDropPolicy sets which events to drop when the buffer of the event stream is full. Defaults to
+// DropNewest.
+func WithDropPolicy(p DropPolicy) Option {
+	return func(o *options) { o.dropPolicy = p }
+}
+
+// WithLeakageGuard enables the guard mode, in which a Wait called through a Namespace fails fast with a
+// LeakError, when it would be satisfied by a signal set through a different namespace.
+func WithLeakageGuard() Option {
+	return func(o *options) { o.leakageGuard = true }
+}
+
+// WithFilePollInterval sets how often the files watched by SignalOnFile are checked. Defaults to
+// DefaultFilePollInterval.
+func WithFilePollInterval(d time.Duration) Option {
+	return func(o *options) {
+		if d > 0 {
+			o.filePollInterval = d
+		}
+	}
+}
+
+// WithSeed sets the seed used by the randomized behaviors of the bus, e.g. WithJitter, to make the runs
+// reproducible. When not set, the seed is derived from the current time, and it can be retrieved by the Seed
+// method.
+func WithSeed(seed int64) Option {
+	return func(o *options) { o.seed = seed }
+}
+
+// WithJitter enables a stress mode, in which every Wait call sleeps for a random duration, up to max, before
+// registering and before returning after having been released. It widens the explored interleaving space of the
+// tested goroutines, e.g. when running the tests with -count=N.
+func WithJitter(max time.Duration) Option {
+	return func(o *options) { o.jitter = max }
+}
+
+// WithSampling sets the fraction of the Wait calls that actually block, while the rest of them return nil
+// immediately, as if the awaited signals were set. It allows running the same instrumented binary e.g. in load
+// tests with low overhead, while still occasionally exercising the synchronization points. The fraction is
+// expected to be between 0 and 1, where 1, the default, means that every call blocks.
+func WithSampling(fraction float64) Option {
+	return func(o *options) { o.sampling = fraction }
+}
+
+func withShuffledWakeup() Option {
+	return func(o *options) { o.wakeupOrder = WakeupOrder{kind: shuffledWakeup} }
+}
+
+// WithPCT enables probabilistic concurrency testing (PCT) over the operations of the bus. Every wait gets a random
+// priority, and when multiple waits are satisfied at the same time, only the one with the highest priority is released,
+// while the rest of them is released in later passes, one by one, with the result that they had when they were
+// satisfied. At depth-1 randomly chosen points among the first steps operations of the bus, the priority of the highest
+// priority pending wait gets lowered below all the others. Compared to uniform randomization, this makes finding the
+// orderings that depend on a small number of goroutines being scheduled in a specific order more likely. The random
+// choices are controlled by the seed of the bus.
+func WithPCT(depth, steps int) Option {
+	return func(o *options) {
+		o.pctDepth = depth
+		o.pctSteps = steps
+	}
+}
@@ -0,0 +1,114 @@
+package syncbus
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+type orderWait struct {
+	next int
+	err  error
+}
+
+// OrderError is returned by WaitInOrder when a signal arrives before the signals preceding it in the expected
+// order.
+type OrderError struct {
+
+	// Keys contains the keys of the wait, in the expected order.
+	Keys []string
+
+	// Expected is the key that was expected to be signaled next.
+	Expected string
+
+	// Got is the key that was signaled instead.
+	Got string
+}
+
+// ErrOrder is the error that OrderError unwraps to.
+var ErrOrder = errors.New("signals out of order")
+
+func (err *OrderError) Error() string {
+	return fmt.Sprintf(
+		"%v: expected %q, got %q, order: %s",
+		ErrOrder,
+		err.Expected,
+		err.Got,
+		strings.Join(err.Keys, ", "),
+	)
+}
+
+// Unwrap returns ErrOrder.
+func (err *OrderError) Unwrap() error {
+	return ErrOrder
+}
+
+func (w waitItem) orderSignal(key string) {
+	o := w.order
+	if o.err != nil || o.next == len(w.keys) {
+		return
+	}
+
+	if w.keys[o.next] == key {
+		o.next++
+		return
+	}
+
+	for _, k := range w.keys[o.next+1:] {
+		if k == key {
+			o.err = &OrderError{Keys: w.keys, Expected: w.keys[o.next], Got: key}
+			return
+		}
+	}
+}
+
+// initOrder accepts the signals already set at the time of registering an ordered wait, when they form a
+// prefix of the expected order.
+func (b *SyncBus) initOrder(w waitItem) {
+	if w.order == nil {
+		return
+	}
+
+	for _, key := range w.keys {
+		if !b.signals.has(key) {
+			break
+		}
+
+		w.order.next++
+	}
+
+	for _, key := range w.keys[w.order.next:] {
+		if b.signals.has(key) {
+			w.orderSignal(key)
+			return
+		}
+	}
+}
+
+// orderSignals registers the signals for the pending ordered waits, in the order of the keys of the Signal call.
+func (b *SyncBus) orderSignals(keys []string) {
+	for _, w := range b.waiting {
+		if w.order == nil {
+			continue
+		}
+
+		for _, key := range keys {
+			w.orderSignal(key)
+		}
+	}
+}
+
+// WaitInOrder blocks until the signals represented by the keys are set in the listed order, or returns an
+// ErrTimeout if it doesn't happen within the timeout of the bus. When a signal arrives before the ones preceding
+// it in the list, it fails immediately with an OrderError, instead of waiting for the timeout. The signals that
+// were already set at the time of calling WaitInOrder are accepted if they form a prefix of the list. The keys
+// signaled by the same Signal call are considered in the order of its arguments.
+//
+// If the receiver *SyncBus is nil, or no key argument is passed to it, it is a noop.
+func (b *SyncBus) WaitInOrder(keys ...string) error {
+	if b == nil || len(keys) == 0 {
+		return nil
+	}
+
+	return b.waitItem(waitItem{keys: keys, order: &orderWait{}})
+}
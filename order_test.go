@@ -0,0 +1,78 @@
+package syncbus
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestNilWaitInOrder(t *testing.T) {
+	var bus *SyncBus
+	if err := bus.WaitInOrder("foo", "bar"); err != nil {
+		t.Error(err)
+	}
+}
+
+func waitInOrder(bus *SyncBus, keys ...string) <-chan error {
+	errs := make(chan error, 1)
+	go func() { errs <- bus.WaitInOrder(keys...) }()
+	for bus.Stats().Waiting != 1 {
+		time.Sleep(time.Millisecond / 10)
+	}
+
+	return errs
+}
+
+func TestWaitInOrder(t *testing.T) {
+	bus := New(120 * time.Millisecond)
+	defer bus.Close()
+
+	errs := waitInOrder(bus, "foo", "bar", "baz")
+	bus.Signal("foo")
+	bus.Signal("bar", "baz")
+	if err := <-errs; err != nil {
+		t.Error(err)
+	}
+}
+
+func TestWaitInOrderFailsFast(t *testing.T) {
+	bus := New(time.Hour)
+	defer bus.Close()
+
+	errs := waitInOrder(bus, "foo", "bar", "baz")
+	bus.Signal("foo")
+	bus.Signal("baz")
+	err := <-errs
+	var oerr *OrderError
+	if !errors.As(err, &oerr) || !errors.Is(err, ErrOrder) || oerr.Expected != "bar" || oerr.Got != "baz" {
+		t.Error("failed to detect the out of order signal", err)
+	}
+}
+
+func TestWaitInOrderAlreadySet(t *testing.T) {
+	bus := New(120 * time.Millisecond)
+	defer bus.Close()
+
+	bus.Signal("foo")
+	errs := waitInOrder(bus, "foo", "bar")
+	bus.Signal("bar")
+	if err := <-errs; err != nil {
+		t.Error(err)
+	}
+
+	bus.Reset()
+	bus.Signal("bar")
+	if err := bus.WaitInOrder("foo", "bar"); !errors.Is(err, ErrOrder) {
+		t.Error("failed to detect the out of order signal", err)
+	}
+}
+
+func TestWaitInOrderTimeout(t *testing.T) {
+	bus := New(12 * time.Millisecond)
+	defer bus.Close()
+
+	bus.Signal("foo")
+	if err := bus.WaitInOrder("foo", "bar"); err != ErrTimeout {
+		t.Error("failed to timeout", err)
+	}
+}
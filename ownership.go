@@ -0,0 +1,59 @@
+package syncbus
+
+import (
+	"fmt"
+	"sync/atomic"
+	"time"
+)
+
+// OwnedBy declares that the key is owned by the component identified by owner, and only the owner may set or
+// clear its signal, through the namespace with the same name, e.g. bus.Namespace(owner).Signal(key). The Signal
+// and ResetSignals calls from anywhere else are ignored for the key, and reported as violations, carrying the
+// location of the offending call. Reset, clearing all the signals, is not restricted. In large test suites, it
+// catches the unrelated tests setting each other's keys.
+func OwnedBy(owner string) KeyOpt {
+	return func(d *keyDecl) { d.owner = owner }
+}
+
+// declareOwner marks the bus as having owned keys, so that the callers capture their call sites.
+func (b *SyncBus) declareOwner(d keyDecl) {
+	if d.owner != "" {
+		atomic.StoreInt32(&b.ownership, 1)
+	}
+}
+
+func (b *SyncBus) hasOwners() bool {
+	return atomic.LoadInt32(&b.ownership) != 0
+}
+
+// checkOwnership returns those keys that the caller, identified by the namespace, is allowed to set or clear,
+// and reports a violation for the rest.
+func (b *SyncBus) checkOwnership(now time.Time, op string, keys []string, namespace string, c callInfo) []string {
+	if !b.hasOwners() {
+		return keys
+	}
+
+	var allowed []string
+	for _, key := range keys {
+		owner := b.declared[key].owner
+		if owner == "" || owner == namespace {
+			allowed = append(allowed, key)
+			continue
+		}
+
+		caller := "the bus"
+		if namespace != "" {
+			caller = fmt.Sprintf("namespace %q", namespace)
+		}
+
+		b.reportViolation(Violation{
+			Key:     key,
+			Message: fmt.Sprintf("%s by %s at %s, owned by %q", op, caller, c.site, owner),
+			Time:    now,
+			Stack:   c.stack,
+			Site:    c.site,
+		})
+	}
+
+	return allowed
+}
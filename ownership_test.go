@@ -0,0 +1,64 @@
+package syncbus
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestOwnership(t *testing.T) {
+	violations := make(chan Violation, 2)
+	bus := New(3*time.Millisecond, WithViolationHandler(func(v Violation) { violations <- v }))
+	defer bus.Close()
+
+	bus.Declare("db.ready", OwnedBy("db"))
+	bus.Namespace("cache").Signal("db.ready")
+	v := <-violations
+	if v.Key != "db.ready" || !strings.Contains(v.Site, "ownership_test.go") || !strings.Contains(v.Message, `"db"`) {
+		t.Error("invalid violation", v)
+	}
+
+	if err := bus.Wait("db.ready"); err != ErrTimeout {
+		t.Fatal("failed to ignore the signal", err)
+	}
+
+	db := bus.Namespace("db")
+	db.Signal("db.ready")
+	if err := bus.Wait("db.ready"); err != nil {
+		t.Fatal(err)
+	}
+
+	bus.ResetSignals("db.ready")
+	v = <-violations
+	if !strings.Contains(v.Message, "ResetSignals by the bus") {
+		t.Error("invalid violation", v)
+	}
+
+	if err := bus.Wait("db.ready"); err != nil {
+		t.Fatal("failed to ignore the reset", err)
+	}
+
+	db.ResetSignals("db.ready")
+	if err := bus.Wait("db.ready"); err != ErrTimeout {
+		t.Error("failed to reset", err)
+	}
+
+	if n := len(bus.Violations()); n != 2 {
+		t.Error("invalid number of violations", n)
+	}
+}
+
+func TestOwnershipPartial(t *testing.T) {
+	bus := New(3 * time.Millisecond)
+	defer bus.Close()
+
+	bus.Declare("foo", OwnedBy("foo-owner"))
+	bus.Signal("foo", "bar")
+	if err := bus.Wait("bar"); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := bus.Wait("foo"); err != ErrTimeout {
+		t.Error("failed to ignore the signal", err)
+	}
+}
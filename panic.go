@@ -0,0 +1,92 @@
+package syncbus
+
+import (
+	"errors"
+	"fmt"
+	"runtime/debug"
+	"sync/atomic"
+)
+
+// PanicError is returned by Wait when the run loop of the bus stopped due to a panic, e.g. in a callback passed
+// to the bus.
+type PanicError struct {
+
+	// Value is the recovered value of the panic.
+	Value interface{}
+
+	// Stack is the stack trace of the run loop at the time of the panic.
+	Stack []byte
+}
+
+// ErrPanic is the error that PanicError unwraps to.
+var ErrPanic = errors.New("panic in the run loop")
+
+// WithRepanic makes Close panic with the *PanicError, when the run loop of the bus stopped due to a panic.
+func WithRepanic() Option {
+	return func(o *options) { o.repanic = true }
+}
+
+func (err *PanicError) Error() string {
+	return fmt.Sprintf("%v: %v\n%s", ErrPanic, err.Value, err.Stack)
+}
+
+// Unwrap returns ErrPanic.
+func (err *PanicError) Unwrap() error {
+	return ErrPanic
+}
+
+// releaseAll releases all the pending waits with the provided error. It doesn't block on the waits that were
+// already released, in case the run loop panicked during releasing them.
+func (b *SyncBus) releaseAll(err error) {
+	b.releaseHeld()
+	for _, w := range b.waiting {
+		select {
+		case w.signal <- err:
+			w.notify(err)
+		default:
+		}
+	}
+
+	b.waiting = nil
+}
+
+func (b *SyncBus) recoverPanic() {
+	r := recover()
+	if r == nil {
+		return
+	}
+
+	b.panicked = &PanicError{Value: r, Stack: debug.Stack()}
+	b.releaseAll(b.panicked)
+}
+
+func (b *SyncBus) run() {
+	if b.options.vet {
+		atomic.StoreInt64(&b.loopID, goid())
+	}
+
+	defer close(b.done)
+	defer close(b.dispatch)
+	defer close(b.events)
+	defer b.closeWatchers()
+	defer b.recoverPanic()
+	b.loop()
+}
+
+// Panic returns the *PanicError, if the run loop of the bus stopped due to a panic. Otherwise, or if the
+// receiver *SyncBus is nil, it returns nil.
+func (b *SyncBus) Panic() error {
+	if b == nil {
+		return nil
+	}
+
+	select {
+	case <-b.done:
+		if b.panicked != nil {
+			return b.panicked
+		}
+	default:
+	}
+
+	return nil
+}
@@ -0,0 +1,83 @@
+package syncbus
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func panickingBus(opts ...Option) *SyncBus {
+	opts = append(opts, WithViolationHandler(func(Violation) { panic("foo") }))
+	bus := New(120*time.Millisecond, opts...)
+	bus.Declare("bar", MaxWaiters(1))
+	return bus
+}
+
+func TestNilPanic(t *testing.T) {
+	var bus *SyncBus
+	if bus.Panic() != nil {
+		t.Error("unexpected panic")
+	}
+}
+
+func TestPanicReleasesWaits(t *testing.T) {
+	bus := panickingBus()
+	defer bus.Close()
+
+	errs := make(chan error, 2)
+	go func() { errs <- bus.Wait("bar") }()
+	for bus.Stats().Waiting != 1 {
+		time.Sleep(time.Millisecond / 10)
+	}
+
+	go func() { errs <- bus.Wait("bar") }()
+	for i := 0; i < 2; i++ {
+		err := <-errs
+		var perr *PanicError
+		if !errors.As(err, &perr) || !errors.Is(err, ErrPanic) || perr.Value != "foo" || len(perr.Stack) == 0 {
+			t.Error("failed to surface the panic", err)
+		}
+	}
+
+	if !errors.Is(bus.Wait("baz"), ErrPanic) {
+		t.Error("failed to surface the panic after the loop stopped")
+	}
+
+	bus.Signal("baz")
+	if s := bus.Stats(); s != (Stats{}) {
+		t.Error("unexpected stats", s)
+	}
+
+	if !errors.Is(bus.Panic(), ErrPanic) {
+		t.Error("failed to report the panic")
+	}
+}
+
+func TestPanicWithoutRepanic(t *testing.T) {
+	bus := New(120 * time.Millisecond)
+	bus.Close()
+	if bus.Panic() != nil {
+		t.Error("unexpected panic")
+	}
+}
+
+func TestRepanic(t *testing.T) {
+	bus := panickingBus(WithRepanic())
+	errs := make(chan error, 2)
+	go func() { errs <- bus.Wait("bar") }()
+	for bus.Stats().Waiting != 1 {
+		time.Sleep(time.Millisecond / 10)
+	}
+
+	go func() { errs <- bus.Wait("bar") }()
+	<-errs
+	<-errs
+
+	defer func() {
+		if err, ok := recover().(error); !ok || !errors.Is(err, ErrPanic) {
+			t.Error("failed to repanic", err)
+		}
+	}()
+
+	bus.Close()
+}
@@ -0,0 +1,64 @@
+package syncbus
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Arrival tells when the signal of a key, that a timed out wait depended on, was set.
+type Arrival struct {
+
+	// Key is the key of the signal.
+	Key string
+
+	// Time tells when the signal was set.
+	Time time.Time
+}
+
+// WithPartialResults makes the timed out Wait calls return a TimeoutError, that contains which of the awaited
+// keys did arrive, and when, and which of them were missing. A wait for five keys that got four of them tells a
+// different story than one that got none.
+func WithPartialResults() Option {
+	return func(o *options) { o.partialResults = true }
+}
+
+func (b *SyncBus) recordArrival(now time.Time, key string) {
+	if b.options.partialResults || b.options.waitGraph {
+		b.arrivals[key] = now
+	}
+}
+
+// timeoutError creates the TimeoutError of a timed out wait.
+func (b *SyncBus) timeoutError(now time.Time, w waitItem, g *WaitGraph) *TimeoutError {
+	err := &TimeoutError{Keys: w.keys, Graph: g, Time: now}
+	if b.options.errorContext > 0 {
+		err.Recent = b.recent.query(Filter{})
+	}
+
+	for _, key := range w.keys {
+		if !b.signals.has(key) {
+			err.Missing = append(err.Missing, key)
+			continue
+		}
+
+		err.Arrived = append(err.Arrived, Arrival{Key: key, Time: b.arrivals[key]})
+	}
+
+	err.Sources = b.missingSources(err.Missing)
+
+	return err
+}
+
+func (a Arrival) String() string {
+	return fmt.Sprintf("%s at %s", a.Key, a.Time.Format(time.RFC3339Nano))
+}
+
+func formatArrived(a []Arrival) string {
+	s := make([]string, len(a))
+	for i, ai := range a {
+		s[i] = ai.String()
+	}
+
+	return strings.Join(s, ", ")
+}
@@ -0,0 +1,55 @@
+package syncbus
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestPartialResults(t *testing.T) {
+	bus := New(15*time.Millisecond, WithPartialResults())
+	defer bus.Close()
+
+	go func() {
+		time.Sleep(3 * time.Millisecond)
+		bus.Signal("foo", "baz")
+	}()
+
+	err := bus.Wait("foo", "bar", "baz", "qux")
+	var terr *TimeoutError
+	if !errors.As(err, &terr) || !errors.Is(err, ErrTimeout) {
+		t.Fatal("failed to timeout with details", err)
+	}
+
+	if len(terr.Arrived) != 2 || terr.Arrived[0].Key != "foo" || terr.Arrived[1].Key != "baz" {
+		t.Fatal("invalid arrivals", terr.Arrived)
+	}
+
+	if terr.Arrived[0].Time.IsZero() {
+		t.Error("arrival time not set")
+	}
+
+	if len(terr.Missing) != 2 || terr.Missing[0] != "bar" || terr.Missing[1] != "qux" {
+		t.Error("invalid missing keys", terr.Missing)
+	}
+
+	if !strings.Contains(err.Error(), "missing: bar, qux") {
+		t.Error("invalid error message", err)
+	}
+}
+
+func TestPartialResultsNone(t *testing.T) {
+	bus := New(3*time.Millisecond, WithPartialResults())
+	defer bus.Close()
+
+	err := bus.Wait("foo", "bar")
+	var terr *TimeoutError
+	if !errors.As(err, &terr) {
+		t.Fatal("failed to timeout with details", err)
+	}
+
+	if len(terr.Arrived) != 0 || len(terr.Missing) != 2 {
+		t.Error("invalid partial results", terr)
+	}
+}
@@ -0,0 +1,153 @@
+package syncbus
+
+import (
+	"sort"
+	"time"
+)
+
+// pctStepDelay is the time after which the waits held back by the PCT scheduler are reconsidered, when no
+// other operation happens on the bus in the meantime.
+const pctStepDelay = time.Millisecond
+
+type pct struct {
+	rand         *random
+	depth        int64
+	step         int
+	changePoints map[int]int64
+	deferred     bool
+	timer        *time.Timer
+	held         []released
+}
+
+func newPCT(depth, steps int, r *random) *pct {
+	if depth <= 0 {
+		return nil
+	}
+
+	if steps <= 0 {
+		steps = 1
+	}
+
+	p := &pct{
+		rand:         r,
+		depth:        int64(depth),
+		changePoints: make(map[int]int64),
+	}
+
+	for i := 1; i < depth; i++ {
+		p.changePoints[int(r.int63n(int64(steps)))+1] = int64(depth - i)
+	}
+
+	return p
+}
+
+// priority returns a random priority for a new wait. The initial priorities are always higher than the ones
+// assigned at the change points.
+func (p *pct) priority() int64 {
+	if p == nil {
+		return 0
+	}
+
+	return p.depth + p.rand.int63n(1<<62)
+}
+
+func (b *SyncBus) pctStep() {
+	p := b.pct
+	if p == nil {
+		return
+	}
+
+	p.step++
+	lowered, ok := p.changePoints[p.step]
+	if !ok || len(b.waiting)+len(p.held) == 0 {
+		return
+	}
+
+	var highest *int64
+	for i := range b.waiting {
+		if highest == nil || b.waiting[i].priority > *highest {
+			highest = &b.waiting[i].priority
+		}
+	}
+
+	for i := range p.held {
+		if highest == nil || p.held[i].item.priority > *highest {
+			highest = &p.held[i].item.priority
+		}
+	}
+
+	*highest = lowered
+}
+
+// pctRelease releases the waits in the order of their priorities: the failed ones, and the satisfied one with
+// the highest priority are released immediately, while the rest of the satisfied ones are held back, and
+// released one by one in the subsequent steps. The held back waits keep the result recorded when they were
+// satisfied, so the PCT scheduler changes only the order of the releases, and a reset can't fail them.
+func (b *SyncBus) pctRelease(r []released) []released {
+	p := b.pct
+	r = append(p.held, r...)
+	p.held = nil
+	sort.SliceStable(r, func(i, j int) bool { return r[i].item.priority > r[j].item.priority })
+
+	var (
+		release []released
+		first   = true
+	)
+
+	for _, ri := range r {
+		if ri.err != nil || first {
+			release = append(release, ri)
+			first = first && ri.err != nil
+			continue
+		}
+
+		p.held = append(p.held, ri)
+	}
+
+	p.deferred = len(p.held) > 0
+	if p.deferred {
+		if p.timer == nil {
+			p.timer = time.NewTimer(pctStepDelay)
+		} else {
+			p.timer.Reset(pctStepDelay)
+		}
+	}
+
+	return release
+}
+
+// pctHeld returns the number of the satisfied waits held back by the PCT scheduler, that are still pending from
+// the point of view of the callers.
+func (b *SyncBus) pctHeld() int {
+	if b.pct == nil {
+		return 0
+	}
+
+	return len(b.pct.held)
+}
+
+// releaseHeld releases the waits held back by the PCT scheduler with their recorded result, when the run loop
+// stops. Like releaseAll, it doesn't block.
+func (b *SyncBus) releaseHeld() {
+	if b.pct == nil {
+		return
+	}
+
+	for _, r := range b.pct.held {
+		select {
+		case r.item.signal <- r.err:
+			r.item.notify(r.err)
+		default:
+		}
+	}
+
+	b.pct.held = nil
+}
+
+func (b *SyncBus) pctStepTimer() <-chan time.Time {
+	if b.pct == nil || !b.pct.deferred {
+		return nil
+	}
+
+	return b.pct.timer.C
+}
@@ -0,0 +1,115 @@
+package syncbus
+
+import (
+	"sync"
+	"testing"
+	"time"
+)
+
+func pctOrder(t *testing.T, seed int64) []int {
+	bus := New(120*time.Millisecond, WithPCT(3, 9), WithSeed(seed))
+	defer bus.Close()
+
+	var (
+		mx    sync.Mutex
+		order []int
+	)
+
+	tw := newTestWait(5)
+	for i := 0; i < 5; i++ {
+		go func(i int) {
+			if err := bus.Wait("foo"); err != nil {
+				t.Error(err)
+			}
+
+			mx.Lock()
+			order = append(order, i)
+			mx.Unlock()
+			tw.done()
+		}(i)
+
+		for bus.Stats().Waiting != i+1 {
+			time.Sleep(time.Millisecond / 10)
+		}
+	}
+
+	bus.Signal("foo")
+	if err := tw.wait(); err != nil {
+		t.Fatal(err)
+	}
+
+	return order
+}
+
+func TestPCTReproducible(t *testing.T) {
+	for _, seed := range []int64{1, 2, 3} {
+		o1 := pctOrder(t, seed)
+		o2 := pctOrder(t, seed)
+		for i := range o1 {
+			if o1[i] != o2[i] {
+				t.Fatal("failed to reproduce order", seed, o1, o2)
+			}
+		}
+	}
+}
+
+func TestPCTExploresOrders(t *testing.T) {
+	orders := make(map[int]bool)
+	for seed := int64(0); seed < 30; seed++ {
+		orders[pctOrder(t, seed)[0]] = true
+	}
+
+	if len(orders) < 2 {
+		t.Error("failed to vary the order")
+	}
+}
+
+func TestPCTStaggeredRelease(t *testing.T) {
+	bus := New(120*time.Millisecond, WithPCT(1, 1))
+	defer bus.Close()
+
+	tw := newTestWait(2)
+	for i := 0; i < 2; i++ {
+		go func() {
+			bus.Wait("foo")
+			tw.done()
+		}()
+	}
+
+	for bus.Stats().Waiting != 2 {
+		time.Sleep(time.Millisecond / 10)
+	}
+
+	bus.Signal("foo")
+	if s := bus.Stats(); s.Releases != 1 || s.Waiting != 1 {
+		t.Error("failed to hold back the lower priority wait", s)
+	}
+
+	if err := tw.wait(); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestPCTResetAfterSatisfied(t *testing.T) {
+	for seed := int64(0); seed < 40; seed++ {
+		bus := New(120*time.Millisecond, WithPCT(3, 4), WithSeed(seed))
+		errs := make(chan error, 2)
+		for i := 0; i < 2; i++ {
+			go func() { errs <- bus.Wait("foo") }()
+		}
+
+		for bus.Stats().Waiting != 2 {
+			time.Sleep(time.Millisecond / 10)
+		}
+
+		bus.Signal("foo")
+		bus.ResetSignals("foo")
+		for i := 0; i < 2; i++ {
+			if err := <-errs; err != nil {
+				t.Errorf("seed %d: satisfied wait failed: %v", seed, err)
+			}
+		}
+
+		bus.Close()
+	}
+}
@@ -0,0 +1,71 @@
+package syncbus
+
+import "errors"
+
+// ErrUnknownStage is returned by the methods of Pipeline, when called with a stage that is not part of the
+// pipeline.
+var ErrUnknownStage = errors.New("unknown stage")
+
+// Pipeline is a sequence of stages, where each stage can start only after the previous one completed. It is
+// created with the Pipeline method of the bus.
+type Pipeline struct {
+	bus    *SyncBus
+	stages []string
+}
+
+// StageStartKey returns the key signaled when the stage represented by name can start.
+func StageStartKey(name string) string {
+	return "stage." + name + ".start"
+}
+
+// StageDoneKey returns the key signaled when the stage represented by name completed.
+func StageDoneKey(name string) string {
+	return "stage." + name + ".done"
+}
+
+// Pipeline creates a pipeline from the stages, in the order they are listed. The completion of each stage is
+// wired to gate the next one with WaitThenSignal, and the first stage can start immediately. The stages use the
+// keys returned by StageStartKey and StageDoneKey.
+//
+// If the receiver *SyncBus is nil, the returned pipeline is a noop.
+func (b *SyncBus) Pipeline(stages ...string) *Pipeline {
+	p := &Pipeline{bus: b, stages: append([]string(nil), stages...)}
+	if b == nil || len(stages) == 0 {
+		return p
+	}
+
+	for i := 1; i < len(stages); i++ {
+		b.WaitThenSignal([]string{StageDoneKey(stages[i-1])}, []string{StageStartKey(stages[i])})
+	}
+
+	b.Signal(StageStartKey(stages[0]))
+	return p
+}
+
+func (p *Pipeline) check(name string) error {
+	if !containsKey(p.stages, name) {
+		return ErrUnknownStage
+	}
+
+	return nil
+}
+
+// AwaitStage blocks until the stage represented by name can start, i.e. the previous stage was released, or
+// the timeout of the bus expires.
+func (p *Pipeline) AwaitStage(name string) error {
+	if err := p.check(name); err != nil {
+		return err
+	}
+
+	return p.bus.Wait(StageStartKey(name))
+}
+
+// ReleaseStage marks the stage represented by name as completed, allowing the next stage to start.
+func (p *Pipeline) ReleaseStage(name string) error {
+	if err := p.check(name); err != nil {
+		return err
+	}
+
+	p.bus.Signal(StageDoneKey(name))
+	return nil
+}
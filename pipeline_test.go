@@ -0,0 +1,77 @@
+package syncbus
+
+import (
+	"testing"
+	"time"
+)
+
+func TestNilPipeline(t *testing.T) {
+	var bus *SyncBus
+	p := bus.Pipeline("ingest", "store")
+	if err := p.AwaitStage("store"); err != nil {
+		t.Error(err)
+	}
+
+	if err := p.ReleaseStage("ingest"); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestPipeline(t *testing.T) {
+	bus := New(120 * time.Millisecond)
+	defer bus.Close()
+
+	p := bus.Pipeline("ingest", "transform", "store")
+	if err := p.AwaitStage("ingest"); err != nil {
+		t.Fatal(err)
+	}
+
+	order := make(chan string, 3)
+	errs := make(chan error, 2)
+	for _, stage := range []string{"store", "transform"} {
+		go func(stage string) {
+			if err := p.AwaitStage(stage); err != nil {
+				errs <- err
+				return
+			}
+
+			order <- stage
+			errs <- p.ReleaseStage(stage)
+		}(stage)
+	}
+
+	order <- "ingest"
+	if err := p.ReleaseStage("ingest"); err != nil {
+		t.Fatal(err)
+	}
+
+	for i := 0; i < 2; i++ {
+		if err := <-errs; err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	for _, expected := range []string{"ingest", "transform", "store"} {
+		if stage := <-order; stage != expected {
+			t.Error("invalid order", stage, expected)
+		}
+	}
+
+	if !bus.SynchronizeWith(StageDoneKey("store")) {
+		t.Error("failed to complete the last stage")
+	}
+}
+
+func TestPipelineUnknownStage(t *testing.T) {
+	bus := New(120 * time.Millisecond)
+	defer bus.Close()
+
+	p := bus.Pipeline("ingest")
+	if err := p.AwaitStage("store"); err != ErrUnknownStage {
+		t.Error("failed to fail", err)
+	}
+
+	if err := p.ReleaseStage("store"); err != ErrUnknownStage {
+		t.Error("failed to fail", err)
+	}
+}
@@ -0,0 +1,29 @@
+package syncbus
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// WithErrorContext makes the timed out Wait calls return a TimeoutError, that contains the last n events of the
+// bus, leading up to the timeout, so that a single failure message contains a short trace of what the bus saw,
+// without enabling the history or a separate logging. Zero means no error context, which is the default.
+func WithErrorContext(n int) Option {
+	return func(o *options) { o.errorContext = n }
+}
+
+func (b *SyncBus) recordRecent(e Event) {
+	if b.options.errorContext > 0 {
+		b.recent.add(b.options.errorContext, e)
+	}
+}
+
+func formatRecent(events []Event, start time.Time) string {
+	s := make([]string, len(events))
+	for i, e := range events {
+		s[i] = fmt.Sprintf("%v %s at -%v", e.Type, strings.Join(e.Keys, ", "), start.Sub(e.Time))
+	}
+
+	return strings.Join(s, "; ")
+}
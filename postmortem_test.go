@@ -0,0 +1,30 @@
+package syncbus
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestErrorContext(t *testing.T) {
+	bus := New(3*time.Millisecond, WithErrorContext(2))
+	defer bus.Close()
+
+	bus.Signal("foo")
+	bus.Signal("bar")
+	bus.ResetSignals("foo")
+	err := bus.Wait("foo", "bar")
+	var terr *TimeoutError
+	if !errors.As(err, &terr) {
+		t.Fatal("failed to timeout with context", err)
+	}
+
+	if len(terr.Recent) != 2 || terr.Recent[0].Type != EventReset || terr.Recent[1].Type != EventWait {
+		t.Fatal("invalid recent events", terr.Recent)
+	}
+
+	if !strings.Contains(err.Error(), "recent: reset foo at -") {
+		t.Error("invalid error message", err)
+	}
+}
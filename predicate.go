@@ -0,0 +1,78 @@
+package syncbus
+
+import "sort"
+
+// State is a read-only view of the state of the bus, passed to the predicates of WaitFor. It is valid only
+// during the call to the predicate. A State can also be created with NewState, without a bus, describing an
+// expected state for Diff.
+type State struct {
+	bus      *SyncBus
+	expected *expectedState
+}
+
+// IsSet tells whether the signal represented by key is set.
+func (s State) IsSet(key string) bool {
+	if s.bus == nil {
+		return s.expected.isSet(key)
+	}
+
+	return s.bus.signals.has(key)
+}
+
+// Signals returns the keys of the set signals, sorted.
+func (s State) Signals() []string {
+	if s.bus == nil {
+		return s.expected.list()
+	}
+
+	keys := s.bus.signals.list()
+	sort.Strings(keys)
+	return keys
+}
+
+// Count returns how many times the signal represented by key was set since the bus was created, including the
+// times when it was already set. For a State created with NewState, it returns the count set by WithCount.
+func (s State) Count(key string) int {
+	if s.bus == nil {
+		return s.expected.count(key)
+	}
+
+	return s.bus.signals.count(key)
+}
+
+// Stats returns the current size and the cumulative counters of the bus. For a State created with NewState, it
+// returns zero stats.
+func (s State) Stats() Stats {
+	if s.bus == nil {
+		return Stats{}
+	}
+
+	return s.bus.createSnapshot(s.bus.now()).stats
+}
+
+// hasPredicates tells whether any of the pending waits is a predicate or condition wait, that needs to be
+// evaluated also on resets.
+func (b *SyncBus) hasPredicates() bool {
+	for _, w := range b.waiting {
+		if w.predicate != nil || w.cond != nil {
+			return true
+		}
+	}
+
+	return false
+}
+
+// WaitFor blocks until the predicate returns true, or returns an ErrTimeout if it doesn't happen within the
+// timeout of the bus. The predicate is evaluated by the run loop against the state of the bus, when calling
+// WaitFor, and whenever the state changes, i.e. signals are set or reset. It allows expressing conditions that
+// can't be described by a list of keys, e.g. "at least 3 workers done, and no error key set". The predicate
+// must not call the methods of the bus, and it must not retain the State.
+//
+// If the receiver *SyncBus is nil, or the predicate is nil, it is a noop.
+func (b *SyncBus) WaitFor(predicate func(State) bool) error {
+	if b == nil || predicate == nil {
+		return nil
+	}
+
+	return b.waitItem(waitItem{predicate: predicate})
+}
@@ -0,0 +1,71 @@
+package syncbus
+
+import (
+	"fmt"
+	"testing"
+	"time"
+)
+
+func TestNilWaitFor(t *testing.T) {
+	var bus *SyncBus
+	if err := bus.WaitFor(func(State) bool { return false }); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestWaitFor(t *testing.T) {
+	bus := New(120 * time.Millisecond)
+	defer bus.Close()
+
+	bus.Signal("error")
+	errs := make(chan error, 1)
+	go func() {
+		errs <- bus.WaitFor(func(s State) bool {
+			var done int
+			for _, key := range s.Signals() {
+				if len(key) > 4 && key[:4] == "done" {
+					done++
+				}
+			}
+
+			return done >= 3 && !s.IsSet("error")
+		})
+	}()
+
+	for bus.Stats().Waiting != 1 {
+		time.Sleep(time.Millisecond / 10)
+	}
+
+	for i := 0; i < 3; i++ {
+		bus.Signal(fmt.Sprintf("done-%d", i))
+	}
+
+	if s := bus.Stats(); s.Waiting != 1 {
+		t.Fatal("unexpected release", s)
+	}
+
+	bus.ResetSignals("error")
+	if err := <-errs; err != nil {
+		t.Error(err)
+	}
+}
+
+func TestWaitForCount(t *testing.T) {
+	bus := New(120 * time.Millisecond)
+	defer bus.Close()
+
+	bus.Signal("foo")
+	bus.Signal("foo")
+	if err := bus.WaitFor(func(s State) bool { return s.Count("foo") == 2 && s.Stats().SignalCalls == 2 }); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestWaitForTimeout(t *testing.T) {
+	bus := New(12 * time.Millisecond)
+	defer bus.Close()
+
+	if err := bus.WaitFor(func(State) bool { return false }); err != ErrTimeout {
+		t.Error("failed to timeout", err)
+	}
+}
@@ -0,0 +1,136 @@
+package syncbus
+
+import (
+	"path"
+	"sort"
+	"time"
+)
+
+// Filter describes a query over the recorded history. The zero value of each field matches every event.
+type Filter struct {
+
+	// KeyPattern matches the events that involve at least one key matching the pattern. The pattern syntax is
+	// the same as of path.Match, e.g. "worker.*".
+	KeyPattern string
+
+	// Types matches the events of the listed types.
+	Types []EventType
+
+	// From matches the events that happened at or after it.
+	From time.Time
+
+	// To matches the events that happened before it.
+	To time.Time
+
+	// Labels matches the events of the waits that have all the listed labels with the same values, see
+	// WithLabel.
+	Labels map[string]string
+}
+
+type queryItem struct {
+	filter Filter
+	result chan []Event
+}
+
+func (f Filter) matchKeys(keys []string) bool {
+	if f.KeyPattern == "" {
+		return true
+	}
+
+	for _, key := range keys {
+		if m, _ := path.Match(f.KeyPattern, key); m {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (f Filter) matchType(t EventType) bool {
+	if len(f.Types) == 0 {
+		return true
+	}
+
+	for _, ft := range f.Types {
+		if ft == t {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (f Filter) matchLabels(labels map[string]string) bool {
+	for name, value := range f.Labels {
+		if v, ok := labels[name]; !ok || v != value {
+			return false
+		}
+	}
+
+	return true
+}
+
+func (f Filter) match(e Event) bool {
+	return f.matchType(e.Type) && f.matchKeys(e.Keys) && f.matchLabels(e.Labels)
+}
+
+// query returns the recorded events matching the filter. Since the events are recorded in the order of their
+// time, it finds the boundaries of the time range with binary search, and scans only the events within them.
+func (h *history) query(f Filter) []Event {
+	n := len(h.events)
+	at := func(i int) Event { return h.events[(h.start+i)%n] }
+	from := 0
+	if !f.From.IsZero() {
+		from = sort.Search(n, func(i int) bool { return !at(i).Time.Before(f.From) })
+	}
+
+	to := n
+	if !f.To.IsZero() {
+		to = sort.Search(n, func(i int) bool { return !at(i).Time.Before(f.To) })
+	}
+
+	l := make([]Event, 0, to-from)
+	for i := from; i < to; i++ {
+		if e := at(i); f.match(e) {
+			l = append(l, e)
+		}
+	}
+
+	return l
+}
+
+func (b *SyncBus) queryHistory(f Filter) []Event {
+	q := queryItem{filter: f, result: make(chan []Event, 1)}
+	if b.closed() {
+		return nil
+	}
+
+	select {
+	case b.historyReq <- q:
+		return <-q.result
+	case <-b.done:
+		return nil
+	}
+}
+
+// Query returns the recorded events matching the filter, in the order they happened. The filtering is executed
+// by the run loop, without copying the whole history. It requires the bus to be created with WithHistory,
+// otherwise it returns ErrNoHistory. When the key pattern of the filter is malformed, it returns
+// path.ErrBadPattern.
+//
+// If the receiver *SyncBus is nil, it returns nil.
+func (b *SyncBus) Query(f Filter) ([]Event, error) {
+	if b == nil {
+		return nil, nil
+	}
+
+	if b.options.historySize <= 0 {
+		return nil, ErrNoHistory
+	}
+
+	if _, err := path.Match(f.KeyPattern, ""); err != nil {
+		return nil, err
+	}
+
+	return b.queryHistory(f), nil
+}
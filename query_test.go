@@ -0,0 +1,73 @@
+package syncbus
+
+import (
+	"path"
+	"testing"
+	"time"
+)
+
+func TestNilQuery(t *testing.T) {
+	var bus *SyncBus
+	if e, err := bus.Query(Filter{}); e != nil || err != nil {
+		t.Error("unexpected result", e, err)
+	}
+}
+
+func TestQueryNoHistory(t *testing.T) {
+	bus := New(120 * time.Millisecond)
+	defer bus.Close()
+	if _, err := bus.Query(Filter{}); err != ErrNoHistory {
+		t.Error("failed to fail", err)
+	}
+}
+
+func TestQueryBadPattern(t *testing.T) {
+	bus := New(120*time.Millisecond, WithHistory())
+	defer bus.Close()
+	if _, err := bus.Query(Filter{KeyPattern: "["}); err != path.ErrBadPattern {
+		t.Error("failed to fail", err)
+	}
+}
+
+func TestQuery(t *testing.T) {
+	bus := New(120*time.Millisecond, WithHistorySize(4))
+	defer bus.Close()
+
+	bus.Signal("worker.1")
+	bus.Signal("other")
+	if err := bus.WaitWith([]WaitOpt{WithLabel("component", "ingress")}, "worker.1"); err != nil {
+		t.Fatal(err)
+	}
+
+	time.Sleep(3 * time.Millisecond)
+	middle := time.Now()
+	time.Sleep(3 * time.Millisecond)
+	bus.Signal("worker.2")
+	bus.Signal("worker.3")
+
+	check := func(f Filter, expected ...EventType) {
+		t.Helper()
+		e, err := bus.Query(f)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if len(e) != len(expected) {
+			t.Fatal("invalid number of events", e)
+		}
+
+		for i := range expected {
+			if e[i].Type != expected[i] {
+				t.Error("invalid event", i, e[i])
+			}
+		}
+	}
+
+	check(Filter{}, EventWait, EventRelease, EventSignal, EventSignal)
+	check(Filter{KeyPattern: "worker.*", Types: []EventType{EventSignal}}, EventSignal, EventSignal)
+	check(Filter{Labels: map[string]string{"component": "ingress"}}, EventWait, EventRelease)
+	check(Filter{Labels: map[string]string{"component": "egress"}})
+	check(Filter{From: middle}, EventSignal, EventSignal)
+	check(Filter{To: middle}, EventWait, EventRelease)
+	check(Filter{KeyPattern: "other"})
+}
@@ -0,0 +1,129 @@
+package syncbus
+
+import (
+	"math/rand"
+	"sync"
+	"time"
+)
+
+type random struct {
+	mx        sync.Mutex
+	rand      *rand.Rand
+	record    bool
+	recorded  []decision
+	replay    []decision
+	generated bool
+	choices   Schedule
+}
+
+func newRandom(seed int64, record bool, replay []decision) *random {
+	return &random{
+		rand:   rand.New(rand.NewSource(seed)),
+		record: record,
+		replay: replay,
+	}
+}
+
+// next returns the next replayed decision, if it matches the requested kind. Once the replayed schedule
+// diverges, the rest of it is dropped, and the decisions are made by the seeded source.
+func (r *random) next(float bool, n int64) (decision, bool) {
+	if len(r.replay) == 0 {
+		return decision{}, false
+	}
+
+	d := r.replay[0]
+	if d.float != float || d.n != n {
+		r.replay = nil
+		return decision{}, false
+	}
+
+	r.replay = r.replay[1:]
+	return d, true
+}
+
+func (r *random) int63n(n int64) int64 {
+	r.mx.Lock()
+	defer r.mx.Unlock()
+	d, ok := r.next(false, n)
+	if !ok {
+		d, ok = r.choose(false, n)
+	}
+
+	if !ok {
+		d = decision{n: n, value: r.rand.Int63n(n)}
+	}
+
+	if r.record {
+		r.recorded = append(r.recorded, d)
+	}
+
+	return d.value
+}
+
+func (r *random) float64() float64 {
+	r.mx.Lock()
+	defer r.mx.Unlock()
+	d, ok := r.next(true, 0)
+	if !ok {
+		d, ok = r.choose(true, 0)
+	}
+
+	if !ok {
+		d = decision{float: true, floatValue: r.rand.Float64()}
+	}
+
+	if r.record {
+		r.recorded = append(r.recorded, d)
+	}
+
+	return d.floatValue
+}
+
+func (r *random) decisions() []decision {
+	r.mx.Lock()
+	defer r.mx.Unlock()
+	return append([]decision(nil), r.recorded...)
+}
+
+func (b *SyncBus) sample() bool {
+	if b.options.sampling >= 1 {
+		return true
+	}
+
+	return b.rand.float64() < b.options.sampling
+}
+
+func (r *random) shuffle(n int, swap func(i, j int)) {
+	for i := n - 1; i > 0; i-- {
+		swap(i, int(r.int63n(int64(i+1))))
+	}
+}
+
+// orderRelease returns the satisfied waits to be released in the current pass, in the order of releasing them.
+// With PCT scheduling, it may keep some of them pending for a later pass.
+func (b *SyncBus) orderRelease(r []released) []released {
+	if b.pct != nil {
+		return b.pctRelease(r)
+	}
+
+	return b.options.wakeupOrder.order(b.rand, r)
+}
+
+func (b *SyncBus) jitter() {
+	if b.options.jitter <= 0 {
+		return
+	}
+
+	time.Sleep(time.Duration(b.rand.int63n(int64(b.options.jitter))))
+}
+
+// Seed returns the seed used by the randomized behaviors of the bus.
+//
+// If the receiver *SyncBus is nil, it returns 0.
+func (b *SyncBus) Seed() int64 {
+	if b == nil {
+		return 0
+	}
+
+	return b.options.seed
+}
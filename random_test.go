@@ -0,0 +1,75 @@
+package syncbus
+
+import (
+	"testing"
+	"time"
+)
+
+func TestSeed(t *testing.T) {
+	var bus *SyncBus
+	if bus.Seed() != 0 {
+		t.Error("unexpected seed")
+	}
+
+	bus = New(12*time.Millisecond, WithSeed(42))
+	defer bus.Close()
+	if bus.Seed() != 42 {
+		t.Error("invalid seed")
+	}
+}
+
+func TestJitterReproducible(t *testing.T) {
+	r1 := newRandom(42, false, nil)
+	r2 := newRandom(42, false, nil)
+	for i := 0; i < 9; i++ {
+		if r1.int63n(1000) != r2.int63n(1000) {
+			t.Fatal("failed to reproduce random sequence")
+		}
+	}
+}
+
+func TestJitter(t *testing.T) {
+	bus := New(120*time.Millisecond, WithJitter(3*time.Millisecond), WithSeed(42))
+	defer bus.Close()
+
+	tw := newTestWait(3)
+	for i := 0; i < 3; i++ {
+		go func() {
+			if err := bus.Wait("foo"); err != nil {
+				t.Error(err)
+			}
+
+			tw.done()
+		}()
+	}
+
+	bus.Signal("foo")
+	if err := tw.wait(); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestSampling(t *testing.T) {
+	bus := New(time.Millisecond, WithSampling(0.5), WithSeed(42))
+	defer bus.Close()
+
+	var blocked int
+	for i := 0; i < 100; i++ {
+		if err := bus.Wait("foo"); err == ErrTimeout {
+			blocked++
+		}
+	}
+
+	if blocked == 0 || blocked == 100 {
+		t.Error("failed to sample", blocked)
+	}
+}
+
+func TestSamplingNone(t *testing.T) {
+	bus := New(120*time.Millisecond, WithSampling(0))
+	defer bus.Close()
+
+	if err := bus.Wait("foo"); err != nil {
+		t.Error(err)
+	}
+}
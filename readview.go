@@ -0,0 +1,111 @@
+package syncbus
+
+import (
+	"sort"
+	"sync"
+	"sync/atomic"
+)
+
+// readView is a copy of the signals and the stats of the bus, maintained by the run loop, and read by the
+// queries without going through the run loop.
+type readView struct {
+	lock    sync.RWMutex
+	signals map[string]bool
+	stats   Stats
+}
+
+// WithReadMostlyState enables maintaining a copy of the set signals and the stats of the bus, protected by a
+// read-write mutex, so that IsSet, SetKeys and Stats don't need to go through the run loop, keeping the
+// inspection cheap even while the run loop is busy releasing waits. The copy is updated by the run loop after
+// it processed an operation, which means that these queries may not reflect yet an operation whose call
+// already returned, e.g. Signal. To synchronize with the signals, use Wait or SynchronizeWith.
+func WithReadMostlyState() Option {
+	return func(o *options) { o.readMostly = true }
+}
+
+func (b *SyncBus) viewSignals(keys []string, set bool) {
+	if b.view == nil {
+		return
+	}
+
+	b.view.lock.Lock()
+	defer b.view.lock.Unlock()
+	for _, key := range keys {
+		if set {
+			b.view.signals[key] = true
+		} else {
+			delete(b.view.signals, key)
+		}
+	}
+}
+
+func (b *SyncBus) viewResetAll() {
+	if b.view == nil {
+		return
+	}
+
+	b.view.lock.Lock()
+	defer b.view.lock.Unlock()
+	b.view.signals = make(map[string]bool)
+}
+
+func (b *SyncBus) viewStats() {
+	if b.view == nil {
+		return
+	}
+
+	s := b.createStats()
+	b.view.lock.Lock()
+	defer b.view.lock.Unlock()
+	b.view.stats = s
+}
+
+func (b *SyncBus) readStats() Stats {
+	b.view.lock.RLock()
+	defer b.view.lock.RUnlock()
+	s := b.view.stats
+	s.DroppedEvents = atomic.LoadUint64(&b.dropped)
+	s.ProducerWaits = atomic.LoadUint64(&b.stalled)
+	return s
+}
+
+// IsSet tells whether the signal represented by key is set. Without WithReadMostlyState, it is the same as
+// SynchronizeWith.
+//
+// If the receiver *SyncBus is nil, it returns false.
+func (b *SyncBus) IsSet(key string) bool {
+	if b == nil || b.closed() {
+		return false
+	}
+
+	if b.view == nil {
+		return b.SynchronizeWith(key)
+	}
+
+	b.view.lock.RLock()
+	defer b.view.lock.RUnlock()
+	return b.view.signals[key]
+}
+
+// SetKeys returns the keys of the currently set signals, in alphabetical order.
+//
+// If the receiver *SyncBus is nil, it returns nil.
+func (b *SyncBus) SetKeys() []string {
+	if b == nil || b.closed() {
+		return nil
+	}
+
+	if b.view == nil {
+		return b.getSnapshot().signals
+	}
+
+	b.view.lock.RLock()
+	keys := make([]string, 0, len(b.view.signals))
+	for key := range b.view.signals {
+		keys = append(keys, key)
+	}
+
+	b.view.lock.RUnlock()
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,58 @@
+package syncbus
+
+import (
+	"testing"
+	"time"
+)
+
+func TestNilIsSet(t *testing.T) {
+	var bus *SyncBus
+	if bus.IsSet("foo") || bus.SetKeys() != nil {
+		t.Error("unexpected signal")
+	}
+}
+
+func testReadState(t *testing.T, bus *SyncBus) {
+	bus.Signal("foo", "bar", "baz")
+	bus.ResetSignals("bar")
+	if err := bus.Wait("foo", "baz"); err != nil {
+		t.Fatal(err)
+	}
+
+	// a round trip to the run loop makes sure that the read-mostly state was updated after the release
+	bus.SynchronizeWith("foo")
+
+	if !bus.IsSet("foo") || bus.IsSet("bar") {
+		t.Error("invalid signals")
+	}
+
+	if k := bus.SetKeys(); len(k) != 2 || k[0] != "baz" || k[1] != "foo" {
+		t.Error("invalid keys", k)
+	}
+
+	if s := bus.Stats(); s.Signals != 2 || s.SignalCalls != 1 || s.Waits != 1 || s.Releases != 1 {
+		t.Error("invalid stats", s)
+	}
+
+	bus.Reset()
+	if err := bus.WaitTimeout(time.Millisecond, "foo"); err != ErrTimeout {
+		t.Fatal("failed to timeout", err)
+	}
+
+	if bus.IsSet("foo") || len(bus.SetKeys()) != 0 {
+		t.Error("failed to reset")
+	}
+
+	bus.Close()
+	if bus.IsSet("foo") || bus.SetKeys() != nil || bus.Stats() != (Stats{}) {
+		t.Error("unexpected state after close")
+	}
+}
+
+func TestReadState(t *testing.T) {
+	testReadState(t, New(120*time.Millisecond))
+}
+
+func TestReadMostlyState(t *testing.T) {
+	testReadState(t, New(120*time.Millisecond, WithReadMostlyState()))
+}
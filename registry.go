@@ -0,0 +1,156 @@
+package syncbus
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+type keyDecl struct {
+	key        string
+	after      []string
+	maxWaiters int
+	owner      string
+}
+
+// KeyOpt can be used to describe a key when declaring it with Declare.
+type KeyOpt func(*keyDecl)
+
+// CycleError is returned by Wait when the keys that it is waiting for can never be set, because they depend on
+// each other in a circle, according to the declared keys.
+type CycleError struct {
+
+	// Keys contains the keys of the cycle, in the order of their dependencies, starting and ending with the
+	// same key.
+	Keys []string
+}
+
+// ErrCycle is the error that CycleError unwraps to.
+var ErrCycle = errors.New("circular wait")
+
+// SignaledAfter declares that the key is only ever signaled after a wait for the provided keys has proceeded,
+// i.e. the code that signals it, waits for these keys first.
+func SignaledAfter(keys ...string) KeyOpt {
+	return func(d *keyDecl) { d.after = append(d.after, keys...) }
+}
+
+func (err *CycleError) Error() string {
+	return fmt.Sprintf("%v: %s", ErrCycle, strings.Join(err.Keys, " -> "))
+}
+
+// Unwrap returns ErrCycle.
+func (err *CycleError) Unwrap() error {
+	return ErrCycle
+}
+
+// findCycles returns the keys that are part of a circular wait, mapped to the cycle that they are part of. Only
+// those keys are considered, that are not set, and that are being waited for.
+func (b *SyncBus) findCycles() map[string][]string {
+	if len(b.declared) == 0 {
+		return nil
+	}
+
+	awaited := make(map[string]bool)
+	for _, w := range b.waiting {
+		for _, key := range b.missing(w.keys) {
+			awaited[key] = true
+		}
+	}
+
+	var keys []string
+	for key := range awaited {
+		keys = append(keys, key)
+	}
+
+	sort.Strings(keys)
+
+	const (
+		visiting = 1
+		visited  = 2
+	)
+
+	var (
+		state  = make(map[string]int)
+		stack  []string
+		cycles = make(map[string][]string)
+		visit  func(string)
+	)
+
+	visit = func(key string) {
+		state[key] = visiting
+		stack = append(stack, key)
+		for _, next := range b.declared[key].after {
+			if !awaited[next] {
+				continue
+			}
+
+			switch state[next] {
+			case visiting:
+				var c []string
+				for i := len(stack) - 1; i >= 0; i-- {
+					if stack[i] == next {
+						c = append(append(c, stack[i:]...), next)
+						break
+					}
+				}
+
+				for _, key := range c {
+					if _, ok := cycles[key]; !ok {
+						cycles[key] = c
+					}
+				}
+			case 0:
+				visit(next)
+			}
+		}
+
+		stack = stack[:len(stack)-1]
+		state[key] = visited
+	}
+
+	for _, key := range keys {
+		if state[key] == 0 {
+			visit(key)
+		}
+	}
+
+	return cycles
+}
+
+func (b *SyncBus) checkCycle(w waitItem) error {
+	for _, key := range w.keys {
+		if c, ok := b.cycles[key]; ok && !b.signals.has(key) {
+			return &CycleError{Keys: c}
+		}
+	}
+
+	return nil
+}
+
+// Declare registers a key with the bus, describing how it is used by the tested code. The declarations enable
+// the bus to detect problems early, e.g. a Wait that can never succeed due to a circular dependency between the
+// keys fails with a CycleError, instead of timing out. Declaring the same key again overrides the previous
+// declaration.
+//
+// If the receiver *SyncBus is nil, it is a noop.
+func (b *SyncBus) Declare(key string, opts ...KeyOpt) {
+	if b == nil {
+		return
+	}
+
+	d := keyDecl{key: key}
+	for _, opt := range opts {
+		opt(&d)
+	}
+
+	b.declareOwner(d)
+	if b.closed() {
+		return
+	}
+
+	select {
+	case b.declare <- d:
+	case <-b.done:
+	}
+}
@@ -0,0 +1,112 @@
+package syncbus
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestNilDeclare(t *testing.T) {
+	var bus *SyncBus
+	bus.Declare("foo", SignaledAfter("bar"))
+}
+
+func TestCircularWait(t *testing.T) {
+	bus := New(120 * time.Millisecond)
+	defer bus.Close()
+
+	bus.Declare("a.done", SignaledAfter("b.done"))
+	bus.Declare("b.done", SignaledAfter("a.done"))
+
+	errs := make(chan error, 2)
+	go func() { errs <- bus.Wait("b.done") }()
+	go func() { errs <- bus.Wait("a.done") }()
+
+	start := time.Now()
+	for i := 0; i < 2; i++ {
+		err := <-errs
+		var cerr *CycleError
+		if !errors.As(err, &cerr) || !errors.Is(err, ErrCycle) {
+			t.Fatal("failed to detect the cycle", err)
+		}
+
+		if len(cerr.Keys) != 3 || cerr.Keys[0] != cerr.Keys[2] || cerr.Keys[0] == cerr.Keys[1] {
+			t.Error("invalid cycle", cerr.Keys)
+		}
+	}
+
+	if time.Since(start) >= 120*time.Millisecond {
+		t.Error("failed to fail fast")
+	}
+}
+
+func TestCircularWaitNeedsLiveWaiters(t *testing.T) {
+	bus := New(12 * time.Millisecond)
+	defer bus.Close()
+
+	bus.Declare("a.done", SignaledAfter("b.done"))
+	bus.Declare("b.done", SignaledAfter("a.done"))
+	if err := bus.Wait("a.done"); err != ErrTimeout {
+		t.Error("failed to timeout", err)
+	}
+}
+
+func TestCircularWaitSetKeyBreaksCycle(t *testing.T) {
+	bus := New(120 * time.Millisecond)
+	defer bus.Close()
+
+	bus.Declare("a", SignaledAfter("b"))
+	bus.Declare("b", SignaledAfter("c"))
+	bus.Declare("c", SignaledAfter("a"))
+	bus.Signal("c")
+
+	tw := newTestWait(2)
+	go func() {
+		if err := bus.Wait("a"); err != nil {
+			t.Error(err)
+		}
+
+		tw.done()
+	}()
+
+	go func() {
+		if err := bus.Wait("b"); err != nil {
+			t.Error(err)
+		}
+
+		tw.done()
+	}()
+
+	for bus.Stats().Waiting != 2 {
+		time.Sleep(time.Millisecond / 10)
+	}
+
+	bus.Signal("b")
+	bus.Signal("a")
+	if err := tw.wait(); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestCircularWaitAfterReset(t *testing.T) {
+	bus := New(120 * time.Millisecond)
+	defer bus.Close()
+
+	bus.Declare("a", SignaledAfter("b"))
+	bus.Declare("b", SignaledAfter("a"))
+	bus.Signal("b")
+
+	errs := make(chan error, 2)
+	go func() { errs <- bus.Wait("a", "b") }()
+	go func() { errs <- bus.Wait("b", "c") }()
+	for bus.Stats().Waiting != 2 {
+		time.Sleep(time.Millisecond / 10)
+	}
+
+	bus.ResetSignals("b")
+	for i := 0; i < 2; i++ {
+		if err := <-errs; !errors.Is(err, ErrCycle) {
+			t.Error("failed to detect the cycle", err)
+		}
+	}
+}
@@ -0,0 +1,65 @@
+package syncbus
+
+import "time"
+
+type relayItem struct {
+	wait []string
+	then []string
+}
+
+// fireRelays sets the follow-on signals of the relays whose awaited signals are all set. Since the follow-on
+// signals can satisfy further relays, it repeats until no more relays fire.
+func (b *SyncBus) fireRelays(now time.Time) {
+	for {
+		var (
+			keep  []relayItem
+			fired []relayItem
+		)
+
+		for _, r := range b.relays {
+			if len(b.missing(r.wait)) == 0 {
+				fired = append(fired, r)
+				continue
+			}
+
+			keep = append(keep, r)
+		}
+
+		if len(fired) == 0 {
+			return
+		}
+
+		b.relays = keep
+		for _, r := range fired {
+			b.setSignal(now, signalItem{keys: r.then})
+		}
+	}
+}
+
+// WaitThenSignal registers a relay with the bus, that sets the signals represented by thenKeys, once all the
+// signals represented by waitKeys are set. It doesn't block the caller, and the relay fires only once. When
+// the awaited signals are already set, the follow-on signals are set immediately. It replaces the helper
+// goroutines that exist only to relay signals.
+//
+// If the receiver *SyncBus is nil, or either waitKeys or thenKeys is empty, it is a noop.
+func (b *SyncBus) WaitThenSignal(waitKeys []string, thenKeys []string) {
+	if b == nil || len(waitKeys) == 0 || len(thenKeys) == 0 {
+		return
+	}
+
+	r := relayItem{
+		wait: append([]string(nil), waitKeys...),
+		then: append([]string(nil), thenKeys...),
+	}
+
+	if b.closed() {
+		b.useAfterClose("WaitThenSignal", thenKeys)
+		return
+	}
+
+	select {
+	case b.relay <- r:
+	case <-b.done:
+		b.useAfterClose("WaitThenSignal", thenKeys)
+	}
+}
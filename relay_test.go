@@ -0,0 +1,58 @@
+package syncbus
+
+import (
+	"testing"
+	"time"
+)
+
+func TestNilWaitThenSignal(t *testing.T) {
+	var bus *SyncBus
+	bus.WaitThenSignal([]string{"foo"}, []string{"bar"})
+}
+
+func TestWaitThenSignal(t *testing.T) {
+	bus := New(120 * time.Millisecond)
+	defer bus.Close()
+
+	bus.WaitThenSignal([]string{"foo", "bar"}, []string{"baz"})
+	bus.WaitThenSignal([]string{"baz"}, []string{"qux", "quux"})
+	bus.Signal("foo")
+	if bus.SynchronizeWith("baz") {
+		t.Fatal("relay fired too early")
+	}
+
+	errs := make(chan error, 1)
+	go func() { errs <- bus.Wait("quux") }()
+	bus.Signal("bar")
+	if err := <-errs; err != nil {
+		t.Fatal(err)
+	}
+
+	if !bus.SynchronizeWith("baz") || !bus.SynchronizeWith("qux") {
+		t.Error("failed to relay the signals")
+	}
+}
+
+func TestWaitThenSignalAlreadySet(t *testing.T) {
+	bus := New(120 * time.Millisecond)
+	defer bus.Close()
+
+	bus.Signal("foo")
+	bus.WaitThenSignal([]string{"foo"}, []string{"bar"})
+	if err := bus.Wait("bar"); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestWaitThenSignalOnce(t *testing.T) {
+	bus := New(120 * time.Millisecond)
+	defer bus.Close()
+
+	bus.WaitThenSignal([]string{"foo"}, []string{"bar"})
+	bus.Signal("foo")
+	bus.ResetSignals("bar")
+	bus.Signal("foo")
+	if bus.SynchronizeWith("bar") {
+		t.Error("relay fired twice")
+	}
+}
@@ -0,0 +1,130 @@
+package syncbus
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+type blockedTime struct {
+	total       time.Duration
+	late        int
+	waiters     map[string]*WaiterReport
+	lateSignals []LateSignal
+}
+
+// WaiterReport contains the accumulated blocked time of the waits with the same name.
+type WaiterReport struct {
+
+	// Name is the name of the waits set by WithName. For the unnamed waits, it is the list of their keys.
+	Name string
+
+	// Waits is the number of the waits that finished.
+	Waits int
+
+	// Blocked is the total time that the waits spent blocked.
+	Blocked time.Duration
+
+	// Late is the number of the waits released within the grace period set by WithGracePeriod.
+	Late int
+}
+
+// Report contains how much time the waits of the bus spent blocked.
+type Report struct {
+
+	// Blocked is the total time that the waits of the bus spent blocked. The time of the concurrent waits is
+	// counted separately for each of them.
+	Blocked time.Duration
+
+	// Late is the number of the waits released within the grace period set by WithGracePeriod.
+	Late int
+
+	// Waiters contains the blocked time per waiter, ordered by the blocked time, in descending order.
+	Waiters []WaiterReport
+
+	// LateSignals contains the signals that arrived after the waits depending on them timed out, recorded with
+	// WithLateSignalTracking, in the order of their arrival.
+	LateSignals []LateSignal
+}
+
+func waiterName(w waitItem) string {
+	if w.name != "" {
+		return w.name
+	}
+
+	return strings.Join(w.keys, ", ")
+}
+
+// accountBlocked accounts the time that a wait spent blocked, when it leaves the queue of the pending waits.
+func (b *SyncBus) accountBlocked(now time.Time, w waitItem) {
+	d := now.Sub(w.start)
+	b.blocked.total += d
+	name := waiterName(w)
+	r, ok := b.blocked.waiters[name]
+	if !ok {
+		r = &WaiterReport{Name: name}
+		b.blocked.waiters[name] = r
+	}
+
+	r.Waits++
+	r.Blocked += d
+}
+
+func (b *SyncBus) createReport() Report {
+	r := Report{
+		Blocked:     b.blocked.total,
+		Late:        b.blocked.late,
+		LateSignals: append([]LateSignal(nil), b.blocked.lateSignals...),
+	}
+
+	for _, w := range b.blocked.waiters {
+		r.Waiters = append(r.Waiters, *w)
+	}
+
+	sort.Slice(r.Waiters, func(i, j int) bool {
+		if r.Waiters[i].Blocked == r.Waiters[j].Blocked {
+			return r.Waiters[i].Name < r.Waiters[j].Name
+		}
+
+		return r.Waiters[i].Blocked > r.Waiters[j].Blocked
+	})
+
+	return r
+}
+
+// String returns a human readable table of the blocked times.
+func (r Report) String() string {
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "blocked: %v; late: %d\n", r.Blocked, r.Late)
+	for _, w := range r.Waiters {
+		fmt.Fprintf(&buf, "  %s: %v in %d waits", w.Name, w.Blocked, w.Waits)
+		if w.Late > 0 {
+			fmt.Fprintf(&buf, "; late: %d", w.Late)
+		}
+
+		fmt.Fprintln(&buf)
+	}
+
+	if len(r.LateSignals) > 0 {
+		fmt.Fprintln(&buf, "late signals:")
+		for _, s := range r.LateSignals {
+			fmt.Fprintf(&buf, "  %v\n", s)
+		}
+	}
+
+	return buf.String()
+}
+
+// Report returns how much time the finished waits of the bus spent blocked, in total, and per waiter. It helps
+// identifying which synchronization points dominate the wall-clock time of a slow test suite.
+//
+// If the receiver *SyncBus is nil, it returns an empty report.
+func (b *SyncBus) Report() Report {
+	if b == nil {
+		return Report{}
+	}
+
+	return b.getSnapshot().report
+}
@@ -0,0 +1,62 @@
+package syncbus
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestNilReport(t *testing.T) {
+	var bus *SyncBus
+	if r := bus.Report(); r.Blocked != 0 || len(r.Waiters) != 0 {
+		t.Error("unexpected report", r)
+	}
+}
+
+func TestReport(t *testing.T) {
+	bus := New(120 * time.Millisecond)
+	defer bus.Close()
+
+	errs := make(chan error, 2)
+	go func() { errs <- bus.WaitWith([]WaitOpt{WithName("slow")}, "foo") }()
+	go func() { errs <- bus.Wait("bar", "baz") }()
+	for bus.Stats().Waiting != 2 {
+		time.Sleep(time.Millisecond / 10)
+	}
+
+	time.Sleep(12 * time.Millisecond)
+	bus.Signal("bar", "baz")
+	time.Sleep(12 * time.Millisecond)
+	bus.Signal("foo")
+	for i := 0; i < 2; i++ {
+		if err := <-errs; err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if err := bus.Wait("foo"); err != nil {
+		t.Fatal(err)
+	}
+
+	r := bus.Report()
+	if len(r.Waiters) != 3 {
+		t.Fatal("invalid report", r)
+	}
+
+	slow, keys := r.Waiters[0], r.Waiters[1]
+	if slow.Name != "slow" || slow.Waits != 1 || slow.Blocked < 24*time.Millisecond {
+		t.Error("invalid named waiter", slow)
+	}
+
+	if keys.Name != "bar, baz" || keys.Blocked < 12*time.Millisecond || keys.Blocked >= slow.Blocked {
+		t.Error("invalid unnamed waiter", keys)
+	}
+
+	if r.Blocked != slow.Blocked+keys.Blocked+r.Waiters[2].Blocked {
+		t.Error("invalid total", r.Blocked)
+	}
+
+	if s := r.String(); !strings.Contains(s, "slow: ") {
+		t.Error("invalid report string", s)
+	}
+}
@@ -0,0 +1,34 @@
+package syncbus
+
+import (
+	"errors"
+	"time"
+)
+
+// WaitRetry is like Wait, but when it times out, it waits again, up to attempts times in total, sleeping
+// between the attempts. The sleep starts with backoff, and it doubles after every attempt. Every attempt checks
+// the signals again, so the keys that were reset meanwhile need to be set again. It is meant for the integration
+// tests against eventually consistent systems. It returns the error of the last attempt, and it doesn't retry
+// on errors other than timeout.
+//
+// If the receiver *SyncBus is nil, or no key argument is passed to it, it is a noop.
+func (b *SyncBus) WaitRetry(attempts int, backoff time.Duration, keys ...string) error {
+	if b == nil || len(keys) == 0 {
+		return nil
+	}
+
+	var err error
+	for i := 0; i < attempts || i == 0; i++ {
+		if i > 0 {
+			time.Sleep(backoff)
+			backoff *= 2
+		}
+
+		err = b.Wait(keys...)
+		if !errors.Is(err, ErrTimeout) {
+			return err
+		}
+	}
+
+	return err
+}
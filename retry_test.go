@@ -0,0 +1,52 @@
+package syncbus
+
+import (
+	"testing"
+	"time"
+)
+
+func TestNilWaitRetry(t *testing.T) {
+	var bus *SyncBus
+	if err := bus.WaitRetry(3, time.Millisecond, "foo"); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestWaitRetry(t *testing.T) {
+	bus := New(12 * time.Millisecond)
+	defer bus.Close()
+
+	go func() {
+		time.Sleep(36 * time.Millisecond)
+		bus.Signal("foo")
+	}()
+
+	if err := bus.WaitRetry(5, 3*time.Millisecond, "foo"); err != nil {
+		t.Error(err)
+	}
+
+	if s := bus.Stats(); s.Timeouts == 0 {
+		t.Error("failed to retry", s)
+	}
+}
+
+func TestWaitRetryGivesUp(t *testing.T) {
+	bus := New(3 * time.Millisecond)
+	defer bus.Close()
+
+	if err := bus.WaitRetry(3, time.Millisecond, "foo"); err != ErrTimeout {
+		t.Error("failed to timeout", err)
+	}
+
+	if s := bus.Stats(); s.Timeouts != 3 {
+		t.Error("invalid number of attempts", s)
+	}
+}
+
+func TestWaitRetryClosed(t *testing.T) {
+	bus := New(3 * time.Millisecond)
+	bus.Close()
+	if err := bus.WaitRetry(3, time.Hour, "foo"); err != ErrClosed {
+		t.Error("unexpected retry", err)
+	}
+}
@@ -0,0 +1,145 @@
+package syncbus
+
+import "strings"
+
+// KeyRule maps a key to a new one. When it returns false, the key is dropped.
+type KeyRule func(key string) (string, bool)
+
+// RewriteBus is a decorator that maps or filters the keys according to a set of rules before delegating the
+// calls to the wrapped bus.
+type RewriteBus struct {
+	bus   Bus
+	rules []KeyRule
+}
+
+// StripPrefix creates a rule that removes the prefix from the keys that have it.
+func StripPrefix(prefix string) KeyRule {
+	return func(key string) (string, bool) {
+		return strings.TrimPrefix(key, prefix), true
+	}
+}
+
+// AddPrefix creates a rule that prepends the prefix to every key.
+func AddPrefix(prefix string) KeyRule {
+	return func(key string) (string, bool) {
+		return prefix + key, true
+	}
+}
+
+// Rename creates a rule that replaces the key from with the key to.
+func Rename(from, to string) KeyRule {
+	return func(key string) (string, bool) {
+		if key == from {
+			return to, true
+		}
+
+		return key, true
+	}
+}
+
+// Drop creates a rule that drops the specified keys.
+func Drop(keys ...string) KeyRule {
+	m := make(map[string]bool)
+	for _, k := range keys {
+		m[k] = true
+	}
+
+	return func(key string) (string, bool) {
+		return key, !m[key]
+	}
+}
+
+// DropPrefix creates a rule that drops the keys with the prefix.
+func DropPrefix(prefix string) KeyRule {
+	return func(key string) (string, bool) {
+		return key, !strings.HasPrefix(key, prefix)
+	}
+}
+
+// Rewrite creates a decorator bus that applies the rules, in order, to every key before delegating to b.
+func Rewrite(b Bus, rules ...KeyRule) *RewriteBus {
+	return &RewriteBus{bus: b, rules: rules}
+}
+
+func (b *RewriteBus) mapKeys(keys []string) []string {
+	var mapped []string
+	for _, k := range keys {
+		keep := true
+		for _, r := range b.rules {
+			if k, keep = r(k); !keep {
+				break
+			}
+		}
+
+		if keep {
+			mapped = append(mapped, k)
+		}
+	}
+
+	return mapped
+}
+
+// Wait blocks until all the signals represented by the mapped keys are set. The dropped keys are not waited
+// for.
+//
+// If the receiver or the wrapped bus is nil, or all the keys were dropped, it is a noop.
+func (b *RewriteBus) Wait(keys ...string) error {
+	if b == nil || b.bus == nil {
+		return nil
+	}
+
+	keys = b.mapKeys(keys)
+	if len(keys) == 0 {
+		return nil
+	}
+
+	return b.bus.Wait(keys...)
+}
+
+// Signal sets the signals represented by the mapped keys.
+//
+// If the receiver or the wrapped bus is nil, or all the keys were dropped, it is a noop.
+func (b *RewriteBus) Signal(keys ...string) {
+	if b == nil || b.bus == nil {
+		return
+	}
+
+	if keys = b.mapKeys(keys); len(keys) > 0 {
+		b.bus.Signal(keys...)
+	}
+}
+
+// ResetSignals clears the signals represented by the mapped keys.
+//
+// If the receiver or the wrapped bus is nil, or all the keys were dropped, it is a noop.
+func (b *RewriteBus) ResetSignals(keys ...string) {
+	if b == nil || b.bus == nil {
+		return
+	}
+
+	if keys = b.mapKeys(keys); len(keys) > 0 {
+		b.bus.ResetSignals(keys...)
+	}
+}
+
+// Reset clears all the signals of the wrapped bus.
+//
+// If the receiver or the wrapped bus is nil, it is a noop.
+func (b *RewriteBus) Reset() {
+	if b == nil || b.bus == nil {
+		return
+	}
+
+	b.bus.Reset()
+}
+
+// Close tears down the wrapped bus.
+//
+// If the receiver or the wrapped bus is nil, it is a noop.
+func (b *RewriteBus) Close() {
+	if b == nil || b.bus == nil {
+		return
+	}
+
+	b.bus.Close()
+}
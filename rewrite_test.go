@@ -0,0 +1,81 @@
+package syncbus
+
+import (
+	"testing"
+	"time"
+)
+
+var (
+	_ Bus = (*SyncBus)(nil)
+	_ Bus = (*RewriteBus)(nil)
+)
+
+func TestRewriteNil(t *testing.T) {
+	var b *RewriteBus
+	if err := b.Wait("foo"); err != nil {
+		t.Error(err)
+	}
+
+	b.Signal("foo")
+	b.ResetSignals("foo")
+	b.Reset()
+	b.Close()
+
+	b = Rewrite(nil)
+	if err := b.Wait("foo"); err != nil {
+		t.Error(err)
+	}
+
+	b.Signal("foo")
+	b.Close()
+}
+
+func TestRewriteRules(t *testing.T) {
+	bus := New(12 * time.Millisecond)
+	defer bus.Close()
+
+	rb := Rewrite(
+		bus,
+		Drop("noise"),
+		DropPrefix("debug."),
+		StripPrefix("lib."),
+		Rename("ready", "initialized"),
+		AddPrefix("test."),
+	)
+
+	rb.Signal("lib.ready", "noise", "debug.tick")
+	if err := bus.Wait("test.initialized"); err != nil {
+		t.Error(err)
+	}
+
+	if err := bus.Wait("test.noise"); err != ErrTimeout {
+		t.Error("failed to drop key")
+	}
+
+	if err := rb.Wait("ready", "noise", "debug.foo"); err != nil {
+		t.Error(err)
+	}
+
+	if err := rb.Wait("noise"); err != nil {
+		t.Error(err)
+	}
+
+	rb.ResetSignals("lib.ready")
+	if err := bus.Wait("test.initialized"); err != ErrTimeout {
+		t.Error("failed to reset")
+	}
+
+	rb.Signal("foo")
+	rb.Reset()
+	if err := rb.Wait("foo"); err != ErrTimeout {
+		t.Error("failed to reset")
+	}
+}
+
+func TestRewriteClose(t *testing.T) {
+	bus := New(12 * time.Millisecond)
+	rb := Rewrite(bus)
+	rb.Close()
+	for range bus.Events() {
+	}
+}
@@ -0,0 +1,65 @@
+package syncbus
+
+import (
+	"os"
+	"strconv"
+	"time"
+)
+
+// TimeoutScaleEnv is the name of the environment variable that, when set to a positive number, multiplies all
+// the timeouts of the buses, regardless of the race detector. It can be used to indicate a slow mode, e.g.
+// when running the tests under a debugger.
+const TimeoutScaleEnv = "SYNCBUS_TIMEOUT_SCALE"
+
+// DefaultRaceScale is the multiplier applied to the timeouts of the buses created with NewForTest, when the
+// test binary was built with the race detector.
+const DefaultRaceScale = 4
+
+// WithRaceScale sets a multiplier, that is applied to all the timeouts of the bus, when the binary was built
+// with the race detector, e.g. with go test -race. The test suites that pass normally often flake under the
+// race detector only because of the slowdown. Defaults to 1, except for the buses created with NewForTest,
+// where it defaults to DefaultRaceScale. The environment variable SYNCBUS_TIMEOUT_SCALE overrides it.
+func WithRaceScale(multiplier float64) Option {
+	return func(o *options) { o.raceScale = multiplier }
+}
+
+func envTimeoutScale() (float64, bool) {
+	f, err := strconv.ParseFloat(os.Getenv(TimeoutScaleEnv), 64)
+	if err != nil || f <= 0 {
+		return 0, false
+	}
+
+	return f, true
+}
+
+func timeoutScale(o options) float64 {
+	if f, ok := envTimeoutScale(); ok {
+		return f
+	}
+
+	if raceEnabled && o.raceScale > 0 {
+		return o.raceScale
+	}
+
+	return 1
+}
+
+func (b *SyncBus) scaleTimeout(d time.Duration) time.Duration {
+	if b.scale == 1 {
+		return d
+	}
+
+	return time.Duration(float64(d) * b.scale)
+}
+
+// TimeoutScale returns the multiplier applied to the timeouts of the bus, see WithRaceScale and
+// SYNCBUS_TIMEOUT_SCALE.
+//
+// If the receiver *SyncBus is nil, it returns 1.
+func (b *SyncBus) TimeoutScale() float64 {
+	if b == nil {
+		return 1
+	}
+
+	return b.scale
+}
@@ -0,0 +1,52 @@
+package syncbus
+
+import (
+	"testing"
+	"time"
+)
+
+func TestTimeoutScale(t *testing.T) {
+	var bus *SyncBus
+	if s := bus.TimeoutScale(); s != 1 {
+		t.Error("unexpected scale", s)
+	}
+
+	bus = New(time.Second, WithRaceScale(3))
+	defer bus.Close()
+	expected := 1.0
+	if raceEnabled {
+		expected = 3
+	}
+
+	if s := bus.TimeoutScale(); s != expected {
+		t.Error("unexpected scale", s)
+	}
+}
+
+func TestTimeoutScaleEnv(t *testing.T) {
+	t.Setenv(TimeoutScaleEnv, "2")
+	bus := New(3*time.Millisecond, WithRaceScale(3))
+	defer bus.Close()
+
+	if s := bus.TimeoutScale(); s != 2 {
+		t.Fatal("unexpected scale", s)
+	}
+
+	start := time.Now()
+	if err := bus.WaitTimeout(6*time.Millisecond, "foo"); err != ErrTimeout {
+		t.Fatal("failed to timeout", err)
+	}
+
+	if d := time.Since(start); d < 12*time.Millisecond {
+		t.Error("timeout not scaled", d)
+	}
+}
+
+func TestTimeoutScaleInvalidEnv(t *testing.T) {
+	t.Setenv(TimeoutScaleEnv, "foo")
+	bus := New(time.Second)
+	defer bus.Close()
+	if s := bus.TimeoutScale(); s != 1 {
+		t.Error("unexpected scale", s)
+	}
+}
@@ -0,0 +1,113 @@
+package syncbus
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// decision represents a single random choice made by the bus, e.g. the duration of a jitter, or a position
+// in the order of releasing the waits.
+type decision struct {
+	float      bool
+	n          int64
+	value      int64
+	floatValue float64
+}
+
+func formatSchedule(d []decision) []byte {
+	var buf bytes.Buffer
+	for _, di := range d {
+		if di.float {
+			fmt.Fprintf(&buf, "f %s\n", strconv.FormatFloat(di.floatValue, 'g', -1, 64))
+			continue
+		}
+
+		fmt.Fprintf(&buf, "i %d %d\n", di.n, di.value)
+	}
+
+	return buf.Bytes()
+}
+
+func parseSchedule(b []byte) ([]decision, error) {
+	var d []decision
+	s := bufio.NewScanner(bytes.NewReader(b))
+	for line := 1; s.Scan(); line++ {
+		fields := strings.Fields(s.Text())
+		if len(fields) == 0 {
+			continue
+		}
+
+		var (
+			di  decision
+			err error
+		)
+
+		switch {
+		case fields[0] == "f" && len(fields) == 2:
+			di.float = true
+			di.floatValue, err = strconv.ParseFloat(fields[1], 64)
+		case fields[0] == "i" && len(fields) == 3:
+			if di.n, err = strconv.ParseInt(fields[1], 10, 64); err == nil {
+				di.value, err = strconv.ParseInt(fields[2], 10, 64)
+			}
+		default:
+			err = fmt.Errorf("invalid decision: %q", s.Text())
+		}
+
+		if err != nil {
+			return nil, fmt.Errorf("schedule line %d: %v", line, err)
+		}
+
+		d = append(d, di)
+	}
+
+	return d, s.Err()
+}
+
+func writeSchedule(path string, d []decision) error {
+	return ioutil.WriteFile(path, formatSchedule(d), 0644)
+}
+
+// RecordSchedule makes the bus record every random decision that it makes, e.g. the jitter durations and the
+// order of releasing the waits, and write them to the file at path when the bus is closed. The recorded
+// schedule can be replayed with ReplaySchedule.
+func RecordSchedule(path string) Option {
+	return func(o *options) { o.recordSchedule = path }
+}
+
+// ReplaySchedule reads a schedule recorded with RecordSchedule or saved by Stress, and returns an option that
+// makes the bus repeat the same random decisions, in the same order. When the run diverges from the recorded
+// one, the bus falls back to making the decisions based on its seed.
+func ReplaySchedule(path string) (Option, error) {
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	d, err := parseSchedule(b)
+	if err != nil {
+		return nil, err
+	}
+
+	return func(o *options) { o.replay = d }, nil
+}
+
+// WithScheduleDir sets the directory where Stress saves the schedules of the failed runs. Defaults to the
+// default directory for temporary files.
+func WithScheduleDir(dir string) Option {
+	return func(o *options) { o.scheduleDir = dir }
+}
+
+func withKeptSchedule() Option {
+	return func(o *options) { o.keepSchedule = true }
+}
+
+func saveStressSchedule(dir string, seed int64, d []decision) (string, error) {
+	path := filepath.Join(dir, fmt.Sprintf("syncbus-%d.schedule", seed))
+	return path, writeSchedule(path, d)
+}
@@ -0,0 +1,76 @@
+package syncbus
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestScheduleFormat(t *testing.T) {
+	d := []decision{
+		{n: 9, value: 3},
+		{float: true, floatValue: 0.25},
+		{n: 1 << 62, value: 42},
+	}
+
+	p, err := parseSchedule(formatSchedule(d))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(p) != len(d) {
+		t.Fatal("invalid schedule", p)
+	}
+
+	for i := range d {
+		if p[i] != d[i] {
+			t.Error("invalid decision", p[i], d[i])
+		}
+	}
+}
+
+func TestScheduleInvalid(t *testing.T) {
+	for _, s := range []string{"x 1", "i 1", "f foo", "i 1 foo"} {
+		if _, err := parseSchedule([]byte(s)); err == nil {
+			t.Error("failed to fail", s)
+		}
+	}
+
+	if _, err := ReplaySchedule(filepath.Join(t.TempDir(), "missing")); err == nil {
+		t.Error("failed to fail")
+	}
+}
+
+func runScheduled(t *testing.T, opts ...Option) []decision {
+	opts = append(opts, WithJitter(time.Millisecond), WithSampling(0.5), withShuffledWakeup(), withKeptSchedule())
+	bus := New(time.Millisecond, opts...)
+	bus.Signal("foo")
+	for i := 0; i < 9; i++ {
+		bus.Wait("foo")
+		bus.Wait("bar")
+	}
+
+	bus.Close()
+	return bus.rand.decisions()
+}
+
+func TestRecordReplay(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "schedule")
+	recorded := runScheduled(t, WithSeed(42), RecordSchedule(path))
+
+	replay, err := ReplaySchedule(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	replayed := runScheduled(t, WithSeed(36), replay)
+	if len(replayed) < len(recorded) {
+		t.Fatal("failed to replay the schedule")
+	}
+
+	for i := range recorded {
+		if replayed[i] != recorded[i] {
+			t.Fatal("failed to replay the schedule", i)
+		}
+	}
+}
@@ -0,0 +1,118 @@
+package syncbus
+
+import (
+	"strconv"
+	"sync"
+	"sync/atomic"
+)
+
+// Scope is a view of a SyncBus, whose signals are visible only to the waits created from the same view. The
+// waits of a scope are satisfied both by the signals of the scope, and by the global signals set directly on
+// the bus, so the shared infrastructure events can be signaled globally, while the events of each scenario
+// stay isolated in its own scope. It implements the Bus interface.
+type Scope struct {
+	bus    *SyncBus
+	prefix string
+	mx     sync.Mutex
+	keys   map[string]bool
+}
+
+// Scoped creates a new scope over the bus. The signals of the scope are stored by the bus under keys prefixed
+// with "scope/<id>/", where id is unique for each scope of the bus, and they appear so in the state and in the
+// events of the bus.
+//
+// If the receiver *SyncBus is nil, the returned scope is a noop.
+func (b *SyncBus) Scoped() *Scope {
+	if b == nil {
+		return &Scope{}
+	}
+
+	id := atomic.AddUint64(&b.scopes, 1)
+	return &Scope{
+		bus:    b,
+		prefix: "scope/" + strconv.FormatUint(id, 10) + "/",
+		keys:   make(map[string]bool),
+	}
+}
+
+func (s *Scope) scopedKeys(keys []string) []string {
+	scoped := make([]string, len(keys))
+	for i, key := range keys {
+		scoped[i] = s.prefix + key
+	}
+
+	return scoped
+}
+
+// Wait blocks until all the signals represented by the keys are set, either in the scope, or globally, or the
+// timeout expires.
+//
+// If the receiver or the underlying bus is nil, or no key argument is passed to it, it is a noop.
+func (s *Scope) Wait(keys ...string) error {
+	if s == nil || s.bus == nil || len(keys) == 0 {
+		return nil
+	}
+
+	c := Cond()
+	for _, key := range keys {
+		c.Any(key, s.prefix+key)
+	}
+
+	return s.bus.waitItem(waitItem{keys: c.keys(), cond: c})
+}
+
+// Signal sets one or more signals represented by the keys, visible only to the waits of the scope.
+//
+// If the receiver or the underlying bus is nil, or no key argument is passed to it, it is a noop.
+func (s *Scope) Signal(keys ...string) {
+	if s == nil || s.bus == nil || len(keys) == 0 {
+		return
+	}
+
+	s.mx.Lock()
+	for _, key := range keys {
+		s.keys[key] = true
+	}
+
+	s.mx.Unlock()
+	s.bus.Signal(s.scopedKeys(keys)...)
+}
+
+// ResetSignals clears the signals of the scope represented by the keys. It doesn't affect the global signals.
+//
+// If the receiver or the underlying bus is nil, or no key argument is passed to it, it is a noop.
+func (s *Scope) ResetSignals(keys ...string) {
+	if s == nil || s.bus == nil || len(keys) == 0 {
+		return
+	}
+
+	s.mx.Lock()
+	for _, key := range keys {
+		delete(s.keys, key)
+	}
+
+	s.mx.Unlock()
+	s.bus.ResetSignals(s.scopedKeys(keys)...)
+}
+
+// Reset clears all the signals of the scope. It doesn't affect the global signals.
+//
+// If the receiver or the underlying bus is nil, it is a noop.
+func (s *Scope) Reset() {
+	if s == nil || s.bus == nil {
+		return
+	}
+
+	s.mx.Lock()
+	var keys []string
+	for key := range s.keys {
+		keys = append(keys, key)
+	}
+
+	s.keys = make(map[string]bool)
+	s.mx.Unlock()
+	s.bus.ResetSignals(s.scopedKeys(keys)...)
+}
+
+// Close is a noop, the scope doesn't own the underlying bus.
+func (s *Scope) Close() {}
@@ -0,0 +1,77 @@
+package syncbus
+
+import (
+	"testing"
+	"time"
+)
+
+func TestNilScope(t *testing.T) {
+	var bus *SyncBus
+	s := bus.Scoped()
+	s.Signal("foo")
+	if err := s.Wait("foo"); err != nil {
+		t.Error(err)
+	}
+
+	s.ResetSignals("foo")
+	s.Reset()
+	s.Close()
+}
+
+func TestScope(t *testing.T) {
+	bus := New(3 * time.Millisecond)
+	defer bus.Close()
+
+	var _ Bus = bus.Scoped()
+	a, b := bus.Scoped(), bus.Scoped()
+	a.Signal("ready")
+	if err := a.Wait("ready"); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := b.Wait("ready"); err != ErrTimeout {
+		t.Fatal("scoped signal leaked to another scope", err)
+	}
+
+	if err := bus.Wait("ready"); err != ErrTimeout {
+		t.Fatal("scoped signal leaked to the bus", err)
+	}
+
+	bus.Signal("infra")
+	if err := b.Wait("infra"); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := a.Wait("infra", "ready"); err != nil {
+		t.Fatal(err)
+	}
+
+	a.Reset()
+	if err := a.Wait("ready"); err != ErrTimeout {
+		t.Fatal("failed to reset", err)
+	}
+
+	if err := a.Wait("infra"); err != nil {
+		t.Error("global signal reset by the scope", err)
+	}
+}
+
+func TestScopeWaitBeforeSignal(t *testing.T) {
+	bus := New(120 * time.Millisecond)
+	defer bus.Close()
+
+	s := bus.Scoped()
+	errs := make(chan error)
+	go func() { errs <- s.Wait("foo", "bar") }()
+	s.Signal("foo")
+	bus.Signal("bar")
+	if err := <-errs; err != nil {
+		t.Error(err)
+	}
+
+	bus.Signal("foo")
+	s.ResetSignals("foo")
+	if err := s.Wait("foo"); err != nil {
+		t.Error("failed to fall back to the global signal", err)
+	}
+}
@@ -0,0 +1,40 @@
+package syncbus
+
+// SignalSync sets one or more signals represented by the keys, like Signal, but it returns only after the run
+// loop has evaluated the pending waits, and the goroutines of the waits released by the signals have received
+// their result. It provides a strict point in the test, after which everyone who was waiting for the signals
+// has been woken. With WithPCT, the waits released by the later passes of the scheduler are not awaited.
+//
+// If the receiver *SyncBus is nil, or no key argument is passed to it, it is a noop.
+func (b *SyncBus) SignalSync(keys ...string) {
+	if b == nil || len(keys) == 0 {
+		return
+	}
+
+	s := signalItem{keys: keys, sync: make(chan []chan struct{}, 1)}
+	if !b.sendSignal(s) {
+		return
+	}
+
+	select {
+	case acks := <-s.sync:
+		for _, ack := range acks {
+			<-ack
+		}
+	case <-b.done:
+	}
+}
+
+// syncSignal reports the released waits to a SignalSync call.
+func (b *SyncBus) syncSignal(s signalItem, r []released) {
+	if s.sync == nil {
+		return
+	}
+
+	acks := make([]chan struct{}, 0, len(r))
+	for _, ri := range r {
+		acks = append(acks, ri.item.ack)
+	}
+
+	s.sync <- acks
+}
@@ -0,0 +1,57 @@
+package syncbus
+
+import (
+	"testing"
+	"time"
+)
+
+func TestNilSignalSync(t *testing.T) {
+	var bus *SyncBus
+	bus.SignalSync("foo")
+}
+
+func TestSignalSync(t *testing.T) {
+	bus := New(120 * time.Millisecond)
+	defer bus.Close()
+
+	errs := make(chan error, 3)
+	for i := 0; i < 2; i++ {
+		go func() { errs <- bus.Wait("foo") }()
+	}
+
+	go func() { errs <- bus.Wait("foo", "bar") }()
+	for bus.Stats().Waiting != 3 {
+		time.Sleep(time.Millisecond / 10)
+	}
+
+	bus.SignalSync("foo")
+	if s := bus.Stats(); s.Waiting != 1 || s.Releases != 2 {
+		t.Fatal("failed to release the waits synchronously", s)
+	}
+
+	for i := 0; i < 2; i++ {
+		if err := <-errs; err != nil {
+			t.Error(err)
+		}
+	}
+
+	bus.SignalSync("bar")
+	if err := <-errs; err != nil {
+		t.Error(err)
+	}
+}
+
+func TestSignalSyncNoWaits(t *testing.T) {
+	bus := New(120 * time.Millisecond)
+	defer bus.Close()
+	bus.SignalSync("foo")
+	if !bus.SynchronizeWith("foo") {
+		t.Error("failed to signal")
+	}
+}
+
+func TestSignalSyncAfterClose(t *testing.T) {
+	bus := New(120 * time.Millisecond)
+	bus.Close()
+	bus.SignalSync("foo")
+}
@@ -0,0 +1,280 @@
+/*
+Package sqlsync provides a database/sql driver wrapper that reports the activity of the wrapped driver through a
+syncbus.Bus, and can pause it at configured checkpoints, to allow deterministic testing of query interleaving.
+*/
+package sqlsync
+
+import (
+	"context"
+	"database/sql/driver"
+
+	"github.com/aryszka/syncbus"
+)
+
+// Events reported by the driver.
+const (
+	Open       = "open"
+	QueryBegin = "query.begin"
+	QueryEnd   = "query.end"
+	Begin      = "tx.begin"
+	Commit     = "tx.commit"
+	Rollback   = "tx.rollback"
+)
+
+// Driver wraps a driver.Driver, and signals the events of the connections, queries and transactions. The keys
+// are constructed from the name and the event, see Key. When an event is listed as a checkpoint, the driver
+// blocks after signaling its key until the test releases it with syncbus.Release, or the timeout of the bus
+// expires, in which case the operation fails with the timeout error. The commit and rollback events are
+// signaled before calling the wrapped transaction, so that a checkpoint can hold them before they take effect.
+// When the commit checkpoint fails, the transaction is rolled back.
+//
+// The connections forward the optional driver.NamedValueChecker, driver.Pinger, driver.SessionResetter and
+// driver.Validator interfaces, when the wrapped connections implement them.
+type Driver struct {
+
+	// Driver is the wrapped driver.
+	Driver driver.Driver
+
+	// Bus receives the signals. When nil, the driver only delegates.
+	Bus syncbus.Bus
+
+	// Name is used as the prefix of the keys.
+	Name string
+
+	// Checkpoints lists the events at which the driver blocks.
+	Checkpoints []string
+}
+
+type conn struct {
+	driver *Driver
+	conn   driver.Conn
+}
+
+type stmt struct {
+	driver *Driver
+	stmt   driver.Stmt
+}
+
+type tx struct {
+	driver *Driver
+	tx     driver.Tx
+}
+
+// Key returns the key signaled by the driver when the event happens.
+func (d *Driver) Key(event string) string {
+	return d.Name + "." + event
+}
+
+func (d *Driver) event(event string) error {
+	if d.Bus == nil {
+		return nil
+	}
+
+	key := d.Key(event)
+	for _, cp := range d.Checkpoints {
+		if cp == event {
+			return syncbus.Checkpoint(d.Bus, key)
+		}
+	}
+
+	d.Bus.Signal(key)
+	return nil
+}
+
+// Open opens a connection with the wrapped driver, and signals the open event.
+func (d *Driver) Open(name string) (driver.Conn, error) {
+	c, err := d.Driver.Open(name)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := d.event(Open); err != nil {
+		c.Close()
+		return nil, err
+	}
+
+	return &conn{driver: d, conn: c}, nil
+}
+
+func (c *conn) Prepare(query string) (driver.Stmt, error) {
+	s, err := c.conn.Prepare(query)
+	if err != nil {
+		return nil, err
+	}
+
+	return &stmt{driver: c.driver, stmt: s}, nil
+}
+
+func (c *conn) PrepareContext(ctx context.Context, query string) (driver.Stmt, error) {
+	pc, ok := c.conn.(driver.ConnPrepareContext)
+	if !ok {
+		return c.Prepare(query)
+	}
+
+	s, err := pc.PrepareContext(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+
+	return &stmt{driver: c.driver, stmt: s}, nil
+}
+
+func (c *conn) Close() error {
+	return c.conn.Close()
+}
+
+func (c *conn) CheckNamedValue(v *driver.NamedValue) error {
+	if nc, ok := c.conn.(driver.NamedValueChecker); ok {
+		return nc.CheckNamedValue(v)
+	}
+
+	return driver.ErrSkip
+}
+
+func (c *conn) Ping(ctx context.Context) error {
+	if p, ok := c.conn.(driver.Pinger); ok {
+		return p.Ping(ctx)
+	}
+
+	return nil
+}
+
+func (c *conn) ResetSession(ctx context.Context) error {
+	if sr, ok := c.conn.(driver.SessionResetter); ok {
+		return sr.ResetSession(ctx)
+	}
+
+	return nil
+}
+
+func (c *conn) IsValid() bool {
+	if v, ok := c.conn.(driver.Validator); ok {
+		return v.IsValid()
+	}
+
+	return true
+}
+
+func (c *conn) Begin() (driver.Tx, error) {
+	return c.BeginTx(context.Background(), driver.TxOptions{})
+}
+
+func (c *conn) BeginTx(ctx context.Context, opts driver.TxOptions) (driver.Tx, error) {
+	var (
+		t   driver.Tx
+		err error
+	)
+
+	if bc, ok := c.conn.(driver.ConnBeginTx); ok {
+		t, err = bc.BeginTx(ctx, opts)
+	} else {
+		t, err = c.conn.Begin()
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	if err := c.driver.event(Begin); err != nil {
+		t.Rollback()
+		return nil, err
+	}
+
+	return &tx{driver: c.driver, tx: t}, nil
+}
+
+func (c *conn) QueryContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	q, ok := c.conn.(driver.QueryerContext)
+	if !ok {
+		return nil, driver.ErrSkip
+	}
+
+	if err := c.driver.event(QueryBegin); err != nil {
+		return nil, err
+	}
+
+	rows, err := q.QueryContext(ctx, query, args)
+	if err == driver.ErrSkip {
+		return nil, err
+	}
+
+	if eerr := c.driver.event(QueryEnd); err == nil {
+		err = eerr
+	}
+
+	return rows, err
+}
+
+func (c *conn) ExecContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	e, ok := c.conn.(driver.ExecerContext)
+	if !ok {
+		return nil, driver.ErrSkip
+	}
+
+	if err := c.driver.event(QueryBegin); err != nil {
+		return nil, err
+	}
+
+	result, err := e.ExecContext(ctx, query, args)
+	if err == driver.ErrSkip {
+		return nil, err
+	}
+
+	if eerr := c.driver.event(QueryEnd); err == nil {
+		err = eerr
+	}
+
+	return result, err
+}
+
+func (s *stmt) Close() error {
+	return s.stmt.Close()
+}
+
+func (s *stmt) NumInput() int {
+	return s.stmt.NumInput()
+}
+
+func (s *stmt) Exec(args []driver.Value) (driver.Result, error) {
+	if err := s.driver.event(QueryBegin); err != nil {
+		return nil, err
+	}
+
+	result, err := s.stmt.Exec(args)
+	if eerr := s.driver.event(QueryEnd); err == nil {
+		err = eerr
+	}
+
+	return result, err
+}
+
+func (s *stmt) Query(args []driver.Value) (driver.Rows, error) {
+	if err := s.driver.event(QueryBegin); err != nil {
+		return nil, err
+	}
+
+	rows, err := s.stmt.Query(args)
+	if eerr := s.driver.event(QueryEnd); err == nil {
+		err = eerr
+	}
+
+	return rows, err
+}
+
+func (t *tx) Commit() error {
+	if err := t.driver.event(Commit); err != nil {
+		t.tx.Rollback()
+		return err
+	}
+
+	return t.tx.Commit()
+}
+
+func (t *tx) Rollback() error {
+	err := t.driver.event(Rollback)
+	if rerr := t.tx.Rollback(); rerr != nil {
+		return rerr
+	}
+
+	return err
+}
@@ -0,0 +1,267 @@
+package sqlsync
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/aryszka/syncbus"
+)
+
+type testDriver struct{}
+
+type testConn struct{}
+
+type testStmt struct{}
+
+type testTx struct{}
+
+type testRows struct{ n int }
+
+func (testDriver) Open(string) (driver.Conn, error) { return testConn{}, nil }
+
+func (testConn) Prepare(string) (driver.Stmt, error) { return testStmt{}, nil }
+func (testConn) Close() error                        { return nil }
+func (testConn) Begin() (driver.Tx, error)           { return testTx{}, nil }
+
+func (testStmt) Close() error                               { return nil }
+func (testStmt) NumInput() int                              { return 0 }
+func (testStmt) Exec([]driver.Value) (driver.Result, error) { return driver.RowsAffected(1), nil }
+func (testStmt) Query([]driver.Value) (driver.Rows, error)  { return &testRows{n: 1}, nil }
+
+func (testTx) Commit() error   { return nil }
+func (testTx) Rollback() error { return nil }
+
+func (*testRows) Columns() []string { return []string{"value"} }
+func (*testRows) Close() error      { return nil }
+
+func (r *testRows) Next(dest []driver.Value) error {
+	if r.n == 0 {
+		return io.EOF
+	}
+
+	r.n--
+	dest[0] = int64(42)
+	return nil
+}
+
+type dsnConnector struct{ d *Driver }
+
+func (c dsnConnector) Connect(context.Context) (driver.Conn, error) { return c.d.Open("") }
+func (c dsnConnector) Driver() driver.Driver                        { return c.d }
+
+func TestDriver(t *testing.T) {
+	bus := syncbus.New(12 * time.Millisecond)
+	defer bus.Close()
+
+	d := &Driver{Driver: testDriver{}, Bus: bus, Name: "db"}
+	db := sql.OpenDB(dsnConnector{d})
+	defer db.Close()
+
+	var v int
+	if err := db.QueryRow("select 42").Scan(&v); err != nil || v != 42 {
+		t.Fatal("failed to query", err, v)
+	}
+
+	if err := bus.Wait(d.Key(Open), d.Key(QueryBegin), d.Key(QueryEnd)); err != nil {
+		t.Error(err)
+	}
+
+	tx, err := db.Begin()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := tx.Exec("update foo"); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := bus.Wait(d.Key(Begin), d.Key(Commit)); err != nil {
+		t.Error(err)
+	}
+
+	tx, err = db.Begin()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := tx.Rollback(); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := bus.Wait(d.Key(Rollback)); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestDriverCheckpoint(t *testing.T) {
+	bus := syncbus.New(120 * time.Millisecond)
+	defer bus.Close()
+
+	d := &Driver{Driver: testDriver{}, Bus: bus, Name: "db", Checkpoints: []string{QueryBegin}}
+	db := sql.OpenDB(dsnConnector{d})
+	defer db.Close()
+
+	done := make(chan error)
+	go func() {
+		_, err := db.Exec("update foo")
+		done <- err
+	}()
+
+	if err := bus.Wait(d.Key(QueryBegin)); err != nil {
+		t.Fatal(err)
+	}
+
+	select {
+	case <-done:
+		t.Fatal("failed to block at checkpoint")
+	default:
+	}
+
+	syncbus.Release(bus, d.Key(QueryBegin))
+	if err := <-done; err != nil {
+		t.Error(err)
+	}
+
+	if err := bus.Wait(d.Key(QueryEnd)); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestDriverCheckpointTimeout(t *testing.T) {
+	bus := syncbus.New(12 * time.Millisecond)
+	defer bus.Close()
+
+	d := &Driver{Driver: testDriver{}, Bus: bus, Name: "db", Checkpoints: []string{QueryBegin}}
+	db := sql.OpenDB(dsnConnector{d})
+	defer db.Close()
+
+	if _, err := db.Exec("update foo"); err != syncbus.ErrTimeout {
+		t.Error("failed to timeout", err)
+	}
+}
+
+func TestDriverNilBus(t *testing.T) {
+	d := &Driver{Driver: testDriver{}}
+	db := sql.OpenDB(dsnConnector{d})
+	defer db.Close()
+
+	if _, err := db.Exec("update foo"); err != nil {
+		t.Error(err)
+	}
+}
+
+type optionalConn struct {
+	testConn
+	calls map[string]bool
+}
+
+type recordingTx struct{ committed chan struct{} }
+
+type txDriver struct{ tx recordingTx }
+
+type txConn struct {
+	testConn
+	tx recordingTx
+}
+
+func (c optionalConn) CheckNamedValue(*driver.NamedValue) error { c.calls["check"] = true; return nil }
+func (c optionalConn) Ping(context.Context) error               { c.calls["ping"] = true; return nil }
+func (c optionalConn) ResetSession(context.Context) error       { c.calls["reset"] = true; return nil }
+func (c optionalConn) IsValid() bool                            { c.calls["valid"] = true; return false }
+
+func (tx recordingTx) Commit() error { close(tx.committed); return nil }
+func (recordingTx) Rollback() error  { return nil }
+
+func (d txDriver) Open(string) (driver.Conn, error) { return txConn{tx: d.tx}, nil }
+
+func (c txConn) Begin() (driver.Tx, error) { return c.tx, nil }
+
+func TestConnOptionalInterfaces(t *testing.T) {
+	d := &Driver{Driver: testDriver{}}
+	oc := optionalConn{calls: make(map[string]bool)}
+	c := &conn{driver: d, conn: oc}
+	if err := c.CheckNamedValue(&driver.NamedValue{}); err != nil {
+		t.Error(err)
+	}
+
+	if err := c.Ping(context.Background()); err != nil {
+		t.Error(err)
+	}
+
+	if err := c.ResetSession(context.Background()); err != nil {
+		t.Error(err)
+	}
+
+	if c.IsValid() {
+		t.Error("failed to forward validation")
+	}
+
+	for _, call := range []string{"check", "ping", "reset", "valid"} {
+		if !oc.calls[call] {
+			t.Error("failed to forward", call)
+		}
+	}
+
+	c = &conn{driver: d, conn: testConn{}}
+	if err := c.CheckNamedValue(&driver.NamedValue{}); err != driver.ErrSkip {
+		t.Error("failed to skip value check", err)
+	}
+
+	if err := c.Ping(context.Background()); err != nil {
+		t.Error(err)
+	}
+
+	if err := c.ResetSession(context.Background()); err != nil {
+		t.Error(err)
+	}
+
+	if !c.IsValid() {
+		t.Error("invalid connection")
+	}
+}
+
+func TestDriverCommitCheckpoint(t *testing.T) {
+	bus := syncbus.New(120 * time.Millisecond)
+	defer bus.Close()
+
+	rtx := recordingTx{committed: make(chan struct{})}
+	d := &Driver{Driver: txDriver{tx: rtx}, Bus: bus, Name: "db", Checkpoints: []string{Commit}}
+	db := sql.OpenDB(dsnConnector{d})
+	defer db.Close()
+
+	tx, err := db.Begin()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	done := make(chan error)
+	go func() { done <- tx.Commit() }()
+	if err := bus.Wait(d.Key(Commit)); err != nil {
+		t.Fatal(err)
+	}
+
+	select {
+	case <-rtx.committed:
+		t.Fatal("committed before the checkpoint was released")
+	default:
+	}
+
+	syncbus.Release(bus, d.Key(Commit))
+	if err := <-done; err != nil {
+		t.Error(err)
+	}
+
+	select {
+	case <-rtx.committed:
+	default:
+		t.Error("failed to commit")
+	}
+}
@@ -0,0 +1,193 @@
+package syncbus
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+	"strings"
+	"sync/atomic"
+	"time"
+)
+
+type counters struct {
+	waits     uint64
+	releases  uint64
+	timeouts  uint64
+	signals   uint64
+	throttled uint64
+}
+
+type waitInfo struct {
+	keys      []string
+	missing   []string
+	namespace string
+	name      string
+	labels    map[string]string
+	remaining time.Duration
+}
+
+type snapshot struct {
+	signals    []string
+	waiting    []waitInfo
+	stats      Stats
+	violations []Violation
+	report     Report
+	labels     map[label]Stats
+	latency    map[LoopOp]OpLatency
+}
+
+// Stats contains the current size and the cumulative counters of a bus.
+type Stats struct {
+
+	// Signals is the number of the currently set signals.
+	Signals int
+
+	// Waiting is the number of the currently pending waits.
+	Waiting int
+
+	// Waits is the number of registered waits since the bus was created.
+	Waits uint64
+
+	// Releases is the number of waits that were released, successfully or with an error other than
+	// timeout.
+	Releases uint64
+
+	// Timeouts is the number of waits that timed out.
+	Timeouts uint64
+
+	// SignalCalls is the number of times signals were set.
+	SignalCalls uint64
+
+	// DroppedEvents is the number of the dropped events of the event stream.
+	DroppedEvents uint64
+
+	// ThrottledSignals is the number of the signals dropped by the throttles set with WithThrottle.
+	ThrottledSignals uint64
+
+	// DroppedHistory is the number of the events dropped from the history, because it exceeded its capacity.
+	DroppedHistory uint64
+
+	// ProducerWaits is the number of the Signal, ResetSignals and Reset calls that had to wait for the run loop
+	// to receive them, see also WithSignalBuffer.
+	ProducerWaits uint64
+}
+
+func (b *SyncBus) missing(keys []string) []string {
+	var m []string
+	for _, key := range keys {
+		if !b.signals.has(key) {
+			m = append(m, key)
+		}
+	}
+
+	return m
+}
+
+func (b *SyncBus) createSnapshot(now time.Time) snapshot {
+	var s snapshot
+	s.signals = b.signals.list()
+
+	sort.Strings(s.signals)
+	for _, w := range b.waiting {
+		s.waiting = append(s.waiting, waitInfo{
+			keys:      w.keys,
+			missing:   b.missing(w.keys),
+			namespace: w.namespace,
+			name:      w.name,
+			labels:    w.labels,
+			remaining: w.deadline.Sub(now),
+		})
+	}
+
+	s.stats = b.createStats()
+	s.violations = append([]Violation(nil), b.violations...)
+	s.report = b.createReport()
+	s.labels = b.createLabelStats()
+	s.latency = b.createLatency()
+	return s
+}
+
+func (b *SyncBus) createStats() Stats {
+	return Stats{
+		Signals:          b.signals.len(),
+		Waiting:          len(b.waiting) + b.pctHeld(),
+		Waits:            b.counters.waits + atomic.LoadUint64(&b.fastHit),
+		Releases:         b.counters.releases + atomic.LoadUint64(&b.fastHit),
+		Timeouts:         b.counters.timeouts,
+		SignalCalls:      b.counters.signals,
+		DroppedEvents:    atomic.LoadUint64(&b.dropped),
+		ThrottledSignals: b.counters.throttled,
+		DroppedHistory:   b.history.dropped,
+		ProducerWaits:    atomic.LoadUint64(&b.stalled),
+	}
+}
+
+func (b *SyncBus) getSnapshot() snapshot {
+	c := make(chan snapshot, 1)
+	if b.closed() {
+		return snapshot{}
+	}
+
+	select {
+	case b.snapshot <- c:
+		return <-c
+	case <-b.done:
+		return snapshot{}
+	}
+}
+
+// Stats returns the current size and the cumulative counters of the bus. With WithReadMostlyState, it doesn't
+// go through the run loop.
+//
+// If the receiver *SyncBus is nil, it returns zero stats.
+func (b *SyncBus) Stats() Stats {
+	if b == nil {
+		return Stats{}
+	}
+
+	if b.view != nil && !b.closed() {
+		return b.readStats()
+	}
+
+	return b.getSnapshot().stats
+}
+
+func (s snapshot) String() string {
+	var buf bytes.Buffer
+	fmt.Fprintln(&buf, "signals:")
+	for _, key := range s.signals {
+		fmt.Fprintf(&buf, "  %s\n", key)
+	}
+
+	fmt.Fprintln(&buf, "waiting:")
+	for _, w := range s.waiting {
+		fmt.Fprintf(&buf, "  keys: %s; missing: %s", strings.Join(w.keys, ", "), strings.Join(w.missing, ", "))
+		if w.namespace != "" {
+			fmt.Fprintf(&buf, "; namespace: %s", w.namespace)
+		}
+
+		if w.name != "" {
+			fmt.Fprintf(&buf, "; name: %s", w.name)
+		}
+
+		if len(w.labels) > 0 {
+			fmt.Fprintf(&buf, "; labels: %s", formatLabels(w.labels))
+		}
+
+		fmt.Fprintf(&buf, "; remaining: %v\n", w.remaining)
+	}
+
+	return buf.String()
+}
+
+// Dump returns a human readable description of the current state of the bus: the set signals, and the pending
+// waits with the keys that they are still missing.
+//
+// If the receiver *SyncBus is nil, it returns an empty string.
+func (b *SyncBus) Dump() string {
+	if b == nil {
+		return ""
+	}
+
+	return b.getSnapshot().String()
+}
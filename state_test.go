@@ -0,0 +1,72 @@
+package syncbus
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestNilStats(t *testing.T) {
+	var bus *SyncBus
+	if bus.Stats() != (Stats{}) {
+		t.Error("unexpected stats")
+	}
+
+	if bus.Dump() != "" {
+		t.Error("unexpected dump")
+	}
+}
+
+func TestStats(t *testing.T) {
+	bus := New(12 * time.Millisecond)
+	defer bus.Close()
+
+	bus.Signal("foo", "bar")
+	if err := bus.Wait("foo"); err != nil {
+		t.Error(err)
+	}
+
+	if err := bus.Wait("baz"); err != ErrTimeout {
+		t.Error("failed to timeout")
+	}
+
+	tw := newTestWait(1)
+	go func() {
+		bus.Wait("qux")
+		tw.done()
+	}()
+
+	time.Sleep(3 * time.Millisecond)
+	s := bus.Stats()
+	if s.Signals != 2 || s.Waiting != 1 || s.Waits != 3 || s.Releases != 1 || s.Timeouts != 1 || s.SignalCalls != 1 {
+		t.Error("invalid stats", s)
+	}
+
+	if err := tw.wait(); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestDump(t *testing.T) {
+	bus := New(120 * time.Millisecond)
+	defer bus.Close()
+
+	bus.Signal("foo")
+	tw := newTestWait(1)
+	go func() {
+		bus.Namespace("test").Wait("foo", "bar")
+		tw.done()
+	}()
+
+	time.Sleep(3 * time.Millisecond)
+	d := bus.Dump()
+	if !strings.Contains(d, "signals:\n  foo\n") ||
+		!strings.Contains(d, "keys: foo, bar; missing: bar; namespace: test") {
+		t.Error("invalid dump", d)
+	}
+
+	bus.Signal("bar")
+	if err := tw.wait(); err != nil {
+		t.Error(err)
+	}
+}
@@ -0,0 +1,110 @@
+package syncbus
+
+type value struct {
+	value interface{}
+	ok    bool
+}
+
+type getItem struct {
+	key    string
+	last   bool
+	result chan value
+}
+
+// Put stores the value under key, and sets the signal represented by key, as a single operation. A Get call
+// made after a successful Wait on the same key is race-free, and it returns the stored value, so it can be used
+// to pass a small piece of data alongside a signal. The value is kept until the signal is reset.
+//
+// If the receiver *SyncBus is nil, it is a noop.
+func (b *SyncBus) Put(key string, v interface{}) {
+	if b == nil {
+		return
+	}
+
+	b.sendSignal(signalItem{keys: []string{key}, value: &value{value: v, ok: true}})
+}
+
+// Get returns the value stored under key by Put, and whether the value exists. The value exists while the
+// signal represented by key is set.
+//
+// If the receiver *SyncBus is nil, it returns nil and false.
+func (b *SyncBus) Get(key string) (interface{}, bool) {
+	if b == nil {
+		return nil, false
+	}
+
+	return b.getValue(getItem{key: key, result: make(chan value, 1)})
+}
+
+func (b *SyncBus) getValue(g getItem) (interface{}, bool) {
+	if b.closed() {
+		return nil, false
+	}
+
+	select {
+	case b.get <- g:
+		v := <-g.result
+		return v.value, v.ok
+	case <-b.done:
+		return nil, false
+	}
+}
+
+// Last returns the most recent value stored under key by Put, and whether such value exists. Unlike Get, it
+// returns the value even after the signal represented by key was reset, until it is cleared with ClearLast. It
+// allows post-hoc assertions about the values reported by the tested code.
+//
+// If the receiver *SyncBus is nil, it returns nil and false.
+func (b *SyncBus) Last(key string) (interface{}, bool) {
+	if b == nil {
+		return nil, false
+	}
+
+	return b.getValue(getItem{key: key, last: true, result: make(chan value, 1)})
+}
+
+// ClearLast clears the most recent values stored under the keys, as returned by Last. When no key argument is
+// passed to it, it clears the most recent values of all the keys.
+//
+// If the receiver *SyncBus is nil, it is a noop.
+func (b *SyncBus) ClearLast(keys ...string) {
+	if b == nil {
+		return
+	}
+
+	if b.closed() {
+		return
+	}
+
+	select {
+	case b.clearLast <- keys:
+	case <-b.done:
+	}
+}
+
+func (b *SyncBus) lookupValue(g getItem) value {
+	values := b.values
+	if g.last {
+		values = b.last
+	}
+
+	v, ok := values[g.key]
+	return value{value: v, ok: ok}
+}
+
+func (b *SyncBus) clearLastValues(keys []string) {
+	if len(keys) == 0 {
+		b.last = make(map[string]interface{})
+		return
+	}
+
+	for _, key := range keys {
+		delete(b.last, key)
+	}
+}
+
+// Value returns the value stored under key by Put, and whether the value exists.
+func (s State) Value(key string) (interface{}, bool) {
+	v, ok := s.bus.values[key]
+	return v, ok
+}
@@ -0,0 +1,118 @@
+package syncbus
+
+import (
+	"testing"
+	"time"
+)
+
+func TestNilStore(t *testing.T) {
+	var bus *SyncBus
+	bus.Put("foo", 42)
+	if v, ok := bus.Get("foo"); ok || v != nil {
+		t.Error("unexpected value", v)
+	}
+}
+
+func TestPutGet(t *testing.T) {
+	bus := New(120 * time.Millisecond)
+	defer bus.Close()
+
+	type result struct{ items []string }
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		if err := bus.Wait("result"); err != nil {
+			t.Error(err)
+			return
+		}
+
+		v, ok := bus.Get("result")
+		if !ok || len(v.(*result).items) != 2 {
+			t.Error("failed to get the value", v)
+		}
+	}()
+
+	bus.Put("result", &result{items: []string{"foo", "bar"}})
+	<-done
+}
+
+func TestGetMissing(t *testing.T) {
+	bus := New(120 * time.Millisecond)
+	defer bus.Close()
+
+	bus.Signal("foo")
+	if _, ok := bus.Get("foo"); ok {
+		t.Error("unexpected value")
+	}
+}
+
+func TestValueReset(t *testing.T) {
+	bus := New(120 * time.Millisecond)
+	defer bus.Close()
+
+	bus.Put("foo", 1)
+	bus.Put("bar", 2)
+	bus.Signal("foo")
+	if v, ok := bus.Get("foo"); !ok || v != 1 {
+		t.Error("failed to keep the value", v)
+	}
+
+	bus.ResetSignals("foo")
+	if _, ok := bus.Get("foo"); ok {
+		t.Error("failed to clear the value")
+	}
+
+	if err := bus.WaitFor(func(s State) bool { v, ok := s.Value("bar"); return ok && v == 2 }); err != nil {
+		t.Error(err)
+	}
+
+	bus.Reset()
+	if _, ok := bus.Get("bar"); ok {
+		t.Error("failed to clear the value")
+	}
+}
+
+func TestGetAfterClose(t *testing.T) {
+	bus := New(120 * time.Millisecond)
+	bus.Put("foo", 1)
+	bus.Close()
+	if _, ok := bus.Get("foo"); ok {
+		t.Error("unexpected value")
+	}
+}
+
+func TestNilLast(t *testing.T) {
+	var bus *SyncBus
+	bus.ClearLast()
+	if v, ok := bus.Last("foo"); ok || v != nil {
+		t.Error("unexpected value", v)
+	}
+}
+
+func TestLast(t *testing.T) {
+	bus := New(120 * time.Millisecond)
+	defer bus.Close()
+
+	bus.Put("foo", 1)
+	bus.Put("foo", 2)
+	bus.Put("bar", 3)
+	bus.Put("baz", 4)
+	bus.Reset()
+	if v, ok := bus.Last("foo"); !ok || v != 2 {
+		t.Error("failed to keep the last value", v)
+	}
+
+	bus.ClearLast("foo")
+	if _, ok := bus.Last("foo"); ok {
+		t.Error("failed to clear the last value")
+	}
+
+	if v, ok := bus.Last("bar"); !ok || v != 3 {
+		t.Error("failed to keep the last value", v)
+	}
+
+	bus.ClearLast()
+	if _, ok := bus.Last("baz"); ok {
+		t.Error("failed to clear the last values")
+	}
+}
@@ -0,0 +1,139 @@
+package syncbus
+
+import "io"
+
+// StreamOptions configure the signals of SignalingReader and SignalingWriter. The signaled keys are constructed
+// from Key: Key + ".bytes" is signaled once AfterBytes bytes were transferred, Key + ".eof" on EOF, and Key +
+// ".error" when the underlying reader or writer returns an error other than EOF.
+type StreamOptions struct {
+
+	// Bus receives the signals. When nil, the wrappers only delegate.
+	Bus Bus
+
+	// Key is the prefix of the signaled keys.
+	Key string
+
+	// AfterBytes sets the byte boundary at which the bytes signal is set. When 0, the signal is disabled.
+	AfterBytes int64
+
+	// PauseAfterBytes makes the stream pause at the byte boundary until the checkpoint Key + ".bytes" is
+	// released with Release.
+	PauseAfterBytes bool
+
+	// PauseOnEOF makes the stream pause on EOF until the checkpoint Key + ".eof" is released.
+	PauseOnEOF bool
+
+	// PauseOnError makes the stream pause on errors until the checkpoint Key + ".error" is released.
+	PauseOnError bool
+}
+
+// SignalingReader wraps an io.Reader, and signals when a configured number of bytes were read, on EOF, or on
+// error. It never reads across the byte boundary in a single call, so that a stream can be frozen at a precise
+// position: when pausing is enabled, the read following the boundary blocks until the checkpoint is released.
+type SignalingReader struct {
+	reader  io.Reader
+	options StreamOptions
+	count   int64
+	pause   bool
+}
+
+// SignalingWriter wraps an io.Writer, and signals when a configured number of bytes were written, or on error.
+// It splits the writes crossing the byte boundary, and when pausing is enabled, it blocks at the boundary until
+// the checkpoint is released.
+type SignalingWriter struct {
+	writer  io.Writer
+	options StreamOptions
+	count   int64
+}
+
+func (o StreamOptions) signal(event string, pause bool) error {
+	if o.Bus == nil {
+		return nil
+	}
+
+	key := o.Key + "." + event
+	if pause {
+		return Checkpoint(o.Bus, key)
+	}
+
+	o.Bus.Signal(key)
+	return nil
+}
+
+func (o StreamOptions) signalErr(err error) error {
+	if err == nil {
+		return nil
+	}
+
+	if err == io.EOF {
+		o.signal("eof", o.PauseOnEOF)
+		return err
+	}
+
+	o.signal("error", o.PauseOnError)
+	return err
+}
+
+// NewSignalingReader creates a reader wrapping r.
+func NewSignalingReader(r io.Reader, o StreamOptions) *SignalingReader {
+	return &SignalingReader{reader: r, options: o}
+}
+
+// Read reads from the underlying reader, and signals the configured events.
+func (r *SignalingReader) Read(p []byte) (int, error) {
+	if r.pause {
+		r.pause = false
+		if err := Checkpoint(r.options.Bus, r.options.Key+".bytes"); err != nil {
+			return 0, err
+		}
+	}
+
+	remaining := r.options.AfterBytes - r.count
+	if remaining > 0 && int64(len(p)) > remaining {
+		p = p[:remaining]
+	}
+
+	n, err := r.reader.Read(p)
+	r.count += int64(n)
+	if remaining > 0 && r.count >= r.options.AfterBytes {
+		r.options.signal("bytes", false)
+		r.pause = r.options.PauseAfterBytes
+	}
+
+	return n, r.options.signalErr(err)
+}
+
+// NewSignalingWriter creates a writer wrapping w.
+func NewSignalingWriter(w io.Writer, o StreamOptions) *SignalingWriter {
+	return &SignalingWriter{writer: w, options: o}
+}
+
+func (w *SignalingWriter) write(p []byte) (int, error) {
+	n, err := w.writer.Write(p)
+	w.count += int64(n)
+	return n, w.options.signalErr(err)
+}
+
+// Write writes to the underlying writer, and signals the configured events.
+func (w *SignalingWriter) Write(p []byte) (int, error) {
+	remaining := w.options.AfterBytes - w.count
+	if remaining <= 0 || int64(len(p)) < remaining {
+		return w.write(p)
+	}
+
+	n, err := w.write(p[:remaining])
+	if err != nil {
+		return n, err
+	}
+
+	if err := w.options.signal("bytes", w.options.PauseAfterBytes); err != nil {
+		return n, err
+	}
+
+	if int64(len(p)) == remaining {
+		return n, nil
+	}
+
+	m, err := w.write(p[remaining:])
+	return n + m, err
+}
@@ -0,0 +1,127 @@
+package syncbus
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"io/ioutil"
+	"strings"
+	"testing"
+	"time"
+)
+
+type failingIO struct{}
+
+var errTest = errors.New("test error")
+
+func (failingIO) Read([]byte) (int, error)  { return 0, errTest }
+func (failingIO) Write([]byte) (int, error) { return 0, errTest }
+
+func TestSignalingReaderNilBus(t *testing.T) {
+	r := NewSignalingReader(strings.NewReader("foobar"), StreamOptions{AfterBytes: 3, PauseAfterBytes: true})
+	b, err := ioutil.ReadAll(r)
+	if err != nil || string(b) != "foobar" {
+		t.Error("failed to read", err, string(b))
+	}
+}
+
+func TestSignalingReader(t *testing.T) {
+	bus := New(120 * time.Millisecond)
+	defer bus.Close()
+
+	r := NewSignalingReader(strings.NewReader("foobarbaz"), StreamOptions{
+		Bus:             bus,
+		Key:             "body",
+		AfterBytes:      3,
+		PauseAfterBytes: true,
+	})
+
+	result := make(chan string)
+	var read bytes.Buffer
+	go func() {
+		io.Copy(&read, r)
+		result <- read.String()
+	}()
+
+	if err := bus.Wait("body.bytes"); err != nil {
+		t.Fatal(err)
+	}
+
+	time.Sleep(3 * time.Millisecond)
+	if s := bus.Stats(); s.Waiting != 1 || s.Signals != 1 {
+		t.Fatal("failed to pause")
+	}
+
+	Release(bus, "body.bytes")
+	if err := bus.Wait("body.eof"); err != nil {
+		t.Error(err)
+	}
+
+	if s := <-result; s != "foobarbaz" {
+		t.Error("invalid result", s)
+	}
+}
+
+func TestSignalingReaderError(t *testing.T) {
+	bus := New(12 * time.Millisecond)
+	defer bus.Close()
+
+	r := NewSignalingReader(failingIO{}, StreamOptions{Bus: bus, Key: "body"})
+	if _, err := r.Read(make([]byte, 3)); err != errTest {
+		t.Error("failed to return error", err)
+	}
+
+	if err := bus.Wait("body.error"); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestSignalingWriter(t *testing.T) {
+	bus := New(120 * time.Millisecond)
+	defer bus.Close()
+
+	var buf bytes.Buffer
+	w := NewSignalingWriter(&buf, StreamOptions{
+		Bus:             bus,
+		Key:             "upload",
+		AfterBytes:      3,
+		PauseAfterBytes: true,
+	})
+
+	done := make(chan error)
+	go func() {
+		_, err := w.Write([]byte("foobar"))
+		done <- err
+	}()
+
+	if err := bus.Wait("upload.bytes"); err != nil {
+		t.Fatal(err)
+	}
+
+	if buf.String() != "foo" {
+		t.Error("failed to pause at the boundary", buf.String())
+	}
+
+	Release(bus, "upload.bytes")
+	if err := <-done; err != nil {
+		t.Error(err)
+	}
+
+	if buf.String() != "foobar" {
+		t.Error("invalid result", buf.String())
+	}
+}
+
+func TestSignalingWriterError(t *testing.T) {
+	bus := New(12 * time.Millisecond)
+	defer bus.Close()
+
+	w := NewSignalingWriter(failingIO{}, StreamOptions{Bus: bus, Key: "upload", AfterBytes: 3})
+	if _, err := w.Write([]byte("foobar")); err != errTest {
+		t.Error("failed to return error", err)
+	}
+
+	if err := bus.Wait("upload.error"); err != nil {
+		t.Error(err)
+	}
+}
@@ -0,0 +1,115 @@
+package syncbus
+
+import (
+	"fmt"
+	"testing"
+	"time"
+)
+
+// DefaultTimeout is the timeout of the buses created by the helpers of the package, when it is not set with
+// WithTimeout.
+const DefaultTimeout = 3 * time.Second
+
+// DefaultStressJitter is the maximum jitter injected by Stress, when it is not set with WithJitter.
+const DefaultStressJitter = time.Millisecond
+
+type stressFailure struct {
+	seed     int64
+	reason   string
+	schedule string
+}
+
+// runStress executes the body in its own goroutine, so that a runtime.Goexit, e.g. called by t.FailNow, stops
+// only the body, and the failure can be attributed to the current run.
+func runStress(b *SyncBus, body func(b *SyncBus)) (reason string) {
+	done := make(chan struct{})
+	go func() {
+		var returned bool
+		defer close(done)
+		defer func() {
+			if err := recover(); err != nil {
+				reason = fmt.Sprintf("panic: %v", err)
+			} else if !returned {
+				reason = "stopped by runtime.Goexit"
+			}
+		}()
+
+		body(b)
+		returned = true
+	}()
+
+	<-done
+	if reason != "" {
+		return reason
+	}
+
+	if s := b.Stats(); s.Timeouts > 0 {
+		return fmt.Sprintf("%d wait(s) timed out", s.Timeouts)
+	}
+
+	return ""
+}
+
+// Stress executes body n times, every time with a fresh bus, using a different seed for each run. The buses
+// inject random delays into the Wait calls (see WithJitter), and release the simultaneously satisfied waits in
+// a random order, to explore different schedules of the tested goroutines. The provided options are applied to
+// every bus, except for the seed.
+//
+// Every run executes the body in a separate goroutine. A run is considered failed when the body panics, when it
+// stops with runtime.Goexit, e.g. because it called t.Fatal or t.FailNow, when any wait on the bus times out, or
+// when the test gets marked as failed during the run. Since the last one can only be observed as a change of
+// t.Failed, the runs that only call t.Error are not detected once the test was already marked as failed, and it
+// is recommended to use t.Fatal in the body. Once all the runs completed, the failed ones are reported together
+// with their seeds, which can be used with WithSeed to reproduce them. The schedules of the failed runs are
+// saved in the directory set by WithScheduleDir, or in the default directory for temporary files, and they can
+// be replayed with ReplaySchedule. When the environment variable SYNCBUS_SEED is set, it is used as the seed of
+// the first run.
+func Stress(t testing.TB, n int, body func(b *SyncBus), opts ...Option) {
+	t.Helper()
+
+	var failures []stressFailure
+	base, ok := envSeed(t)
+	if !ok {
+		base = time.Now().UnixNano()
+	}
+
+	for i := 0; i < n; i++ {
+		seed := base + int64(i)
+		o := append([]Option{WithTimeout(DefaultTimeout), WithJitter(DefaultStressJitter)}, opts...)
+		o = append(o, WithSeed(seed), withShuffledWakeup(), withKeptSchedule())
+
+		failedBefore := t.Failed()
+		b := New(DefaultTimeout, o...)
+		reason := runStress(b, body)
+		b.Close()
+		if reason == "" && !failedBefore && t.Failed() {
+			reason = "test failed"
+		}
+
+		if reason == "" {
+			continue
+		}
+
+		f := stressFailure{seed: seed, reason: reason}
+		path, err := saveStressSchedule(b.options.scheduleDir, seed, b.rand.decisions())
+		if err == nil {
+			f.schedule = path
+		}
+
+		failures = append(failures, f)
+	}
+
+	if len(failures) == 0 {
+		return
+	}
+
+	msg := fmt.Sprintf("%d of %d stress runs failed:", len(failures), n)
+	for _, f := range failures {
+		msg += fmt.Sprintf("\n  seed %d: %s", f.seed, f.reason)
+		if f.schedule != "" {
+			msg += fmt.Sprintf(", schedule: %s", f.schedule)
+		}
+	}
+
+	t.Error(msg)
+}
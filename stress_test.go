@@ -0,0 +1,109 @@
+package syncbus
+
+import (
+	"fmt"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestStress(t *testing.T) {
+	var runs int
+	seeds := make(map[int64]bool)
+	Stress(t, 9, func(b *SyncBus) {
+		runs++
+		seeds[b.Seed()] = true
+
+		tw := newTestWait(3)
+		for i := 0; i < 3; i++ {
+			go func() {
+				if err := b.Wait("foo"); err != nil {
+					t.Error(err)
+				}
+
+				tw.done()
+			}()
+		}
+
+		b.Signal("foo")
+		if err := tw.wait(); err != nil {
+			t.Error(err)
+		}
+	})
+
+	if runs != 9 || len(seeds) != 9 {
+		t.Error("invalid runs", runs, len(seeds))
+	}
+}
+
+type fakeTB struct {
+	testing.TB
+	failed  bool
+	fatal   bool
+	errors  []string
+	logs    []string
+	cleanup []func()
+}
+
+func (t *fakeTB) Helper()      {}
+func (t *fakeTB) Failed() bool { return t.failed }
+func (t *fakeTB) Error(args ...interface{}) {
+	t.failed = true
+	t.errors = append(t.errors, fmt.Sprint(args...))
+}
+func (t *fakeTB) Errorf(f string, a ...interface{}) { t.Error(fmt.Sprintf(f, a...)) }
+func (t *fakeTB) Logf(f string, a ...interface{})   { t.logs = append(t.logs, fmt.Sprintf(f, a...)) }
+func (t *fakeTB) Fatalf(f string, a ...interface{}) { t.Errorf(f, a...); t.fatal = true }
+func (t *fakeTB) Cleanup(f func())                  { t.cleanup = append(t.cleanup, f) }
+
+func (t *fakeTB) runCleanup() {
+	for i := len(t.cleanup) - 1; i >= 0; i-- {
+		t.cleanup[i]()
+	}
+}
+
+func TestStressFailures(t *testing.T) {
+	ft := &fakeTB{}
+	var (
+		i     int
+		buses []*SyncBus
+	)
+
+	Stress(ft, 5, func(b *SyncBus) {
+		defer func() { i++ }()
+		buses = append(buses, b)
+		switch i {
+		case 1:
+			b.Wait("foo")
+		case 2:
+			panic("test")
+		case 3:
+			ft.failed = true
+		case 4:
+			runtime.Goexit()
+		}
+	}, WithTimeout(time.Millisecond), WithScheduleDir(t.TempDir()))
+
+	for _, b := range buses {
+		select {
+		case <-b.Closed():
+		case <-time.After(time.Second):
+			t.Error("failed to close the bus")
+		}
+	}
+
+	if len(ft.errors) != 1 {
+		t.Fatal("failed to report failures")
+	}
+
+	msg := ft.errors[0]
+	if !strings.Contains(msg, "4 of 5 stress runs failed") ||
+		!strings.Contains(msg, "1 wait(s) timed out") ||
+		!strings.Contains(msg, "panic: test") ||
+		!strings.Contains(msg, "test failed") ||
+		!strings.Contains(msg, "stopped by runtime.Goexit") ||
+		strings.Count(msg, ", schedule: ") != 4 {
+		t.Error("invalid report", msg)
+	}
+}
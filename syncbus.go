@@ -10,141 +10,700 @@ calling goroutine continues immediately. Once a signal is set, it stays so until
 
 Wait can expect one or more signals represented by keys. The signals don't need to be set simultaneously in
 order to release a waiting goroutine. A wait continues once all the signals that it depends on were set.
+
+In terms of the Go memory model, a successful Wait call happens after the Signal calls that set the awaited
+signals, as long as they were not reset in the meantime. This means that it is safe, and clean for the race
+detector, to publish values through the bus: a value written before signaling a key can be read without
+additional synchronization after a successful Wait on the same key. SynchronizeWith provides the same guarantee
+without blocking.
 */
 package syncbus
 
 import (
+	"context"
 	"errors"
+	"os"
+	"sync"
+	"sync/atomic"
 	"time"
 )
 
 type waitItem struct {
-	keys     []string
-	deadline time.Time
-	signal   chan error
+	keys         []string
+	ids          []uint32
+	namespace    string
+	name         string
+	labels       map[string]string
+	timeout      time.Duration
+	deadline     time.Time
+	nominal      time.Time
+	start        time.Time
+	budgeted     bool
+	ctx          context.Context
+	seq          uint64
+	priority     int64
+	noBlockIfSet bool
+	count        *countWait
+	order        *orderWait
+	predicate    func(State) bool
+	cond         condition
+	signal       chan error
+	ack          chan struct{}
+	released     chan struct{}
+	done         chan error
+	call         callInfo
+}
+
+type signalItem struct {
+	keys       []string
+	namespace  string
+	background bool
+	value      *value
+	meta       map[string]string
+	sync       chan []chan struct{}
+	call       callInfo
+}
+
+type resetItem struct {
+	keys      []string
+	namespace string
+	all       bool
+	call      callInfo
 }
 
 // SyncBus can be used to synchronize goroutines through signals.
 type SyncBus struct {
-	timeout  time.Duration
-	waiting  []waitItem
-	signals  map[string]bool
-	wait     chan waitItem
-	signal   chan []string
-	reset    chan []string
-	resetAll chan struct{}
-	quit     chan struct{}
+	// accessed atomically, kept first for alignment
+	dropped    uint64
+	loopID     int64
+	callbackID int64
+	fastHit    uint64
+	stalled    uint64
+	scopes     uint64
+	ownership  int32
+
+	timeout    time.Duration
+	waiting    []waitItem
+	spare      []waitItem
+	signals    *signalSet
+	wait       chan waitItem
+	waitBatch  chan []waitItem
+	cancel     chan waitItem
+	modify     chan modifyItem
+	signalOne  chan signalOneItem
+	signal     chan signalItem
+	reset      chan resetItem
+	resetAll   chan callInfo
+	snapshot   chan chan snapshot
+	check      chan checkItem
+	diff       chan diffItem
+	get        chan getItem
+	clearLast  chan []string
+	watch      chan watchItem
+	graph      chan chan *WaitGraph
+	declare    chan keyDecl
+	historyReq chan queryItem
+	onSignal   chan callbackItem
+	relay      chan relayItem
+	dispatch   chan func()
+	failed     chan *PanicError
+	quit       chan struct{}
+	done       chan struct{}
+	exited     chan struct{}
+	closing    sync.Once
+	panicked   *PanicError
+	counters   counters
+
+	options    options
+	events     chan Event
+	owners     map[string]string
+	resets     map[string]resetInfo
+	declared   map[string]keyDecl
+	cycles     map[string][]string
+	history    history
+	recent     history
+	throttled  map[string][]time.Time
+	violations []Violation
+	handling   bool
+	rand       *random
+	pct        *pct
+	watchdog   *watchdog
+	sim        *simulation
+	tracing    *loopTracing
+	audit      *auditLog
+	earliest   time.Time
+	arrivals   map[string]time.Time
+	tombstones []tombstone
+	sources    map[string]*KeySource
+	scale      float64
+	lastSignal map[int64][]string
+	callbacks  map[string][]func(string)
+	values     map[string]interface{}
+	last       map[string]interface{}
+	blocked    blockedTime
+	watchers   map[string][]chan Change
+	labeled    map[label]*counters
+	queue      []func()
+	relays     []relayItem
+	fastPath   bool
+	fastView   atomic.Value
+	view       *readView
+	keyTable   keyTable
+}
+
+// Bus is the common interface implemented by SyncBus and the decorators wrapping it.
+type Bus interface {
+	Wait(keys ...string) error
+	Signal(keys ...string)
+	ResetSignals(keys ...string)
+	Reset()
+	Close()
 }
 
 // ErrTimeout is returned by Wait() when failed to receive all the signals in time.
 var ErrTimeout = errors.New("timeout")
 
-// New creates and initializes a new SyncBus. It uses a shared timeout for all the Wait calls.
-func New(timeout time.Duration) *SyncBus {
+// ErrClosed is returned by Wait() when the bus was closed before or during the call.
+var ErrClosed = errors.New("bus closed")
+
+// New creates and initializes a new SyncBus. It uses a shared timeout for all the Wait calls. The optional
+// arguments can be used to customize the behavior of the bus.
+func New(timeout time.Duration, opts ...Option) *SyncBus {
 	b := &SyncBus{
-		timeout:  timeout,
-		signals:  make(map[string]bool),
-		wait:     make(chan waitItem),
-		signal:   make(chan []string),
-		reset:    make(chan []string),
-		resetAll: make(chan struct{}),
-		quit:     make(chan struct{}),
+		timeout:    timeout,
+		wait:       make(chan waitItem),
+		waitBatch:  make(chan []waitItem),
+		cancel:     make(chan waitItem),
+		arrivals:   make(map[string]time.Time),
+		sources:    make(map[string]*KeySource),
+		modify:     make(chan modifyItem),
+		signalOne:  make(chan signalOneItem),
+		owners:     make(map[string]string),
+		resets:     make(map[string]resetInfo),
+		declared:   make(map[string]keyDecl),
+		throttled:  make(map[string][]time.Time),
+		lastSignal: make(map[int64][]string),
+		snapshot:   make(chan chan snapshot),
+		check:      make(chan checkItem),
+		diff:       make(chan diffItem),
+		get:        make(chan getItem),
+		clearLast:  make(chan []string),
+		watch:      make(chan watchItem),
+		watchers:   make(map[string][]chan Change),
+		labeled:    make(map[label]*counters),
+		values:     make(map[string]interface{}),
+		last:       make(map[string]interface{}),
+		blocked:    blockedTime{waiters: make(map[string]*WaiterReport)},
+		graph:      make(chan chan *WaitGraph),
+		declare:    make(chan keyDecl),
+		historyReq: make(chan queryItem),
+		onSignal:   make(chan callbackItem),
+		relay:      make(chan relayItem),
+		dispatch:   make(chan func()),
+		failed:     make(chan *PanicError),
+		callbacks:  make(map[string][]func(string)),
+		quit:       make(chan struct{}),
+		done:       make(chan struct{}),
+		exited:     make(chan struct{}),
+	}
+
+	b.options = options{
+		timeout:          timeout,
+		eventBuffer:      DefaultEventBuffer,
+		filePollInterval: DefaultFilePollInterval,
+		seed:             time.Now().UnixNano(),
+		sampling:         1,
+		scheduleDir:      os.TempDir(),
+	}
+
+	for _, opt := range opts {
+		opt(&b.options)
+	}
+
+	b.scale = timeoutScale(b.options)
+	b.timeout = b.scaleTimeout(b.options.timeout)
+	b.signals = newSignalSet(b.options.expectedKeys)
+	b.signal = make(chan signalItem, b.options.signalBuffer)
+	b.reset = make(chan resetItem, b.options.signalBuffer)
+	b.resetAll = make(chan callInfo, b.options.signalBuffer)
+	if b.options.expectedWaiters > 0 {
+		b.waiting = make([]waitItem, 0, b.options.expectedWaiters)
+		b.spare = make([]waitItem, 0, b.options.expectedWaiters)
+	}
+
+	b.events = make(chan Event, b.options.eventBuffer)
+	if b.options.wakeupOrder.kind == seededWakeup {
+		b.options.wakeupOrder.rand = newRandom(b.options.wakeupOrder.seed, false, nil)
+	}
+
+	b.rand = newRandom(b.options.seed, b.options.recordSchedule != "" || b.options.keepSchedule, b.options.replay)
+	b.rand.generated = b.options.generated
+	b.rand.choices = b.options.choices
+	b.pct = newPCT(b.options.pctDepth, b.options.pctSteps, b.rand)
+	b.watchdog = newWatchdog(b.options.watchdog, b.options.watchdogHandler)
+	b.sim = newSimulation(b.options.simulated, b.options.settle)
+	b.tracing = newLoopTracing(b.options)
+	b.audit = newAuditLog(b.options)
+	b.fastPath = b.options.fastPath && !b.randomized() && !b.options.vet
+	if b.options.readMostly {
+		b.view = &readView{signals: make(map[string]bool)}
 	}
 
 	go b.run()
+	go b.dispatchCallbacks()
 	return b
 }
 
 func (b *SyncBus) nextTimeout(now time.Time) <-chan time.Time {
-	if len(b.waiting) == 0 {
+	b.earliest = time.Time{}
+	if len(b.waiting) == 0 || b.sim != nil {
 		return nil
 	}
 
-	to := b.waiting[0].deadline.Sub(time.Now())
-	return time.After(to)
+	next := b.waiting[0].deadline
+	for _, w := range b.waiting[1:] {
+		if w.deadline.Before(next) {
+			next = w.deadline
+		}
+	}
+
+	b.earliest = next
+
+	return time.After(next.Sub(time.Now()))
+}
+
+// release delivers the result of a wait.
+func (w waitItem) release(err error) {
+	w.signal <- err
+	w.notify(err)
+}
+
+// notify notifies the handle of a wait, if it has one, about the result. Since the result of a handle is
+// delivered at this point, it also acknowledges the release for SignalSync, regardless of whether the handle
+// is consumed by Wait or by Done.
+func (w waitItem) notify(err error) {
+	if w.released == nil {
+		return
+	}
+
+	w.done <- err
+	close(w.done)
+	close(w.released)
+	if w.ack != nil {
+		close(w.ack)
+	}
 }
 
 func (b *SyncBus) addWaiting(now time.Time, w waitItem) {
-	w.deadline = now.Add(b.timeout)
+	if b.tracing != nil {
+		defer b.traceOp(OpWait, time.Now())
+	}
+
+	w.ids = b.signals.internKeys(w.keys)
+	b.initOrder(w)
+	if b.releaseIfSet(now, w) {
+		return
+	}
+
+	if err := b.checkBusy(w); err != nil {
+		w.release(err)
+		return
+	}
+
+	w.start = now
+	w.deadline = b.waitDeadline(now, w)
+	b.applyGrace(&w)
+	b.applyTolerance(&w)
+	if err := b.applyBudget(now, &w); err != nil {
+		w.release(err)
+		return
+	}
+
+	w.seq = b.counters.waits
+	w.priority = b.pct.priority()
 	b.waiting = append(b.waiting, w)
+	b.checkMaxWaiters(now, w)
+	b.counters.waits++
+	b.countLabels(w, func(c *counters) { c.waits++ })
+	b.emit(Event{Type: EventWait, Keys: w.keys, Labels: w.labels, Time: now})
 }
 
-func (b *SyncBus) setSignal(keys []string) {
+func (b *SyncBus) setSignal(now time.Time, s signalItem) {
+	if b.tracing != nil {
+		defer b.traceOp(OpSignal, time.Now())
+	}
+
+	keys := s.keys
+	if len(b.options.throttles) > 0 {
+		keys = nil
+		for _, key := range s.keys {
+			if !b.throttle(now, key) {
+				keys = append(keys, key)
+			}
+		}
+
+		if len(keys) == 0 {
+			return
+		}
+	}
+
 	for _, key := range keys {
-		b.signals[key] = true
+		old := b.keyState(key)
+		b.countSignal(now, key)
+		b.extendWaiting(key)
+		b.signals.set(key)
+		b.recordArrival(now, key)
+		b.checkTombstones(now, key)
+		b.attributeSet(now, key, s.call)
+		if s.value != nil {
+			b.values[key] = s.value.value
+			b.last[key] = s.value.value
+		}
+		if s.namespace == "" {
+			delete(b.owners, key)
+		} else {
+			b.owners[key] = s.namespace
+		}
+
+		b.notifyWatchers(now, key, old, s.meta)
 	}
+
+	b.publishSignals()
+	b.viewSignals(keys, true)
+	b.orderSignals(keys)
+	b.counters.signals++
+	b.emit(Event{Type: EventSignal, Keys: keys, Time: now, Meta: s.meta})
+	b.queueCallbacks(keys)
 }
 
 func (b *SyncBus) timeoutWaiting(now time.Time) {
-	for i, w := range b.waiting {
+	if b.tracing != nil {
+		defer b.traceOp(OpTimeout, time.Now())
+	}
+
+	var timedOut []waitItem
+	keep := b.keepWaiting()
+	for _, w := range b.waiting {
 		if w.deadline.After(now) {
-			b.waiting = b.waiting[i:]
-			return
+			keep = append(keep, w)
+			continue
 		}
 
-		w.signal <- ErrTimeout
+		timedOut = append(timedOut, w)
 	}
 
-	b.waiting = nil
+	b.swapWaiting(keep)
+
+	var g *WaitGraph
+	if b.options.waitGraph && len(timedOut) > 0 {
+		g = b.createWaitGraph(now, timedOut)
+		b.writeWaitGraph(g)
+	}
+
+	for _, w := range timedOut {
+		var err error = ErrTimeout
+		switch {
+		case w.budgeted:
+			err = ErrBudget
+		case g != nil || b.options.partialResults || b.options.errorContext > 0 || b.options.attribution:
+			err = b.timeoutError(now, w, g)
+		}
+
+		b.accountBlocked(now, w)
+		b.addTombstone(now, w)
+		w.release(err)
+		b.counters.timeouts++
+		b.countLabels(w, func(c *counters) { c.timeouts++ })
+		b.emit(Event{Type: EventTimeout, Keys: w.keys, Labels: w.labels, Time: now})
+	}
 }
 
-func (b *SyncBus) signalWaiting(now time.Time) {
-	var keep []waitItem
-	for _, w := range b.waiting {
-		var keepItem bool
-		for _, key := range w.keys {
-			if !b.signals[key] {
-				keepItem = true
-				break
-			}
+// checkWaiting tells whether a waiting item can be released. When it returns an error, the item needs to be
+// released with the error.
+func (b *SyncBus) checkWaiting(w waitItem) (bool, error) {
+	if w.cond != nil {
+		return w.cond.eval(b.signals.has), nil
+	}
+
+	if err := b.checkLeak(w); err != nil {
+		return true, err
+	}
+
+	if err := b.checkCycle(w); err != nil {
+		return true, err
+	}
+
+	if w.count != nil {
+		return w.count.done, nil
+	}
+
+	if w.predicate != nil {
+		return w.predicate(State{bus: b}), nil
+	}
+
+	if w.order != nil {
+		return w.order.err != nil || w.order.next == len(w.keys), w.order.err
+	}
+
+	for _, id := range w.ids {
+		if !b.signals.hasID(id) {
+			return false, nil
 		}
+	}
+
+	return true, nil
+}
+
+type released struct {
+	item waitItem
+	err  error
+}
+
+// signalWaiting releases the waits whose conditions are met, and returns them.
+func (b *SyncBus) signalWaiting(now time.Time) []released {
+	if b.tracing != nil {
+		defer b.traceOp(OpScan, time.Now())
+	}
 
-		if keepItem {
+	b.expireWaiting(now)
+
+	var release []released
+	keep := b.keepWaiting()
+	b.cycles = b.findCycles()
+	for _, w := range b.waiting {
+		done, err := b.checkWaiting(w)
+		if !done {
 			keep = append(keep, w)
 			continue
 		}
 
-		w.signal <- nil
+		release = append(release, released{item: w, err: err})
+	}
+
+	b.swapWaiting(keep)
+	release = b.orderRelease(release)
+	for _, r := range release {
+		b.releaseWaiting(now, r)
 	}
 
-	b.waiting = keep
+	return release
+}
+
+// releaseWaiting releases a wait that was removed from the pending waits.
+func (b *SyncBus) releaseWaiting(now time.Time, r released) {
+	b.accountBlocked(now, r.item)
+	r.item.release(r.err)
+	b.counters.releases++
+	b.countLabels(r.item, func(c *counters) { c.releases++ })
+	if r.err == nil {
+		b.emit(Event{Type: EventRelease, Keys: r.item.keys, Labels: r.item.labels, Time: now})
+		b.checkLate(now, r.item)
+	}
 }
 
-func (b *SyncBus) resetSignals(keys []string) {
+func (b *SyncBus) resetSignals(now time.Time, r resetItem) {
+	if b.tracing != nil {
+		defer b.traceOp(OpReset, time.Now())
+	}
+
+	keys := r.keys
+	if r.all {
+		keys = nil
+		for key, ns := range b.owners {
+			if ns == r.namespace {
+				keys = append(keys, key)
+			}
+		}
+	} else {
+		keys = b.checkOwnership(now, "ResetSignals", keys, r.namespace, r.call)
+	}
+
+	op := AuditResetSignals
+	if r.all {
+		op = AuditReset
+	}
+
+	b.audit.record(now, op, keys, r.namespace, r.call)
+	b.vetReset(now, keys, r.call)
+	b.attributeReset(now, keys, r.call)
 	for i := range keys {
-		delete(b.signals, keys[i])
+		old := b.keyState(keys[i])
+		b.signals.clear(keys[i])
+		delete(b.values, keys[i])
+		delete(b.owners, keys[i])
+		if old.Set {
+			b.notifyWatchers(now, keys[i], old, nil)
+		}
 	}
+
+	b.publishSignals()
+	b.viewSignals(keys, false)
+	b.recordReset(now, r.namespace, r.all, keys)
+	b.emit(Event{Type: EventReset, Keys: keys, Time: now})
 }
 
-func (b *SyncBus) resetAllSignals() {
-	b.signals = make(map[string]bool)
+func (b *SyncBus) resetAllSignals(now time.Time, c callInfo) {
+	if b.tracing != nil {
+		defer b.traceOp(OpReset, time.Now())
+	}
+
+	if b.options.waitGraph || b.options.vet || b.options.attribution || b.options.auditLog {
+		keys := b.signals.list()
+
+		b.recordReset(now, "", true, keys)
+		b.audit.record(now, AuditReset, keys, "", c)
+		b.vetReset(now, keys, c)
+		b.attributeReset(now, keys, c)
+	}
+
+	watched := b.watchedSet()
+	b.signals.clearAll()
+	b.values = make(map[string]interface{})
+	b.owners = make(map[string]string)
+	for key, old := range watched {
+		b.notifyWatchers(now, key, old, nil)
+	}
+
+	b.publishSignals()
+	b.viewResetAll()
+	b.emit(Event{Type: EventResetAll, Time: now})
+}
+
+func (b *SyncBus) processSignal(now time.Time, signal signalItem) {
+	b.pctStep()
+	signal.keys = b.checkOwnership(now, "Signal", signal.keys, signal.namespace, signal.call)
+	if len(signal.keys) == 0 {
+		b.syncSignal(signal, nil)
+		return
+	}
+
+	b.audit.record(now, AuditSignal, signal.keys, signal.namespace, signal.call)
+	b.setSignal(now, signal)
+	b.fireRelays(now)
+	b.vetSignal(signal)
+	b.syncSignal(signal, b.signalWaiting(now))
 }
 
-func (b *SyncBus) run() {
+func (b *SyncBus) loop() {
 	var to <-chan time.Time
 	for {
+		b.viewStats()
+		dispatch, callback := b.nextCallback()
+		settle := b.settleTimer()
 		select {
 		case <-to:
-			now := time.Now()
+			now := b.now()
+			b.drainSignals(now)
 			b.timeoutWaiting(now)
 			to = b.nextTimeout(now)
+		case <-b.pctStepTimer():
+			now := b.now()
+			b.pctStep()
+			b.signalWaiting(now)
+			to = b.nextTimeout(now)
 		case wait := <-b.wait:
-			now := time.Now()
+			now := b.now()
+			b.pctStep()
+			b.vetWait(now, wait)
 			b.addWaiting(now, wait)
 			b.signalWaiting(now)
 			to = b.nextTimeout(now)
-		case signal := <-b.signal:
-			now := time.Now()
-			b.setSignal(signal)
+		case batch := <-b.waitBatch:
+			now := b.now()
+			b.pctStep()
+			for _, wait := range batch {
+				b.vetWait(now, wait)
+				b.addWaiting(now, wait)
+			}
+
 			b.signalWaiting(now)
 			to = b.nextTimeout(now)
+		case m := <-b.modify:
+			now := b.now()
+			found := b.modifyWaiting(m)
+			m.result <- found
+			if found {
+				b.signalWaiting(now)
+				to = b.nextTimeout(now)
+			}
+		case o := <-b.signalOne:
+			now := b.now()
+			o.result <- b.signalOneWaiting(now, o.key)
+			to = b.nextTimeout(now)
+		case wait := <-b.cancel:
+			now := b.now()
+			b.cancelWaiting(now, wait)
+			to = b.nextTimeout(now)
+		case signal := <-b.signal:
+			now := b.now()
+			b.processSignal(now, signal)
+			to = b.nextTimeout(now)
 		case reset := <-b.reset:
-			b.resetSignals(reset)
-		case <-b.resetAll:
-			b.resetAllSignals()
+			now := b.now()
+			b.pctStep()
+			b.resetSignals(now, reset)
+			if len(b.declared) > 0 || b.hasPredicates() {
+				b.signalWaiting(now)
+				to = b.nextTimeout(now)
+			}
+		case c := <-b.resetAll:
+			now := b.now()
+			b.pctStep()
+			b.resetAllSignals(now, c)
+			if len(b.declared) > 0 || b.hasPredicates() {
+				b.signalWaiting(now)
+				to = b.nextTimeout(now)
+			}
+		case d := <-b.declare:
+			now := b.now()
+			b.declared[d.key] = d
+			b.signalWaiting(now)
+			to = b.nextTimeout(now)
+		case s := <-b.snapshot:
+			s <- b.createSnapshot(b.now())
+		case g := <-b.graph:
+			g <- b.createWaitGraph(b.now(), nil)
+		case q := <-b.historyReq:
+			q.result <- b.history.query(q.filter)
+		case c := <-b.check:
+			c.result <- b.signals.has(c.key)
+		case d := <-b.diff:
+			d.result <- diffStates(d.expected, State{bus: b})
+		case g := <-b.get:
+			g.result <- b.lookupValue(g)
+		case keys := <-b.clearLast:
+			b.clearLastValues(keys)
+		case w := <-b.watch:
+			w.result <- b.addWatcher(w.key)
+		case r := <-b.relay:
+			now := b.now()
+			b.relays = append(b.relays, r)
+			b.fireRelays(now)
+			b.signalWaiting(now)
+			to = b.nextTimeout(now)
+		case cb := <-b.onSignal:
+			b.callbacks[cb.key] = append(b.callbacks[cb.key], cb.f)
+		case dispatch <- callback:
+			b.queue = b.queue[1:]
+		case p := <-b.failed:
+			b.stopWatchdog()
+			b.panicked = p
+			b.releaseAll(p)
+			return
+		case <-b.clockChanged():
+		case <-settle:
+			now := b.advanceClock()
+			b.timeoutWaiting(now)
+		case <-b.watchdogTimer():
+			b.checkWatchdog(time.Now())
+			continue
 		case <-b.quit:
+			b.stopWatchdog()
+			b.releaseAll(ErrClosed)
 			return
 		}
+
+		b.feedWatchdog()
 	}
 }
 
@@ -152,7 +711,11 @@ func (b *SyncBus) run() {
 // returns an ErrTimeout if the timeout, counted from the call to Wait,
 // expires.
 //
-// It returns only ErrTimeout or nil.
+// It returns only ErrTimeout, ErrClosed or nil, unless the bus was
+// created with an option reporting the failures in more detail,
+// like WithWaitGraph, WithLeakageGuard, WithMaxWaiting,
+// WithCloseGuard, WithPartialResults, WithErrorContext or
+// WithSourceAttribution, or keys were declared with Declare.
 //
 // If the receiver *SyncBus is nil, or no key argument is passed to it,
 // it is a noop.
@@ -161,16 +724,154 @@ func (b *SyncBus) Wait(keys ...string) error {
 		return nil
 	}
 
-	w := waitItem{
-		keys:   keys,
-		signal: make(chan error, 1),
+	if b.waitFast(keys) {
+		return nil
+	}
+
+	return b.waitItem(waitItem{keys: keys})
+}
+
+// registerWait sends a wait to the run loop. It tells whether the wait was registered. When it was not, it may
+// return the error of the wait.
+func (b *SyncBus) registerWait(w *waitItem) (bool, error) {
+	if !b.sample() {
+		return false, nil
+	}
+
+	if !w.noBlockIfSet {
+		b.jitter()
+	}
+
+	w.signal = make(chan error, 1)
+	w.ack = make(chan struct{})
+	w.call = b.callInfo()
+	if b.onLoop("Wait", w.keys, w.call) {
+		return false, ErrLoopCall
+	}
+
+	if b.closed() {
+		return false, b.useAfterClose("Wait", w.keys)
+	}
+
+	select {
+	case b.wait <- *w:
+		return true, nil
+	case <-b.done:
+		return false, b.useAfterClose("Wait", w.keys)
+	}
+}
+
+func (b *SyncBus) waitItem(w waitItem) error {
+	if ok, err := b.registerWait(&w); !ok {
+		return err
+	}
+
+	err := b.receive(w)
+	close(w.ack)
+	if !w.noBlockIfSet {
+		b.jitter()
 	}
 
-	b.wait <- w
-	err := <-w.signal
 	return err
 }
 
+// closed tells whether the bus was closed, or its run loop stopped. Checking it before the selects that also
+// watch the done channel makes the calls after Close deterministic.
+func (b *SyncBus) closed() bool {
+	select {
+	case <-b.quit:
+		return true
+	case <-b.done:
+		return true
+	default:
+		return false
+	}
+}
+
+// closedErr returns the error of the waits made after the bus was closed. It must be called only after closed()
+// returned true, or after the done channel was closed.
+func (b *SyncBus) closedErr() error {
+	select {
+	case <-b.done:
+		if b.panicked != nil {
+			return b.panicked
+		}
+	default:
+	}
+
+	return ErrClosed
+}
+
+// sendSignal sets the signals, unless the bus is closed. It tells whether the signals were accepted by the run
+// loop.
+func (b *SyncBus) sendSignal(s signalItem) bool {
+	s.call = b.callInfo()
+	if b.onLoop("Signal", s.keys, s.call) {
+		return false
+	}
+
+	if b.closed() {
+		b.signalAfterClose(s)
+		return false
+	}
+
+	select {
+	case b.signal <- s:
+		return true
+	default:
+		b.countStall()
+	}
+
+	select {
+	case b.signal <- s:
+		return true
+	case <-b.done:
+		b.signalAfterClose(s)
+		return false
+	}
+}
+
+func (b *SyncBus) signalAfterClose(s signalItem) {
+	if !s.background {
+		b.useAfterClose("Signal", s.keys)
+	}
+}
+
+// sendReset clears the signals, unless the bus is closed.
+func (b *SyncBus) sendReset(r resetItem) {
+	r.call = b.callInfo()
+	if b.onLoop("Reset", r.keys, r.call) {
+		return
+	}
+
+	if b.closed() {
+		b.resetAfterClose(r)
+		return
+	}
+
+	select {
+	case b.reset <- r:
+		return
+	default:
+		b.countStall()
+	}
+
+	select {
+	case b.reset <- r:
+	case <-b.done:
+		b.resetAfterClose(r)
+	}
+}
+
+func (b *SyncBus) resetAfterClose(r resetItem) {
+	if r.all {
+		b.useAfterClose("Reset", nil)
+		return
+	}
+
+	b.useAfterClose("ResetSignals", r.keys)
+}
+
 // Signal sets one or more signals represented by the keys.
 //
 // If the receiver *SyncBus is nil, or no key argument is passed to it,
@@ -180,7 +881,7 @@ func (b *SyncBus) Signal(keys ...string) {
 		return
 	}
 
-	b.signal <- keys
+	b.sendSignal(signalItem{keys: keys})
 }
 
 // ResetSignals clears the set signals defined by the provided keys.
@@ -192,7 +893,7 @@ func (b *SyncBus) ResetSignals(keys ...string) {
 		return
 	}
 
-	b.reset <- keys
+	b.sendReset(resetItem{keys: keys})
 }
 
 // Reset clears all the signals.
@@ -203,14 +904,69 @@ func (b *SyncBus) Reset() {
 		return
 	}
 
-	b.resetAll <- struct{}{}
+	c := b.callInfo()
+	if b.onLoop("Reset", nil, c) {
+		return
+	}
+
+	if b.closed() {
+		b.useAfterClose("Reset", nil)
+		return
+	}
+
+	select {
+	case b.resetAll <- c:
+		return
+	default:
+		b.countStall()
+	}
+
+	select {
+	case b.resetAll <- c:
+	case <-b.done:
+		b.useAfterClose("Reset", nil)
+	}
 }
 
-// Close tears down the SyncBus. If the receiver is nil, it is a noop.
+// shutdown initiates the termination of the run loop, without waiting for it.
+func (b *SyncBus) shutdown() {
+	b.closing.Do(func() {
+		close(b.quit)
+		if b.options.recordSchedule != "" {
+			writeSchedule(b.options.recordSchedule, b.rand.decisions())
+		}
+	})
+}
+
+// Close tears down the SyncBus, and waits until its run loop stops. The pending waits return ErrClosed. After
+// closing, Wait returns ErrClosed immediately, the operations changing the signals are noops, and the queries
+// return empty results. Calling Close multiple times is safe. When the run loop stopped due to a panic, and the
+// bus was created with WithRepanic, Close panics with the *PanicError. Close doesn't wait for the goroutine
+// executing the OnSignal callbacks, to allow calling it from a callback. To wait for it, too, use Closed. If the
+// receiver is nil, it is a noop.
 func (b *SyncBus) Close() {
 	if b == nil {
 		return
 	}
 
-	close(b.quit)
+	b.shutdown()
+	<-b.done
+	if b.options.repanic && b.panicked != nil {
+		panic(b.panicked)
+	}
+}
+
+// Closed returns a channel that is closed once the bus was closed, and all its internal goroutines have
+// returned: the run loop, and the goroutine executing the OnSignal callbacks. It allows goroutine leak detectors
+// and ordered teardown logic to rely on a completion point, instead of racing the exit of the goroutines.
+//
+// If the receiver *SyncBus is nil, it returns a closed channel.
+func (b *SyncBus) Closed() <-chan struct{} {
+	if b == nil {
+		c := make(chan struct{})
+		close(c)
+		return c
+	}
+
+	return b.exited
 }
@@ -28,15 +28,16 @@ func newTestWait(n int) *testWait {
 }
 
 func (tw *testWait) wait() error {
+	n := tw.n
 	for {
-		if tw.n <= 0 {
+		if n <= 0 {
 			close(tw.doneAll)
 			return nil
 		}
 
 		select {
 		case <-tw.c:
-			tw.n--
+			n--
 		case <-time.After(testWaitTimeout):
 			return ErrTimeout
 		}
@@ -109,6 +110,45 @@ func TestNilClose(t *testing.T) {
 	bus.Close()
 }
 
+func TestNilClosed(t *testing.T) {
+	var bus *SyncBus
+	<-bus.Closed()
+}
+
+func TestClosed(t *testing.T) {
+	bus := New(120 * time.Millisecond)
+	select {
+	case <-bus.Closed():
+		t.Fatal("unexpected close")
+	default:
+	}
+
+	started, callback := make(chan struct{}), make(chan struct{})
+	bus.OnSignal("foo", func(string) {
+		close(started)
+		<-callback
+	})
+
+	bus.Signal("foo")
+	<-started
+	bus.Close()
+	select {
+	case <-bus.Closed():
+		t.Fatal("closed before the callback returned")
+	case <-time.After(3 * time.Millisecond):
+	}
+
+	close(callback)
+	<-bus.Closed()
+}
+
+func TestCloseFromCallback(t *testing.T) {
+	bus := New(120 * time.Millisecond)
+	bus.OnSignal("foo", func(string) { bus.Close() })
+	bus.Signal("foo")
+	<-bus.Closed()
+}
+
 func TestEmptyWait(t *testing.T) {
 	bus := New(120 * time.Millisecond)
 	if err := bus.Wait(); err != nil {
@@ -289,3 +329,67 @@ func TestResetAll(t *testing.T) {
 		t.Error("failed to timeout")
 	}
 }
+
+func TestCloseReleasesPending(t *testing.T) {
+	bus := New(120 * time.Millisecond)
+
+	tw := newTestWait(1)
+	go func() {
+		if err := bus.Wait("foo"); err != ErrClosed {
+			t.Error("failed to return closed", err)
+		}
+
+		tw.done()
+	}()
+
+	for bus.Stats().Waiting != 1 {
+		time.Sleep(time.Millisecond / 10)
+	}
+
+	bus.Close()
+	if err := tw.wait(); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestAfterClose(t *testing.T) {
+	bus := New(120 * time.Millisecond)
+	bus.Signal("foo")
+	bus.Close()
+	bus.Close()
+
+	tw := newTestWait(1)
+	go func() {
+		if err := bus.Wait("foo"); err != ErrClosed {
+			t.Error("failed to return closed", err)
+		}
+
+		bus.Signal("foo")
+		bus.ResetSignals("foo")
+		bus.Reset()
+		bus.Namespace("test").Signal("foo")
+		bus.Namespace("test").Reset()
+		bus.Declare("foo")
+		if bus.SynchronizeWith("foo") {
+			t.Error("unexpected signal")
+		}
+
+		if s := bus.Stats(); s != (Stats{}) {
+			t.Error("unexpected stats", s)
+		}
+
+		if v := bus.Violations(); v != nil {
+			t.Error("unexpected violations", v)
+		}
+
+		if g := bus.WaitGraph(); len(g.Signals) != 0 {
+			t.Error("unexpected graph", g)
+		}
+
+		tw.done()
+	}()
+
+	if err := tw.wait(); err != nil {
+		t.Error(err)
+	}
+}
@@ -0,0 +1,163 @@
+package syncbus
+
+import (
+	"os"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+// SeedEnv is the name of the environment variable that, when set, overrides the seed of the buses created by
+// NewForTest, and the base seed of Stress, so that a failed randomized run can be reproduced.
+const SeedEnv = "SYNCBUS_SEED"
+
+func envSeed(t testing.TB) (int64, bool) {
+	t.Helper()
+	s := os.Getenv(SeedEnv)
+	if s == "" {
+		return 0, false
+	}
+
+	seed, err := strconv.ParseInt(s, 10, 64)
+	if err != nil {
+		t.Fatalf("invalid %s: %v", SeedEnv, err)
+	}
+
+	return seed, true
+}
+
+func (b *SyncBus) randomized() bool {
+	o := b.options
+	return o.jitter > 0 || o.sampling < 1 || o.wakeupOrder.kind == shuffledWakeup || o.pctDepth > 0 || len(o.replay) > 0 ||
+		o.generated
+}
+
+// NewForTest creates a bus for a test, that gets closed automatically during the cleanup of the test. When
+// the environment variable SYNCBUS_SEED is set, it is used as the seed of the bus. When the bus operates in a
+// randomized mode, e.g. with WithJitter or WithPCT, and the test fails, the seed is logged, so that the run can
+// be reproduced. When the test binary was built with the race detector, the timeouts of the bus are multiplied by
+// DefaultRaceScale, unless set otherwise with WithRaceScale.
+func NewForTest(t testing.TB, timeout time.Duration, opts ...Option) *SyncBus {
+	t.Helper()
+	if seed, ok := envSeed(t); ok {
+		opts = append(opts, WithSeed(seed))
+	}
+
+	opts = append([]Option{WithRaceScale(DefaultRaceScale)}, opts...)
+
+	b := New(timeout, opts...)
+	t.Cleanup(func() {
+		if t.Failed() && b.randomized() {
+			t.Logf("syncbus seed: %d, reproduce with %s=%d", b.Seed(), SeedEnv, b.Seed())
+		}
+
+		b.Close()
+	})
+
+	return b
+}
+
+// deadlineTimeout returns the timeout derived from the remaining time until the deadline, minus headroom. When
+// the headroom doesn't fit, it returns half of the remaining time.
+func deadlineTimeout(now, deadline time.Time, headroom time.Duration) time.Duration {
+	remaining := deadline.Sub(now)
+	if remaining-headroom > 0 {
+		return remaining - headroom
+	}
+
+	return remaining / 2
+}
+
+// NewWithTestDeadline creates a bus for a test like NewForTest, but it sets the timeout of the bus from the
+// remaining time until the deadline of the test, set with go test -timeout, minus headroom. This way, the waits
+// fail with useful diagnostics before the test framework panics on the timeout of the whole test binary. When
+// the headroom is larger than the remaining time, it uses half of the remaining time. When the test has no
+// deadline, it uses DefaultTimeout. Since the timeout is derived from the deadline, it is not scaled under the
+// race detector.
+func NewWithTestDeadline(t *testing.T, headroom time.Duration, opts ...Option) *SyncBus {
+	t.Helper()
+	timeout := DefaultTimeout
+	if deadline, ok := t.Deadline(); ok {
+		timeout = deadlineTimeout(time.Now(), deadline, headroom)
+	}
+
+	return NewForTest(t, timeout, append([]Option{WithRaceScale(1)}, opts...)...)
+}
+
+// WaitB is like Wait, but it stops the timer of the benchmark while waiting, so that the synchronization stalls
+// are not attributed to the measured code.
+//
+// If the receiver *SyncBus is nil, or no key argument is passed to it, it is a noop.
+func (b *SyncBus) WaitB(tb *testing.B, keys ...string) error {
+	if b == nil || len(keys) == 0 {
+		return nil
+	}
+
+	tb.StopTimer()
+	defer tb.StartTimer()
+	return b.Wait(keys...)
+}
+
+// DefaultSubtestTimeout is the timeout of the buses created by ForEachSubtest, unless overridden with
+// WithTimeout.
+const DefaultSubtestTimeout = 10 * time.Second
+
+// ForEachSubtest runs fn as a parallel subtest of t for each of the names, passing it a bus of its own. The
+// buses are created with NewForTest, and closed automatically when the subtests finish. Since the subtests
+// don't share the bus, they can use the same keys without interfering with each other, which is a common trap
+// when sharing a single bus across parallel subtests. The bus is passed to fn wrapped with Rewrite, prefixing
+// every key with the name of the subtest and a dot, e.g. TestFoo/bar.ready, so that the keys in the dumps,
+// errors and violations of the parallel subtests can be told apart. The options are applied to every bus.
+func ForEachSubtest(t *testing.T, names []string, fn func(t *testing.T, b *RewriteBus), opts ...Option) {
+	t.Helper()
+	for _, name := range names {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			fn(t, subtestBus(t, opts))
+		})
+	}
+}
+
+func subtestBus(t *testing.T, opts []Option) *RewriteBus {
+	return Rewrite(NewForTest(t, DefaultSubtestTimeout, opts...), AddPrefix(t.Name()+"."))
+}
+
+// VerifyClean is meant to be called during the cleanup of a test. It reports a test error when there are
+// pending waits on the bus, or when signals are set other than the expected ones. Then it closes the bus, and
+// reports an error if the run loop of the bus doesn't terminate within the timeout of the bus, or if it
+// terminated with a panic.
+//
+// If the receiver *SyncBus is nil, it is a noop.
+func (b *SyncBus) VerifyClean(t testing.TB, expectedSignals ...string) {
+	if b == nil {
+		return
+	}
+
+	t.Helper()
+	s := b.getSnapshot()
+	if len(s.waiting) > 0 {
+		t.Errorf("syncbus: %d pending waits:\n%s", len(s.waiting), s)
+	}
+
+	var unexpected []string
+	for _, key := range s.signals {
+		if !containsKey(expectedSignals, key) {
+			unexpected = append(unexpected, key)
+		}
+	}
+
+	if len(unexpected) > 0 {
+		t.Errorf("syncbus: unexpected signals: %s", strings.Join(unexpected, ", "))
+	}
+
+	b.shutdown()
+	select {
+	case <-b.exited:
+		if b.panicked != nil {
+			t.Errorf("syncbus: %v", b.panicked)
+		}
+	case <-time.After(b.timeout):
+		t.Errorf("syncbus: run loop did not terminate within %v", b.timeout)
+	}
+}
@@ -0,0 +1,211 @@
+package syncbus
+
+import (
+	"os"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestNewForTest(t *testing.T) {
+	ft := &fakeTB{}
+	b := NewForTest(ft, 12*time.Millisecond)
+	b.Signal("foo")
+	if err := b.Wait("foo"); err != nil {
+		t.Error(err)
+	}
+
+	ft.failed = true
+	ft.runCleanup()
+	for range b.Events() {
+	}
+
+	if len(ft.logs) != 0 {
+		t.Error("unexpected log", ft.logs)
+	}
+}
+
+func TestNewForTestLogsSeed(t *testing.T) {
+	ft := &fakeTB{}
+	b := NewForTest(ft, 12*time.Millisecond, WithJitter(time.Millisecond), WithSeed(42))
+	ft.runCleanup()
+	if len(ft.logs) != 0 {
+		t.Error("unexpected log", ft.logs)
+	}
+
+	ft = &fakeTB{}
+	b = NewForTest(ft, 12*time.Millisecond, WithJitter(time.Millisecond), WithSeed(42))
+	ft.failed = true
+	ft.runCleanup()
+	if len(ft.logs) != 1 || !strings.Contains(ft.logs[0], "SYNCBUS_SEED=42") {
+		t.Error("failed to log seed", ft.logs)
+	}
+
+	for range b.Events() {
+	}
+}
+
+func TestNewForTestEnvSeed(t *testing.T) {
+	t.Setenv(SeedEnv, "42")
+	b := NewForTest(t, 12*time.Millisecond, WithSeed(36))
+	if b.Seed() != 42 {
+		t.Error("failed to apply seed", b.Seed())
+	}
+
+	t.Setenv(SeedEnv, "foo")
+	ft := &fakeTB{}
+	NewForTest(ft, 12*time.Millisecond)
+	if !ft.fatal {
+		t.Error("failed to fail")
+	}
+
+	ft.runCleanup()
+}
+
+func TestNilWaitB(t *testing.T) {
+	var bus *SyncBus
+	if err := bus.WaitB(nil, "foo"); err != nil {
+		t.Error(err)
+	}
+}
+
+func BenchmarkWaitB(b *testing.B) {
+	bus := New(120 * time.Millisecond)
+	defer bus.Close()
+
+	bus.Signal("foo")
+	for i := 0; i < b.N; i++ {
+		if err := bus.WaitB(b, "foo"); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func TestForEachSubtest(t *testing.T) {
+	var (
+		mx    sync.Mutex
+		names []string
+		buses = make(map[*SyncBus]bool)
+	)
+
+	t.Run("group", func(t *testing.T) {
+		ForEachSubtest(t, []string{"foo", "bar", "baz"}, func(t *testing.T, b *RewriteBus) {
+			sb := b.bus.(*SyncBus)
+			mx.Lock()
+			names = append(names, t.Name())
+			buses[sb] = true
+			mx.Unlock()
+
+			go b.Signal("ready")
+			if err := b.Wait("ready"); err != nil {
+				t.Error(err)
+			}
+
+			if s := sb.Stats(); s.SignalCalls != 1 {
+				t.Error("bus shared between subtests", s)
+			}
+		}, WithTimeout(120*time.Millisecond))
+	})
+
+	if len(names) != 3 || len(buses) != 3 {
+		t.Fatal("failed to run the subtests", names)
+	}
+
+	for b := range buses {
+		if err := b.Wait("ready"); err != ErrClosed {
+			t.Error("failed to close the bus", err)
+		}
+	}
+}
+
+func TestForEachSubtestPrefix(t *testing.T) {
+	ForEachSubtest(t, []string{"foo"}, func(t *testing.T, b *RewriteBus) {
+		b.Signal("ready")
+		if keys := b.bus.(*SyncBus).SetKeys(); len(keys) != 1 || keys[0] != "TestForEachSubtestPrefix/foo.ready" {
+			t.Error("failed to prefix the key", keys)
+		}
+
+		if err := b.Wait("ready"); err != nil {
+			t.Error(err)
+		}
+	}, WithTimeout(120*time.Millisecond))
+}
+
+func TestNilVerifyClean(t *testing.T) {
+	var bus *SyncBus
+	bus.VerifyClean(t)
+}
+
+func TestVerifyClean(t *testing.T) {
+	ft := &fakeTB{}
+	bus := New(120 * time.Millisecond)
+	bus.Signal("foo")
+	if err := bus.Wait("foo"); err != nil {
+		t.Fatal(err)
+	}
+
+	bus.VerifyClean(ft, "foo")
+	if ft.failed {
+		t.Error("unexpected failure", ft.errors)
+	}
+
+	if !bus.closed() {
+		t.Error("failed to close the bus")
+	}
+}
+
+func TestVerifyCleanFails(t *testing.T) {
+	ft := &fakeTB{}
+	bus := New(120 * time.Millisecond)
+	bus.Signal("foo", "bar")
+	errs := make(chan error, 1)
+	go func() { errs <- bus.Wait("baz") }()
+	for bus.Stats().Waiting != 1 {
+		time.Sleep(time.Millisecond / 10)
+	}
+
+	bus.VerifyClean(ft, "foo")
+	if len(ft.errors) != 2 {
+		t.Fatal("invalid number of errors", ft.errors)
+	}
+
+	if !strings.Contains(ft.errors[0], "1 pending waits") || !strings.Contains(ft.errors[0], "missing: baz") {
+		t.Error("failed to report the pending waits", ft.errors[0])
+	}
+
+	if ft.errors[1] != "syncbus: unexpected signals: bar" {
+		t.Error("failed to report the unexpected signals", ft.errors[1])
+	}
+
+	if err := <-errs; err != ErrClosed {
+		t.Error("failed to close the bus", err)
+	}
+}
+
+func TestDeadlineTimeout(t *testing.T) {
+	now := time.Now()
+	if d := deadlineTimeout(now, now.Add(time.Minute), 10*time.Second); d != 50*time.Second {
+		t.Error("unexpected timeout", d)
+	}
+
+	if d := deadlineTimeout(now, now.Add(time.Second), 10*time.Second); d != 500*time.Millisecond {
+		t.Error("unexpected timeout", d)
+	}
+}
+
+func TestNewWithTestDeadline(t *testing.T) {
+	bus := NewWithTestDeadline(t, time.Second)
+	expected := DefaultTimeout
+	if deadline, ok := t.Deadline(); ok {
+		expected = time.Until(deadline) - time.Second
+	}
+
+	if d := bus.timeout; d > expected+time.Second || d < expected-time.Second {
+		t.Error("unexpected timeout", d, expected)
+	}
+
+	if s := bus.TimeoutScale(); s != 1 && os.Getenv(TimeoutScaleEnv) == "" {
+		t.Error("unexpected scale", s)
+	}
+}
@@ -0,0 +1,46 @@
+package syncbus
+
+import "time"
+
+type throttle struct {
+	max int
+	per time.Duration
+}
+
+// WithThrottle limits how often the signal represented by key can be set. When the key was already signaled
+// max times within the last period of the length per, the further signals of the key are dropped, as if Signal
+// was not called with it: they don't release waits, and they don't appear in the events or the history. The
+// number of the dropped signals is reported by Stats. The option can be used multiple times for different keys.
+// It is useful when high-frequency instrumentation, e.g. in a tight loop, would overwhelm the observers of the
+// bus.
+func WithThrottle(key string, max int, per time.Duration) Option {
+	return func(o *options) {
+		if o.throttles == nil {
+			o.throttles = make(map[string]throttle)
+		}
+
+		o.throttles[key] = throttle{max: max, per: per}
+	}
+}
+
+// throttle tells whether a signal of key needs to be dropped, and if not, it registers it.
+func (b *SyncBus) throttle(now time.Time, key string) bool {
+	t, ok := b.options.throttles[key]
+	if !ok {
+		return false
+	}
+
+	times := b.throttled[key]
+	for len(times) > 0 && now.Sub(times[0]) >= t.per {
+		times = times[1:]
+	}
+
+	if len(times) >= t.max {
+		b.throttled[key] = times
+		b.counters.throttled++
+		return true
+	}
+
+	b.throttled[key] = append(times, now)
+	return false
+}
@@ -0,0 +1,66 @@
+package syncbus
+
+import (
+	"testing"
+	"time"
+)
+
+func TestThrottle(t *testing.T) {
+	bus := New(12*time.Millisecond, WithThrottle("tick", 2, time.Hour), WithHistory())
+	defer bus.Close()
+
+	bus.Signal("tick")
+	bus.ResetSignals("tick")
+	bus.Signal("tick")
+	bus.ResetSignals("tick")
+	bus.Signal("tick", "other")
+	bus.Signal("tick")
+
+	if err := bus.Wait("tick"); err != ErrTimeout {
+		t.Error("failed to drop the signal", err)
+	}
+
+	if err := bus.Wait("other"); err != nil {
+		t.Error(err)
+	}
+
+	s := bus.Stats()
+	if s.ThrottledSignals != 2 || s.SignalCalls != 3 {
+		t.Error("invalid stats", s)
+	}
+
+	var signals int
+	for _, e := range bus.History() {
+		if e.Type == EventSignal {
+			signals++
+			if len(e.Keys) == 1 && e.Keys[0] == "other" {
+				continue
+			}
+
+			if len(e.Keys) != 1 || e.Keys[0] != "tick" {
+				t.Error("invalid event", e)
+			}
+		}
+	}
+
+	if signals != 3 {
+		t.Error("invalid history", bus.History())
+	}
+}
+
+func TestThrottleWindow(t *testing.T) {
+	bus := New(12*time.Millisecond, WithThrottle("tick", 1, 3*time.Millisecond))
+	defer bus.Close()
+
+	bus.Signal("tick")
+	bus.ResetSignals("tick")
+	time.Sleep(6 * time.Millisecond)
+	bus.Signal("tick")
+	if err := bus.Wait("tick"); err != nil {
+		t.Error(err)
+	}
+
+	if s := bus.Stats(); s.ThrottledSignals != 0 {
+		t.Error("invalid stats", s)
+	}
+}
@@ -0,0 +1,61 @@
+package syncbus
+
+import "time"
+
+// TimeoutOrder resolves the race between the deadline of a wait and a signal that satisfies it, when the two
+// happen essentially at the same time. Without an explicit policy, the outcome would depend on the order in
+// which the run loop happens to receive the expired timer and the signal, causing rare flakes.
+type TimeoutOrder struct {
+	signalFirst bool
+	tolerance   time.Duration
+}
+
+// DeadlineFirst enforces the deadlines strictly: a wait whose deadline has passed by the time a signal gets
+// processed times out, even if the run loop didn't handle its timer yet. This is the default.
+var DeadlineFirst = TimeoutOrder{}
+
+// SignalFirst lets the signals win: when the timer of a wait expires, the signals already sent to the bus are
+// processed before the timeouts. Additionally, a signal arriving within tolerance after the deadline of a wait
+// still satisfies it.
+func SignalFirst(tolerance time.Duration) TimeoutOrder {
+	return TimeoutOrder{signalFirst: true, tolerance: tolerance}
+}
+
+// WithTimeoutOrder sets how the bus resolves a signal and a deadline expiring at the same time. Defaults to
+// DeadlineFirst.
+func WithTimeoutOrder(o TimeoutOrder) Option {
+	return func(opts *options) { opts.timeoutOrder = o }
+}
+
+// applyTolerance extends the deadline of a new wait with the tolerance of SignalFirst.
+func (b *SyncBus) applyTolerance(w *waitItem) {
+	if b.options.timeoutOrder.signalFirst {
+		w.deadline = w.deadline.Add(b.options.timeoutOrder.tolerance)
+	}
+}
+
+// expireWaiting times out the waits whose deadline has passed, before the waits get released, with
+// DeadlineFirst.
+func (b *SyncBus) expireWaiting(now time.Time) {
+	if b.options.timeoutOrder.signalFirst || b.earliest.IsZero() || now.Before(b.earliest) {
+		return
+	}
+
+	b.timeoutWaiting(now)
+}
+
+// drainSignals processes the signals already sent to the bus, before the timeouts, with SignalFirst.
+func (b *SyncBus) drainSignals(now time.Time) {
+	if !b.options.timeoutOrder.signalFirst {
+		return
+	}
+
+	for {
+		select {
+		case signal := <-b.signal:
+			b.processSignal(now, signal)
+		default:
+			return
+		}
+	}
+}
@@ -0,0 +1,60 @@
+package syncbus
+
+import (
+	"testing"
+	"time"
+)
+
+// raceDeadline makes the deadline of a wait expire while the run loop is blocked, with a signal satisfying the
+// wait already sent to the bus, and returns the result of the wait.
+func raceDeadline(o TimeoutOrder) error {
+	var blocked bool
+	bus := New(
+		3*time.Millisecond,
+		WithSignalBuffer(1),
+		WithTimeoutOrder(o),
+		WithLoopTracing(time.Nanosecond, func(s SlowOp) {
+			if s.Op == OpWait && !blocked {
+				blocked = true
+				time.Sleep(15 * time.Millisecond)
+			}
+		}),
+	)
+
+	defer bus.Close()
+	result := make(chan error)
+	go func() { result <- bus.Wait("foo") }()
+	time.Sleep(3 * time.Millisecond)
+	bus.Signal("foo")
+	return <-result
+}
+
+func TestDeadlineFirst(t *testing.T) {
+	for i := 0; i < 12; i++ {
+		if err := raceDeadline(DeadlineFirst); err != ErrTimeout {
+			t.Fatal("failed to timeout", err)
+		}
+	}
+}
+
+func TestSignalFirst(t *testing.T) {
+	for i := 0; i < 12; i++ {
+		if err := raceDeadline(SignalFirst(0)); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestSignalFirstTolerance(t *testing.T) {
+	bus := New(3*time.Millisecond, WithTimeoutOrder(SignalFirst(30*time.Millisecond)))
+	defer bus.Close()
+
+	go func() {
+		time.Sleep(9 * time.Millisecond)
+		bus.Signal("foo")
+	}()
+
+	if err := bus.Wait("foo"); err != nil {
+		t.Error(err)
+	}
+}
@@ -0,0 +1,42 @@
+package syncbus
+
+import "time"
+
+// BindTicker sets the signal represented by key on every tick of t, until the bus is closed. The ticks are
+// consumed by the bus, so the channel of the ticker should not be read by other code.
+//
+// If the receiver *SyncBus is nil, it is a noop.
+func (b *SyncBus) BindTicker(key string, t *time.Ticker) {
+	if b == nil {
+		return
+	}
+
+	go func() {
+		for {
+			select {
+			case <-t.C:
+				b.signalBackground(key)
+			case <-b.done:
+				return
+			}
+		}
+	}()
+}
+
+// BindTimer sets the signal represented by key when tm fires. The timer event is consumed by the bus, so the
+// channel of the timer should not be read by other code.
+//
+// If the receiver *SyncBus is nil, it is a noop.
+func (b *SyncBus) BindTimer(key string, tm *time.Timer) {
+	if b == nil {
+		return
+	}
+
+	go func() {
+		select {
+		case <-tm.C:
+			b.signalBackground(key)
+		case <-b.done:
+		}
+	}()
+}
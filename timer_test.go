@@ -0,0 +1,51 @@
+package syncbus
+
+import (
+	"testing"
+	"time"
+)
+
+func TestNilBindTimers(t *testing.T) {
+	var bus *SyncBus
+	bus.BindTicker("foo", time.NewTicker(time.Hour))
+	bus.BindTimer("foo", time.NewTimer(time.Hour))
+}
+
+func TestBindTicker(t *testing.T) {
+	bus := New(120 * time.Millisecond)
+	defer bus.Close()
+
+	ticker := time.NewTicker(time.Millisecond)
+	defer ticker.Stop()
+
+	bus.BindTicker("tick", ticker)
+	for i := 0; i < 3; i++ {
+		if err := bus.Wait("tick"); err != nil {
+			t.Fatal(err)
+		}
+
+		bus.ResetSignals("tick")
+	}
+}
+
+func TestBindTimer(t *testing.T) {
+	bus := New(120 * time.Millisecond)
+	defer bus.Close()
+
+	bus.BindTimer("fired", time.NewTimer(time.Millisecond))
+	if err := bus.Wait("fired"); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestBindTimerStopped(t *testing.T) {
+	bus := New(12 * time.Millisecond)
+	tm := time.NewTimer(time.Hour)
+	bus.BindTimer("fired", tm)
+	tm.Stop()
+	if err := bus.Wait("fired"); err != ErrTimeout {
+		t.Error("failed to timeout")
+	}
+
+	bus.Close()
+}
@@ -0,0 +1,90 @@
+package syncbus
+
+import (
+	"fmt"
+	"time"
+)
+
+// LateSignal records a signal that arrived after a wait depending on it timed out, within the period set with
+// WithLateSignalTracking.
+type LateSignal struct {
+
+	// Key is the key of the late signal.
+	Key string
+
+	// Waiter is the name of the timed out wait, like in the Report.
+	Waiter string
+
+	// Delay tells how much later the signal arrived than the timeout of the wait.
+	Delay time.Duration
+}
+
+type tombstone struct {
+	waiter  string
+	missing []string
+	timeout time.Time
+}
+
+// WithLateSignalTracking makes the bus keep a tombstone of the timed out waits for the period d, and record the
+// missing keys of the waits that arrive during this period. The late signals are listed in the Report. It
+// helps distinguishing the signals that came e.g. 30ms too late from those that never came, as they require
+// different fixes. Zero means no tracking, which is the default.
+func WithLateSignalTracking(d time.Duration) Option {
+	return func(o *options) { o.lateSignalTracking = d }
+}
+
+func (b *SyncBus) addTombstone(now time.Time, w waitItem) {
+	if b.options.lateSignalTracking <= 0 {
+		return
+	}
+
+	t := tombstone{waiter: waiterName(w), timeout: now}
+	for _, key := range w.keys {
+		if !b.signals.has(key) {
+			t.missing = append(t.missing, key)
+		}
+	}
+
+	if len(t.missing) > 0 {
+		b.tombstones = append(b.tombstones, t)
+	}
+}
+
+// checkTombstones records the signal as late for the timed out waits that were missing it, and drops the
+// expired tombstones.
+func (b *SyncBus) checkTombstones(now time.Time, key string) {
+	if len(b.tombstones) == 0 {
+		return
+	}
+
+	keep := b.tombstones[:0]
+	for _, t := range b.tombstones {
+		if now.Sub(t.timeout) > b.options.lateSignalTracking {
+			continue
+		}
+
+		var missing []string
+		for _, k := range t.missing {
+			if k != key {
+				missing = append(missing, k)
+				continue
+			}
+
+			b.blocked.lateSignals = append(
+				b.blocked.lateSignals,
+				LateSignal{Key: key, Waiter: t.waiter, Delay: now.Sub(t.timeout)},
+			)
+		}
+
+		if len(missing) > 0 {
+			t.missing = missing
+			keep = append(keep, t)
+		}
+	}
+
+	b.tombstones = keep
+}
+
+func (s LateSignal) String() string {
+	return fmt.Sprintf("%s: %v after the timeout of %s", s.Key, s.Delay, s.Waiter)
+}
@@ -0,0 +1,46 @@
+package syncbus
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestLateSignalTracking(t *testing.T) {
+	bus := New(3*time.Millisecond, WithLateSignalTracking(60*time.Millisecond))
+	defer bus.Close()
+
+	bus.Signal("foo")
+	if err := bus.WaitWith([]WaitOpt{WithName("setup")}, "foo", "bar", "baz"); err != ErrTimeout {
+		t.Fatal("failed to timeout", err)
+	}
+
+	time.Sleep(6 * time.Millisecond)
+	bus.Signal("bar", "foo")
+	bus.Signal("bar")
+	r := bus.Report()
+	if len(r.LateSignals) != 1 {
+		t.Fatal("invalid late signals", r.LateSignals)
+	}
+
+	s := r.LateSignals[0]
+	if s.Key != "bar" || s.Waiter != "setup" || s.Delay < 6*time.Millisecond {
+		t.Error("invalid late signal", s)
+	}
+
+	if !strings.Contains(r.String(), "late signals:\n  bar: ") {
+		t.Error("invalid report", r)
+	}
+}
+
+func TestLateSignalTrackingExpires(t *testing.T) {
+	bus := New(3*time.Millisecond, WithLateSignalTracking(3*time.Millisecond))
+	defer bus.Close()
+
+	bus.Wait("foo")
+	time.Sleep(15 * time.Millisecond)
+	bus.Signal("foo")
+	if r := bus.Report(); len(r.LateSignals) != 0 {
+		t.Error("unexpected late signals", r.LateSignals)
+	}
+}
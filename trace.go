@@ -0,0 +1,281 @@
+package syncbus
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"time"
+)
+
+// ErrTraceMismatch is the error that TraceError unwraps to.
+var ErrTraceMismatch = errors.New("trace mismatch")
+
+// TraceOpt can be used to set the tolerance of CompareTrace.
+type TraceOpt func(*traceOptions)
+
+type traceOptions struct {
+	timing time.Duration
+	groups [][]string
+}
+
+// TraceDiff describes a difference between the golden and the actual trace.
+type TraceDiff struct {
+
+	// Index is the position of the event in the traces, after the reordering allowed by WithUnorderedGroup.
+	Index int
+
+	// Expected is the event of the golden trace. It is nil, when the actual trace contains more events.
+	Expected *Event
+
+	// Actual is the event of the actual trace. It is nil, when the golden trace contains more events.
+	Actual *Event
+
+	// Message describes the difference.
+	Message string
+}
+
+// TraceError is returned by CompareTrace, when the actual trace doesn't match the golden one.
+type TraceError struct {
+
+	// Diffs contains the differences, in the order of the events.
+	Diffs []TraceDiff
+}
+
+// WithTimingTolerance makes CompareTrace compare the timing of the events, too, measured from the first event
+// of each trace, accepting the differences up to d. By default, the timings are ignored.
+func WithTimingTolerance(d time.Duration) TraceOpt {
+	return func(o *traceOptions) { o.timing = d }
+}
+
+// WithUnorderedGroup allows the consecutive events, whose keys all belong to the group, to appear in any order,
+// e.g. the signals of concurrent workers that race each other.
+func WithUnorderedGroup(keys ...string) TraceOpt {
+	return func(o *traceOptions) { o.groups = append(o.groups, keys) }
+}
+
+func (d TraceDiff) String() string {
+	return fmt.Sprintf("event %d: %s", d.Index, d.Message)
+}
+
+func (err *TraceError) Error() string {
+	d := make([]string, len(err.Diffs))
+	for i := range err.Diffs {
+		d[i] = err.Diffs[i].String()
+	}
+
+	return fmt.Sprintf("%v: %s", ErrTraceMismatch, strings.Join(d, "; "))
+}
+
+// Unwrap returns ErrTraceMismatch.
+func (err *TraceError) Unwrap() error {
+	return ErrTraceMismatch
+}
+
+func parseEventType(s string) (EventType, error) {
+	for t := EventWait; t <= EventLate; t++ {
+		if t.String() == s {
+			return t, nil
+		}
+	}
+
+	return 0, fmt.Errorf("invalid event type: %q", s)
+}
+
+func readTrace(r io.Reader) (Trace, error) {
+	var j []eventJSON
+	if err := json.NewDecoder(r).Decode(&j); err != nil {
+		return nil, err
+	}
+
+	t := make(Trace, len(j))
+	for i, ej := range j {
+		et, err := parseEventType(ej.Type)
+		if err != nil {
+			return nil, err
+		}
+
+		t[i] = TraceEvent{
+			Event:  Event{Type: et, Keys: ej.Keys, Time: ej.Time, Meta: ej.Meta, Labels: ej.Labels},
+			Origin: ej.Origin,
+		}
+	}
+
+	return t, nil
+}
+
+// ReadTrace reads a trace written by WriteJSON.
+func ReadTrace(r io.Reader) ([]Event, error) {
+	t, err := readTrace(r)
+	if err != nil {
+		return nil, err
+	}
+
+	events := make([]Event, len(t))
+	for i := range t {
+		events[i] = t[i].Event
+	}
+
+	return events, nil
+}
+
+func formatEvent(e Event) string {
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "%v %s", e.Type, strings.Join(e.Keys, ", "))
+	if len(e.Meta) > 0 {
+		fmt.Fprintf(&buf, "; meta: %s", formatLabels(e.Meta))
+	}
+
+	if len(e.Labels) > 0 {
+		fmt.Fprintf(&buf, "; labels: %s", formatLabels(e.Labels))
+	}
+
+	return buf.String()
+}
+
+func formatTraceEvent(e TraceEvent) string {
+	if e.Origin == "" {
+		return formatEvent(e.Event)
+	}
+
+	return fmt.Sprintf("%s: %s", e.Origin, formatEvent(e.Event))
+}
+
+// eventGroup returns the index of the unordered group that the event belongs to, or -1.
+func eventGroup(e Event, groups [][]string) int {
+	if len(e.Keys) == 0 {
+		return -1
+	}
+
+	for i, g := range groups {
+		all := true
+		for _, key := range e.Keys {
+			if !containsKey(g, key) {
+				all = false
+				break
+			}
+		}
+
+		if all {
+			return i
+		}
+	}
+
+	return -1
+}
+
+// normalizeTrace sorts the runs of consecutive events belonging to the same unordered group, so that the
+// traces differing only in the order within the groups become equal.
+func normalizeTrace(t Trace, groups [][]string) Trace {
+	n := append(Trace(nil), t...)
+	if len(groups) == 0 {
+		return n
+	}
+
+	for i := 0; i < len(n); {
+		g := eventGroup(n[i].Event, groups)
+		j := i + 1
+		for g >= 0 && j < len(n) && eventGroup(n[j].Event, groups) == g {
+			j++
+		}
+
+		run := n[i:j]
+		sort.SliceStable(run, func(a, b int) bool { return formatTraceEvent(run[a]) < formatTraceEvent(run[b]) })
+		i = j
+	}
+
+	return n
+}
+
+func compareTraces(golden, actual Trace, o traceOptions) error {
+	golden = normalizeTrace(golden, o.groups)
+	actual = normalizeTrace(actual, o.groups)
+
+	var diffs []TraceDiff
+	for i := 0; i < len(golden) || i < len(actual); i++ {
+		switch {
+		case i >= len(actual):
+			diffs = append(diffs, TraceDiff{
+				Index:    i,
+				Expected: &golden[i].Event,
+				Message:  fmt.Sprintf("missing %s", formatTraceEvent(golden[i])),
+			})
+		case i >= len(golden):
+			diffs = append(diffs, TraceDiff{
+				Index:   i,
+				Actual:  &actual[i].Event,
+				Message: fmt.Sprintf("unexpected %s", formatTraceEvent(actual[i])),
+			})
+		default:
+			e, a := formatTraceEvent(golden[i]), formatTraceEvent(actual[i])
+			if e != a {
+				diffs = append(diffs, TraceDiff{
+					Index:    i,
+					Expected: &golden[i].Event,
+					Actual:   &actual[i].Event,
+					Message:  fmt.Sprintf("expected %s, got %s", e, a),
+				})
+
+				continue
+			}
+
+			if o.timing <= 0 {
+				continue
+			}
+
+			et, at := golden[i].Time.Sub(golden[0].Time), actual[i].Time.Sub(actual[0].Time)
+			if d := at - et; d > o.timing || -d > o.timing {
+				diffs = append(diffs, TraceDiff{
+					Index:    i,
+					Expected: &golden[i].Event,
+					Actual:   &actual[i].Event,
+					Message:  fmt.Sprintf("%s at %v, expected at %v", a, at, et),
+				})
+			}
+		}
+	}
+
+	if len(diffs) > 0 {
+		return &TraceError{Diffs: diffs}
+	}
+
+	return nil
+}
+
+// CompareTrace compares the recorded history to a golden trace, written earlier by WriteJSON, and returns a
+// *TraceError listing the differences, when they don't match. The events are compared by their type, keys,
+// metadata and labels. The tolerance of the comparison can be set by the options, e.g. the order of the events
+// of concurrent workers can be ignored with WithUnorderedGroup. It enables snapshot style regression tests for
+// the synchronization behavior. It requires the bus to be created with WithHistory, otherwise it returns
+// ErrNoHistory.
+//
+// If the receiver *SyncBus is nil, it is a noop.
+func (b *SyncBus) CompareTrace(golden io.Reader, opts ...TraceOpt) error {
+	if b == nil {
+		return nil
+	}
+
+	h, err := b.exportHistory()
+	if err != nil {
+		return err
+	}
+
+	return compareTrace(golden, MergeTraces(map[string][]Event{"": h}), opts)
+}
+
+func compareTrace(golden io.Reader, actual Trace, opts []TraceOpt) error {
+	g, err := readTrace(golden)
+	if err != nil {
+		return err
+	}
+
+	var o traceOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	return compareTraces(g, actual, o)
+}
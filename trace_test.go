@@ -0,0 +1,136 @@
+package syncbus
+
+import (
+	"bytes"
+	"errors"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+)
+
+func recordTrace(t *testing.T, signals ...string) *SyncBus {
+	bus := New(3*time.Millisecond, WithHistory())
+	for _, key := range signals {
+		bus.Signal(key)
+	}
+
+	bus.ResetSignals("done")
+	keys := append([]string(nil), signals...)
+	sort.Strings(keys)
+	if err := bus.Wait(keys...); err != nil {
+		t.Fatal(err)
+	}
+
+	return bus
+}
+
+func goldenTrace(t *testing.T, signals ...string) *bytes.Buffer {
+	bus := recordTrace(t, signals...)
+	defer bus.Close()
+
+	var buf bytes.Buffer
+	if err := bus.WriteJSON(&buf); err != nil {
+		t.Fatal(err)
+	}
+
+	return &buf
+}
+
+func TestNilCompareTrace(t *testing.T) {
+	var bus *SyncBus
+	if err := bus.CompareTrace(strings.NewReader("[]")); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestCompareTraceNoHistory(t *testing.T) {
+	bus := New(3 * time.Millisecond)
+	defer bus.Close()
+	if err := bus.CompareTrace(strings.NewReader("[]")); err != ErrNoHistory {
+		t.Error("failed to fail", err)
+	}
+}
+
+func TestCompareTraceInvalid(t *testing.T) {
+	bus := New(3*time.Millisecond, WithHistory())
+	defer bus.Close()
+	if err := bus.CompareTrace(strings.NewReader(`[{"type": "foo"}]`)); err == nil {
+		t.Error("failed to fail")
+	}
+}
+
+func TestCompareTrace(t *testing.T) {
+	golden := goldenTrace(t, "a", "b")
+	bus := recordTrace(t, "a", "b")
+	defer bus.Close()
+
+	if err := bus.CompareTrace(golden); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestCompareTraceMismatch(t *testing.T) {
+	golden := goldenTrace(t, "a", "b")
+	bus := recordTrace(t, "a", "c", "d")
+	defer bus.Close()
+
+	err := bus.CompareTrace(golden)
+	var terr *TraceError
+	if !errors.As(err, &terr) || !errors.Is(err, ErrTraceMismatch) {
+		t.Fatal("failed to fail", err)
+	}
+
+	// signal a, signal b/c, reset, wait, release vs. signal a, c, d, reset, wait, release:
+	if len(terr.Diffs) != 5 {
+		t.Fatal("invalid number of differences", terr.Diffs)
+	}
+
+	d := terr.Diffs[0]
+	if d.Index != 1 || d.Expected == nil || d.Actual == nil || d.Message != "expected signal b, got signal c" {
+		t.Error("invalid difference", d)
+	}
+
+	d = terr.Diffs[4]
+	if d.Index != 5 || d.Expected != nil || d.Actual == nil || !strings.HasPrefix(d.Message, "unexpected release") {
+		t.Error("invalid difference", d)
+	}
+}
+
+func TestCompareTraceUnorderedGroup(t *testing.T) {
+	golden := goldenTrace(t, "a", "b", "c")
+	bus := recordTrace(t, "c", "b", "a")
+	defer bus.Close()
+
+	if err := bus.CompareTrace(bytes.NewReader(golden.Bytes())); err == nil {
+		t.Fatal("failed to fail")
+	}
+
+	if err := bus.CompareTrace(bytes.NewReader(golden.Bytes()), WithUnorderedGroup("a", "b", "c")); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestCompareTraceTiming(t *testing.T) {
+	golden := goldenTrace(t, "a", "b")
+	bus := New(3*time.Millisecond, WithHistory())
+	defer bus.Close()
+
+	bus.Signal("a")
+	time.Sleep(30 * time.Millisecond)
+	bus.Signal("b")
+	bus.ResetSignals("done")
+	if err := bus.Wait("a", "b"); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := bus.CompareTrace(bytes.NewReader(golden.Bytes())); err != nil {
+		t.Fatal(err)
+	}
+
+	err := bus.CompareTrace(bytes.NewReader(golden.Bytes()), WithTimingTolerance(15*time.Millisecond))
+	var terr *TraceError
+	if !errors.As(err, &terr) || terr.Diffs[0].Index != 1 {
+		t.Error("failed to detect the timing difference", err)
+	}
+}
@@ -0,0 +1,208 @@
+package syncbus
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// Tracker tracks the lifecycle of the goroutines registered by the production code, and allows the tests to
+// wait until all of them reach a checkpoint, to count the live ones, or to wait for their full shutdown. The
+// methods of a nil *Tracker are no-ops, so the production code can hold a nil tracker outside of the tests.
+type Tracker struct {
+	bus     *SyncBus
+	key     string
+	mx      sync.Mutex
+	live    map[string]int
+	total   int
+	reached map[string]int
+	set     map[string]bool
+}
+
+// TrackerError is returned by the waits of the Tracker, when the goroutines don't reach the awaited state in
+// time.
+type TrackerError struct {
+
+	// Key is the key of the tracker.
+	Key string
+
+	// Checkpoint is the awaited checkpoint. It is empty when waiting for the shutdown.
+	Checkpoint string
+
+	// Live contains the names of the live goroutines, with their number in parentheses, sorted by the name.
+	Live []string
+
+	// Err is the error returned by the bus.
+	Err error
+}
+
+// Tracker creates a goroutine tracker identified by key. The signal represented by key is set whenever there
+// are no live goroutines, and the signal of key/checkpoint is set whenever all the live goroutines reached the
+// checkpoint.
+//
+// If the receiver *SyncBus is nil, it returns nil.
+func (b *SyncBus) Tracker(key string) *Tracker {
+	if b == nil {
+		return nil
+	}
+
+	b.Signal(key)
+	return &Tracker{
+		bus:     b,
+		key:     key,
+		live:    make(map[string]int),
+		reached: make(map[string]int),
+		set:     map[string]bool{key: true},
+	}
+}
+
+func (err *TrackerError) Error() string {
+	what := "shutdown"
+	if err.Checkpoint != "" {
+		what = "checkpoint " + err.Checkpoint
+	}
+
+	return fmt.Sprintf("%v: tracker %s, %s; live: %s", err.Err, err.Key, what, strings.Join(err.Live, ", "))
+}
+
+// Unwrap returns the error returned by the bus, typically ErrTimeout.
+func (err *TrackerError) Unwrap() error {
+	return err.Err
+}
+
+func (tr *Tracker) checkpointKey(checkpoint string) string {
+	return tr.key + "/" + checkpoint
+}
+
+// setKey sets or resets the signal of key, when it changes. It expects the tracker to be locked.
+func (tr *Tracker) setKey(key string, set bool) {
+	if tr.set[key] == set {
+		return
+	}
+
+	tr.set[key] = set
+	if set {
+		tr.bus.Signal(key)
+	} else {
+		tr.bus.ResetSignals(key)
+	}
+}
+
+// update sets the signals of the tracker according to the current state. It expects the tracker to be locked.
+func (tr *Tracker) update() {
+	tr.setKey(tr.key, tr.total == 0)
+	for checkpoint, n := range tr.reached {
+		tr.setKey(tr.checkpointKey(checkpoint), n >= tr.total)
+	}
+}
+
+// Started registers a started goroutine. The name identifies the goroutine in the diagnostics, and it doesn't
+// need to be unique.
+func (tr *Tracker) Started(name string) {
+	if tr == nil {
+		return
+	}
+
+	tr.mx.Lock()
+	defer tr.mx.Unlock()
+	tr.live[name]++
+	tr.total++
+	tr.update()
+}
+
+// Stopped registers a stopped goroutine. It panics when there is no live goroutine with the name.
+func (tr *Tracker) Stopped(name string) {
+	if tr == nil {
+		return
+	}
+
+	tr.mx.Lock()
+	defer tr.mx.Unlock()
+	if tr.live[name] == 0 {
+		panic(fmt.Sprintf("syncbus: stopped goroutine %s is not live", name))
+	}
+
+	tr.live[name]--
+	if tr.live[name] == 0 {
+		delete(tr.live, name)
+	}
+
+	tr.total--
+	tr.update()
+}
+
+// Checkpoint registers that a live goroutine reached the checkpoint. Every goroutine is expected to report a
+// checkpoint only once.
+func (tr *Tracker) Checkpoint(checkpoint string) {
+	if tr == nil {
+		return
+	}
+
+	tr.mx.Lock()
+	defer tr.mx.Unlock()
+	tr.reached[checkpoint]++
+	tr.update()
+}
+
+// Live returns the number of the live goroutines with the provided names, or, without names, the number of all
+// the live goroutines.
+func (tr *Tracker) Live(names ...string) int {
+	if tr == nil {
+		return 0
+	}
+
+	tr.mx.Lock()
+	defer tr.mx.Unlock()
+	if len(names) == 0 {
+		return tr.total
+	}
+
+	var n int
+	for _, name := range names {
+		n += tr.live[name]
+	}
+
+	return n
+}
+
+func (tr *Tracker) wait(checkpoint, key string) error {
+	if tr == nil {
+		return nil
+	}
+
+	err := tr.bus.Wait(key)
+	if err == nil {
+		return nil
+	}
+
+	tr.mx.Lock()
+	defer tr.mx.Unlock()
+	terr := &TrackerError{Key: tr.key, Checkpoint: checkpoint, Err: err}
+	for name, n := range tr.live {
+		terr.Live = append(terr.Live, fmt.Sprintf("%s (%d)", name, n))
+	}
+
+	sort.Strings(terr.Live)
+	return terr
+}
+
+// WaitCheckpoint blocks until all the live goroutines reached the checkpoint, and at least one of them did, or
+// the timeout of the bus expires. On timeout, it returns a *TrackerError.
+func (tr *Tracker) WaitCheckpoint(checkpoint string) error {
+	if tr == nil {
+		return nil
+	}
+
+	return tr.wait(checkpoint, tr.checkpointKey(checkpoint))
+}
+
+// WaitShutdown blocks until there are no live goroutines, or the timeout of the bus expires. On timeout, it
+// returns a *TrackerError.
+func (tr *Tracker) WaitShutdown() error {
+	if tr == nil {
+		return nil
+	}
+
+	return tr.wait("", tr.key)
+}
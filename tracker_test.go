@@ -0,0 +1,95 @@
+package syncbus
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestNilTracker(t *testing.T) {
+	var bus *SyncBus
+	tr := bus.Tracker("workers")
+	tr.Started("foo")
+	tr.Checkpoint("ready")
+	tr.Stopped("foo")
+	if tr.Live() != 0 || tr.WaitCheckpoint("ready") != nil || tr.WaitShutdown() != nil {
+		t.Error("unexpected result")
+	}
+}
+
+func TestTracker(t *testing.T) {
+	bus := New(120 * time.Millisecond)
+	defer bus.Close()
+
+	tr := bus.Tracker("workers")
+	if err := tr.WaitShutdown(); err != nil {
+		t.Fatal(err)
+	}
+
+	quit := make(chan struct{})
+	for i := 0; i < 3; i++ {
+		tr.Started("worker")
+		go func() {
+			defer tr.Stopped("worker")
+			tr.Checkpoint("ready")
+			<-quit
+		}()
+	}
+
+	if err := tr.WaitCheckpoint("ready"); err != nil {
+		t.Fatal(err)
+	}
+
+	if n := tr.Live("worker"); n != 3 {
+		t.Fatal("invalid number of live goroutines", n)
+	}
+
+	close(quit)
+	if err := tr.WaitShutdown(); err != nil {
+		t.Fatal(err)
+	}
+
+	if n := tr.Live(); n != 0 {
+		t.Error("invalid number of live goroutines", n)
+	}
+}
+
+func TestTrackerCheckpointReset(t *testing.T) {
+	bus := New(3 * time.Millisecond)
+	defer bus.Close()
+
+	tr := bus.Tracker("workers")
+	tr.Started("foo")
+	tr.Checkpoint("ready")
+	if err := tr.WaitCheckpoint("ready"); err != nil {
+		t.Fatal(err)
+	}
+
+	tr.Started("bar")
+	err := tr.WaitCheckpoint("ready")
+	var terr *TrackerError
+	if !errors.As(err, &terr) || !errors.Is(err, ErrTimeout) {
+		t.Fatal("failed to fail", err)
+	}
+
+	if len(terr.Live) != 2 || terr.Live[0] != "bar (1)" || terr.Checkpoint != "ready" {
+		t.Error("invalid error", terr)
+	}
+
+	if err := tr.WaitShutdown(); err == nil {
+		t.Error("failed to fail")
+	}
+}
+
+func TestTrackerStoppedNotLive(t *testing.T) {
+	bus := New(3 * time.Millisecond)
+	defer bus.Close()
+
+	defer func() {
+		if recover() == nil {
+			t.Error("failed to panic")
+		}
+	}()
+
+	bus.Tracker("workers").Stopped("foo")
+}
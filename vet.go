@@ -0,0 +1,164 @@
+package syncbus
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"runtime"
+	"runtime/debug"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+)
+
+// callInfo identifies the goroutine calling an operation of the bus. It is set only in vet mode.
+type callInfo struct {
+	goid  int64
+	stack string
+	site  string
+}
+
+// ErrLoopCall is returned by Wait in vet mode, when it is called from a callback executed by the run loop of the
+// bus, e.g. from the violation handler. Such a call would block the run loop forever.
+var ErrLoopCall = errors.New("called from the run loop")
+
+// WithVet enables a debug mode, in which the bus detects suspicious usage at runtime, and reports it as a
+// violation, carrying the stack of the offending call. The detected cases are:
+//
+// - Wait, Signal or Reset called from a callback executed by the run loop, e.g. from the violation handler, that
+// would otherwise block the bus forever. In this case, Wait returns ErrLoopCall, and the rest of the operations
+// are ignored;
+//
+// - Wait called from an OnSignal callback, that delays the subsequent callbacks, and blocks forever when the
+// awaited key is signaled only by one of them;
+//
+// - a Wait on a key signaled by the previous call of the same goroutine, that is satisfied by the goroutine
+// itself;
+//
+// - resetting a key that was set, and is awaited by a pending wait together with other, not yet set keys, such
+// that the wait loses a signal that it already observed.
+//
+// The vet mode captures the goroutine and the stack of every call, so it is meant only for debugging.
+func WithVet() Option {
+	return func(o *options) { o.vet = true }
+}
+
+// goid returns the ID of the current goroutine, parsed from the header of its stack trace.
+func goid() int64 {
+	var buf [64]byte
+	s := buf[:runtime.Stack(buf[:], false)]
+	s = bytes.TrimPrefix(s, []byte("goroutine "))
+	if i := bytes.IndexByte(s, ' '); i > 0 {
+		s = s[:i]
+	}
+
+	id, _ := strconv.ParseInt(string(s), 10, 64)
+	return id
+}
+
+func (b *SyncBus) callInfo() callInfo {
+	var c callInfo
+	if b.options.vet {
+		c.goid = goid()
+		c.stack = string(debug.Stack())
+	}
+
+	if b.options.attribution || b.options.auditLog || b.hasOwners() {
+		c.goid = goid()
+		c.site = externalCaller()
+	}
+
+	return c
+}
+
+// onLoop tells whether the current goroutine is the run loop. It is used to detect the calls from the callbacks
+// executed by the run loop. Since the caller is the run loop itself, it reports the violation directly.
+func (b *SyncBus) onLoop(op string, keys []string, c callInfo) bool {
+	if !b.options.vet || c.goid != atomic.LoadInt64(&b.loopID) {
+		return false
+	}
+
+	var key string
+	if len(keys) > 0 {
+		key = keys[0]
+	}
+
+	b.vetViolate(b.now(), key, fmt.Sprintf("%s called from the run loop", op), c)
+	return true
+}
+
+func (b *SyncBus) vetViolate(now time.Time, key, message string, c callInfo) {
+	b.reportViolation(Violation{Key: key, Message: message, Time: now, Stack: c.stack})
+}
+
+func (b *SyncBus) vetSignal(s signalItem) {
+	if !b.options.vet || s.background {
+		return
+	}
+
+	b.lastSignal[s.call.goid] = s.keys
+}
+
+func (b *SyncBus) vetWait(now time.Time, w waitItem) {
+	if !b.options.vet {
+		return
+	}
+
+	if w.call.goid == atomic.LoadInt64(&b.callbackID) {
+		var key string
+		if len(w.keys) > 0 {
+			key = w.keys[0]
+		}
+
+		b.vetViolate(now, key, "Wait called from an OnSignal callback", w.call)
+	}
+
+	signaled := b.lastSignal[w.call.goid]
+	delete(b.lastSignal, w.call.goid)
+	for _, key := range w.keys {
+		if containsKey(signaled, key) {
+			b.vetViolate(now, key, "waited right after signaled by the same goroutine", w.call)
+		}
+	}
+}
+
+func (b *SyncBus) vetReset(now time.Time, keys []string, c callInfo) {
+	if !b.options.vet {
+		return
+	}
+
+	delete(b.lastSignal, c.goid)
+	for _, key := range keys {
+		if !b.signals.has(key) {
+			continue
+		}
+
+		for _, w := range b.waiting {
+			if w.count != nil || w.cond != nil || w.predicate != nil || !containsKey(w.keys, key) {
+				continue
+			}
+
+			if missing := b.missing(w.keys); len(missing) > 0 {
+				b.vetViolate(
+					now,
+					key,
+					fmt.Sprintf("reset while awaited by a pending wait, still missing: %s", strings.Join(missing, ", ")),
+					c,
+				)
+
+				break
+			}
+		}
+	}
+}
+
+func containsKey(keys []string, key string) bool {
+	for _, k := range keys {
+		if k == key {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,132 @@
+package syncbus
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestVetLoopCall(t *testing.T) {
+	var errs []error
+	var bus *SyncBus
+	bus = New(120*time.Millisecond, WithVet(), WithViolationHandler(func(v Violation) {
+		errs = append(errs, bus.Wait("baz"))
+		bus.Signal("baz")
+		bus.Reset()
+	}))
+
+	defer bus.Close()
+
+	bus.Declare("foo", MaxWaiters(1))
+	go bus.Wait("foo")
+	for bus.Stats().Waiting != 1 {
+		time.Sleep(time.Millisecond / 10)
+	}
+
+	go bus.Wait("foo")
+	for len(bus.Violations()) != 4 {
+		time.Sleep(time.Millisecond / 10)
+	}
+
+	if len(errs) != 1 || errs[0] != ErrLoopCall {
+		t.Error("failed to detect the wait from the run loop", errs)
+	}
+
+	v := bus.Violations()
+	for i, op := range []string{"Wait", "Signal", "Reset"} {
+		if !strings.HasPrefix(v[i+1].Message, op) || len(v[i+1].Stack) == 0 {
+			t.Error("invalid violation", v[i+1])
+		}
+	}
+
+	if bus.SynchronizeWith("baz") {
+		t.Error("unexpected signal from the run loop")
+	}
+}
+
+func TestVetSignalThenWait(t *testing.T) {
+	bus := New(120*time.Millisecond, WithVet())
+	defer bus.Close()
+
+	bus.Signal("foo")
+	if err := bus.Wait("foo"); err != nil {
+		t.Fatal(err)
+	}
+
+	v := bus.Violations()
+	if len(v) != 1 || v[0].Key != "foo" || !strings.Contains(v[0].Stack, "TestVetSignalThenWait") {
+		t.Fatal("failed to detect signal and wait on the same goroutine", v)
+	}
+
+	done := make(chan struct{})
+	go func() {
+		bus.Signal("bar")
+		close(done)
+	}()
+
+	<-done
+	if err := bus.Wait("bar"); err != nil {
+		t.Fatal(err)
+	}
+
+	if v := bus.Violations(); len(v) != 1 {
+		t.Error("unexpected violation", v)
+	}
+}
+
+func TestVetResetRacingWait(t *testing.T) {
+	bus := New(120*time.Millisecond, WithVet())
+	defer bus.Close()
+
+	bus.Signal("foo")
+	go bus.Wait("foo", "bar")
+	for bus.Stats().Waiting != 1 {
+		time.Sleep(time.Millisecond / 10)
+	}
+
+	bus.ResetSignals("baz")
+	if v := bus.Violations(); len(v) != 0 {
+		t.Fatal("unexpected violation", v)
+	}
+
+	bus.Reset()
+	v := bus.Violations()
+	if len(v) != 1 || v[0].Key != "foo" || !strings.Contains(v[0].Message, "bar") || len(v[0].Stack) == 0 {
+		t.Error("failed to detect the reset racing the wait", v)
+	}
+}
+
+func TestWithoutVet(t *testing.T) {
+	bus := New(120 * time.Millisecond)
+	defer bus.Close()
+
+	bus.Signal("foo")
+	if err := bus.Wait("foo"); err != nil {
+		t.Fatal(err)
+	}
+
+	if v := bus.Violations(); len(v) != 0 {
+		t.Error("unexpected violation", v)
+	}
+}
+
+func TestVetWaitInCallback(t *testing.T) {
+	bus := New(120*time.Millisecond, WithVet())
+	defer bus.Close()
+
+	errs := make(chan error, 1)
+	bus.OnSignal("foo", func(string) { errs <- bus.Wait("bar") })
+	bus.Signal("foo")
+	for len(bus.Violations()) != 1 {
+		time.Sleep(time.Millisecond / 10)
+	}
+
+	if v := bus.Violations()[0]; v.Key != "bar" || v.Message != "Wait called from an OnSignal callback" || len(v.Stack) == 0 {
+		t.Error("invalid violation", v)
+	}
+
+	bus.Signal("bar")
+	if err := <-errs; err != nil {
+		t.Error(err)
+	}
+}
@@ -0,0 +1,99 @@
+package syncbus
+
+import (
+	"fmt"
+	"time"
+)
+
+// Violation describes a usage of the bus that contradicts the declared keys, or, in vet mode, a suspicious
+// usage of the bus.
+type Violation struct {
+
+	// Key is the key involved in the violation.
+	Key string
+
+	// Message describes the violation.
+	Message string
+
+	// Time tells when the violation happened.
+	Time time.Time
+
+	// Stack contains the stack trace of the offending call. It is set only for the violations detected in vet
+	// mode.
+	Stack string
+
+	// Site is the location of the offending call, outside of the package. It is set for the violations of the
+	// key ownership declared with OwnedBy.
+	Site string
+}
+
+// WithViolationHandler sets a function that is called whenever a violation of the declared keys is detected.
+// The handler is called synchronously by the run loop of the bus, and it must not call the methods of the bus.
+// (Unlike the callbacks registered with OnSignal, that are executed outside of the run loop.) It is typically
+// used to fail the test, e.g. by calling t.Error. The violations are recorded regardless of the handler, and
+// they can be retrieved by the Violations method.
+func WithViolationHandler(h func(Violation)) Option {
+	return func(o *options) { o.violationHandler = h }
+}
+
+// MaxWaiters declares that the key should have at most n concurrently pending waits. A Wait exceeding the limit
+// is reported as a violation. It helps catching the accidental registration of the same synchronization point
+// from two places of the test.
+func MaxWaiters(n int) KeyOpt {
+	return func(d *keyDecl) { d.maxWaiters = n }
+}
+
+func (v Violation) String() string {
+	return fmt.Sprintf("%s: %s", v.Key, v.Message)
+}
+
+func (b *SyncBus) violate(now time.Time, key, message string) {
+	b.reportViolation(Violation{Key: key, Message: message, Time: now})
+}
+
+// reportViolation records the violation and calls the handler. The violations detected while the handler is
+// running, e.g. in vet mode, are only recorded, to avoid infinite recursion.
+func (b *SyncBus) reportViolation(v Violation) {
+	b.violations = append(b.violations, v)
+	if b.options.violationHandler == nil || b.handling {
+		return
+	}
+
+	b.handling = true
+	defer func() { b.handling = false }()
+	b.options.violationHandler(v)
+}
+
+func (b *SyncBus) checkMaxWaiters(now time.Time, w waitItem) {
+	for _, key := range w.keys {
+		d, ok := b.declared[key]
+		if !ok || d.maxWaiters <= 0 {
+			continue
+		}
+
+		var n int
+		for _, wi := range b.waiting {
+			for _, k := range wi.keys {
+				if k == key {
+					n++
+					break
+				}
+			}
+		}
+
+		if n > d.maxWaiters {
+			b.violate(now, key, fmt.Sprintf("%d concurrent waiters, max: %d", n, d.maxWaiters))
+		}
+	}
+}
+
+// Violations returns the violations of the declared keys detected so far.
+//
+// If the receiver *SyncBus is nil, it returns nil.
+func (b *SyncBus) Violations() []Violation {
+	if b == nil {
+		return nil
+	}
+
+	return b.getSnapshot().violations
+}
@@ -0,0 +1,69 @@
+package syncbus
+
+import (
+	"testing"
+	"time"
+)
+
+func TestNilViolations(t *testing.T) {
+	var bus *SyncBus
+	if bus.Violations() != nil {
+		t.Error("unexpected violations")
+	}
+}
+
+func TestMaxWaitersViolation(t *testing.T) {
+	var handled []Violation
+	bus := New(120*time.Millisecond, WithViolationHandler(func(v Violation) {
+		handled = append(handled, v)
+	}))
+
+	defer bus.Close()
+
+	bus.Declare("ready", MaxWaiters(1))
+	tw := newTestWait(2)
+	for i := 0; i < 2; i++ {
+		go func() {
+			if err := bus.Wait("ready"); err != nil {
+				t.Error(err)
+			}
+
+			tw.done()
+		}()
+
+		for bus.Stats().Waiting != i+1 {
+			time.Sleep(time.Millisecond / 10)
+		}
+	}
+
+	bus.Signal("ready")
+	if err := tw.wait(); err != nil {
+		t.Fatal(err)
+	}
+
+	v := bus.Violations()
+	if len(v) != 1 || v[0].Key != "ready" || v[0].String() != "ready: 2 concurrent waiters, max: 1" {
+		t.Error("invalid violations", v)
+	}
+
+	if len(handled) != 1 || handled[0] != v[0] {
+		t.Error("failed to call the handler", handled)
+	}
+}
+
+func TestMaxWaitersSequential(t *testing.T) {
+	bus := New(120 * time.Millisecond)
+	defer bus.Close()
+
+	bus.Declare("ready", MaxWaiters(1))
+	bus.Signal("ready")
+	for i := 0; i < 3; i++ {
+		if err := bus.Wait("ready"); err != nil {
+			t.Error(err)
+		}
+	}
+
+	if v := bus.Violations(); len(v) != 0 {
+		t.Error("unexpected violations", v)
+	}
+}
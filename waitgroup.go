@@ -0,0 +1,130 @@
+package syncbus
+
+import (
+	"fmt"
+	"runtime"
+	"strings"
+	"sync"
+)
+
+type waitGroupAdd struct {
+	n      int
+	caller string
+}
+
+// WaitGroup is a counter similar to sync.WaitGroup, whose Wait method integrates with the timeout of the bus.
+// The key of the wait group is set whenever the counter is zero, and it is reset when the counter becomes
+// positive again.
+type WaitGroup struct {
+	bus     *SyncBus
+	key     string
+	mx      sync.Mutex
+	n       int
+	pending []waitGroupAdd
+	wg      sync.WaitGroup
+}
+
+// WaitGroupError is returned by WaitGroup.Wait when the counter doesn't reach zero in time.
+type WaitGroupError struct {
+
+	// Key is the key of the wait group.
+	Key string
+
+	// Pending contains the callers of the Add calls that were not matched by a Done call, in the order of the
+	// Add calls.
+	Pending []string
+
+	// Err is the error returned by the bus.
+	Err error
+}
+
+func (err *WaitGroupError) Error() string {
+	return fmt.Sprintf("%v: wait group %s; pending: %s", err.Err, err.Key, strings.Join(err.Pending, ", "))
+}
+
+// Unwrap returns the error returned by the bus, typically ErrTimeout.
+func (err *WaitGroupError) Unwrap() error {
+	return err.Err
+}
+
+// WaitGroup creates a wait group identified by key.
+//
+// If the receiver *SyncBus is nil, the wait group behaves like a sync.WaitGroup, and its Wait method blocks
+// without a timeout.
+func (b *SyncBus) WaitGroup(key string) *WaitGroup {
+	b.Signal(key)
+	return &WaitGroup{bus: b, key: key}
+}
+
+func caller(skip int) string {
+	_, file, line, ok := runtime.Caller(skip + 1)
+	if !ok {
+		return "unknown"
+	}
+
+	return fmt.Sprintf("%s:%d", file, line)
+}
+
+// Add adds delta, which may be negative, to the counter of the wait group. Like with sync.WaitGroup, it panics
+// if the counter becomes negative. The callers of the positive Add calls are recorded for the diagnostics.
+func (wg *WaitGroup) Add(delta int) {
+	wg.mx.Lock()
+	defer wg.mx.Unlock()
+
+	if wg.n+delta < 0 {
+		panic("syncbus: negative WaitGroup counter")
+	}
+
+	wg.wg.Add(delta)
+	if delta > 0 {
+		wg.pending = append(wg.pending, waitGroupAdd{n: delta, caller: caller(1)})
+	}
+
+	for d := -delta; d > 0; {
+		if wg.pending[0].n > d {
+			wg.pending[0].n -= d
+			break
+		}
+
+		d -= wg.pending[0].n
+		wg.pending = wg.pending[1:]
+	}
+
+	prev := wg.n
+	wg.n += delta
+
+	switch {
+	case prev == 0 && wg.n > 0:
+		wg.bus.ResetSignals(wg.key)
+	case prev > 0 && wg.n == 0:
+		wg.bus.Signal(wg.key)
+	}
+}
+
+// Done decrements the counter of the wait group by one.
+func (wg *WaitGroup) Done() {
+	wg.Add(-1)
+}
+
+// Wait blocks until the counter of the wait group becomes zero, or the timeout of the bus expires. On timeout,
+// it returns a *WaitGroupError.
+func (wg *WaitGroup) Wait() error {
+	if wg.bus == nil {
+		wg.wg.Wait()
+		return nil
+	}
+
+	err := wg.bus.Wait(wg.key)
+	if err == nil {
+		return nil
+	}
+
+	wg.mx.Lock()
+	defer wg.mx.Unlock()
+	werr := &WaitGroupError{Key: wg.key, Err: err}
+	for _, p := range wg.pending {
+		werr.Pending = append(werr.Pending, fmt.Sprintf("%s (%d)", p.caller, p.n))
+	}
+
+	return werr
+}
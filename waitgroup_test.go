@@ -0,0 +1,109 @@
+package syncbus
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestNilWaitGroup(t *testing.T) {
+	var bus *SyncBus
+	wg := bus.WaitGroup("workers")
+	wg.Add(2)
+
+	tw := newTestWait(1)
+	go func() {
+		if err := wg.Wait(); err != nil {
+			t.Error(err)
+		}
+
+		tw.done()
+	}()
+
+	wg.Done()
+	if err := tw.checkWaiting(); err != nil {
+		t.Error(err)
+	}
+
+	wg.Done()
+	if err := tw.wait(); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestWaitGroupZero(t *testing.T) {
+	bus := New(12 * time.Millisecond)
+	defer bus.Close()
+	if err := bus.WaitGroup("workers").Wait(); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestWaitGroup(t *testing.T) {
+	bus := New(120 * time.Millisecond)
+	defer bus.Close()
+
+	wg := bus.WaitGroup("workers")
+	wg.Add(3)
+	for i := 0; i < 3; i++ {
+		go wg.Done()
+	}
+
+	if err := wg.Wait(); err != nil {
+		t.Error(err)
+	}
+
+	if err := bus.Wait("workers"); err != nil {
+		t.Error(err)
+	}
+
+	wg.Add(1)
+	if bus.SynchronizeWith("workers") {
+		t.Error("failed to reset the key")
+	}
+
+	wg.Done()
+	if err := wg.Wait(); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestWaitGroupTimeout(t *testing.T) {
+	bus := New(12 * time.Millisecond)
+	defer bus.Close()
+
+	wg := bus.WaitGroup("workers")
+	wg.Add(2)
+	wg.Add(1)
+	wg.Done()
+	wg.Done()
+
+	err := wg.Wait()
+	if !errors.Is(err, ErrTimeout) {
+		t.Fatal("failed to timeout", err)
+	}
+
+	var werr *WaitGroupError
+	if !errors.As(err, &werr) {
+		t.Fatal("failed to return a wait group error", err)
+	}
+
+	if werr.Key != "workers" || len(werr.Pending) != 1 ||
+		!strings.Contains(werr.Pending[0], "waitgroup_test.go") || !strings.HasSuffix(werr.Pending[0], "(1)") {
+		t.Error("invalid diagnostics", werr)
+	}
+}
+
+func TestWaitGroupNegative(t *testing.T) {
+	bus := New(12 * time.Millisecond)
+	defer bus.Close()
+
+	defer func() {
+		if recover() == nil {
+			t.Error("failed to panic")
+		}
+	}()
+
+	bus.WaitGroup("workers").Done()
+}
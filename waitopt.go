@@ -0,0 +1,79 @@
+package syncbus
+
+import (
+	"context"
+	"time"
+)
+
+// WaitOpt can be used to customize a single wait, when calling WaitWith.
+type WaitOpt func(*waitItem)
+
+// WithName sets a name for the wait, that is displayed in the reports about the waits, e.g. in Dump, in the
+// WaitGraph or in the Report.
+func WithName(name string) WaitOpt {
+	return func(w *waitItem) { w.name = name }
+}
+
+// WithTimeoutOpt sets the timeout of the wait, overriding the timeout of the bus. A zero or negative timeout
+// means the timeout of the bus.
+func WithTimeoutOpt(timeout time.Duration) WaitOpt {
+	return func(w *waitItem) { w.timeout = timeout }
+}
+
+// WithContextOpt sets a context for the wait, like with WaitContext.
+func WithContextOpt(ctx context.Context) WaitOpt {
+	return func(w *waitItem) { w.ctx = ctx }
+}
+
+// WithNoBlockIfSet makes the wait return immediately when the awaited signals are already set at the time of
+// the call, bypassing the randomized scheduling of the bus, like WithJitter or WithPCT, that would otherwise
+// delay it.
+func WithNoBlockIfSet() WaitOpt {
+	return func(w *waitItem) { w.noBlockIfSet = true }
+}
+
+// releaseIfSet releases a wait created with WithNoBlockIfSet, when it is already satisfied at the time of
+// registering it. It tells whether the wait was released.
+func (b *SyncBus) releaseIfSet(now time.Time, w waitItem) bool {
+	if !w.noBlockIfSet {
+		return false
+	}
+
+	done, err := b.checkWaiting(w)
+	if !done {
+		return false
+	}
+
+	w.release(err)
+	w.start = now
+	b.accountBlocked(now, w)
+	b.counters.waits++
+	b.counters.releases++
+	b.countLabels(w, func(c *counters) {
+		c.waits++
+		c.releases++
+	})
+
+	b.emit(Event{Type: EventWait, Keys: w.keys, Labels: w.labels, Time: now})
+	if err == nil {
+		b.emit(Event{Type: EventRelease, Keys: w.keys, Labels: w.labels, Time: now})
+	}
+
+	return true
+}
+
+// WaitWith is like Wait, customized by the provided options.
+//
+// If the receiver *SyncBus is nil, or no key argument is passed to it, it is a noop.
+func (b *SyncBus) WaitWith(opts []WaitOpt, keys ...string) error {
+	if b == nil || len(keys) == 0 {
+		return nil
+	}
+
+	w := waitItem{keys: keys}
+	for _, opt := range opts {
+		opt(&w)
+	}
+
+	return b.waitItem(w)
+}
@@ -0,0 +1,86 @@
+package syncbus
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestNilWaitWith(t *testing.T) {
+	var bus *SyncBus
+	if err := bus.WaitWith([]WaitOpt{WithName("foo")}, "foo"); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestWaitWithName(t *testing.T) {
+	bus := New(120 * time.Millisecond)
+	defer bus.Close()
+
+	errs := make(chan error, 1)
+	go func() { errs <- bus.WaitWith([]WaitOpt{WithName("ready check")}, "foo") }()
+	for bus.Stats().Waiting != 1 {
+		time.Sleep(time.Millisecond / 10)
+	}
+
+	if d := bus.Dump(); !strings.Contains(d, "name: ready check") {
+		t.Error("failed to display the name", d)
+	}
+
+	g := bus.WaitGraph()
+	if len(g.Waiters) != 1 || g.Waiters[0].Name != "ready check" || !strings.Contains(g.DOT(), "ready check") {
+		t.Error("failed to display the name in the wait graph", g)
+	}
+
+	bus.Signal("foo")
+	if err := <-errs; err != nil {
+		t.Error(err)
+	}
+}
+
+func TestWaitWithTimeout(t *testing.T) {
+	bus := New(time.Hour)
+	defer bus.Close()
+
+	if err := bus.WaitWith([]WaitOpt{WithTimeoutOpt(12 * time.Millisecond)}, "foo"); err != ErrTimeout {
+		t.Error("failed to timeout", err)
+	}
+}
+
+func TestWaitWithContext(t *testing.T) {
+	bus := New(time.Hour)
+	defer bus.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := bus.WaitWith([]WaitOpt{WithContextOpt(ctx)}, "foo"); err != context.Canceled {
+		t.Error("failed to cancel", err)
+	}
+}
+
+func TestWaitWithNoBlockIfSet(t *testing.T) {
+	bus := New(120*time.Millisecond, WithJitter(time.Hour), WithPCT(3, 10))
+	defer bus.Close()
+
+	bus.Signal("foo")
+	errs := make(chan error, 2)
+	for i := 0; i < 2; i++ {
+		go func() { errs <- bus.WaitWith([]WaitOpt{WithNoBlockIfSet()}, "foo") }()
+	}
+
+	for i := 0; i < 2; i++ {
+		select {
+		case err := <-errs:
+			if err != nil {
+				t.Error(err)
+			}
+		case <-time.After(60 * time.Millisecond):
+			t.Fatal("failed to return immediately")
+		}
+	}
+
+	if s := bus.Stats(); s.Waits != 2 || s.Releases != 2 {
+		t.Error("unexpected stats", s)
+	}
+}
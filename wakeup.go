@@ -0,0 +1,53 @@
+package syncbus
+
+type wakeupKind int
+
+const (
+	fifoWakeup wakeupKind = iota
+	lifoWakeup
+	seededWakeup
+	shuffledWakeup
+)
+
+// WakeupOrder controls the order in which the waits, satisfied by the same operation on the bus, are
+// released. The release events are emitted in the same order.
+type WakeupOrder struct {
+	kind wakeupKind
+	seed int64
+	rand *random
+}
+
+var (
+	// FIFO releases the waits in the order they were registered. This is the default.
+	FIFO = WakeupOrder{kind: fifoWakeup}
+
+	// LIFO releases the waits in the reverse order of their registration.
+	LIFO = WakeupOrder{kind: lifoWakeup}
+)
+
+// Seeded releases the waits in a random order, that is reproducible with the same seed, independent of
+// the seed of the bus.
+func Seeded(seed int64) WakeupOrder {
+	return WakeupOrder{kind: seededWakeup, seed: seed}
+}
+
+// WithWakeupOrder sets the order in which the simultaneously satisfied waits are released. Defaults to FIFO.
+func WithWakeupOrder(o WakeupOrder) Option {
+	return func(opts *options) { opts.wakeupOrder = o }
+}
+
+func (o WakeupOrder) order(busRand *random, r []released) []released {
+	swap := func(i, j int) { r[i], r[j] = r[j], r[i] }
+	switch o.kind {
+	case lifoWakeup:
+		for i := 0; i < len(r)/2; i++ {
+			swap(i, len(r)-1-i)
+		}
+	case seededWakeup:
+		o.rand.shuffle(len(r), swap)
+	case shuffledWakeup:
+		busRand.shuffle(len(r), swap)
+	}
+
+	return r
+}
@@ -0,0 +1,94 @@
+package syncbus
+
+import (
+	"testing"
+	"time"
+)
+
+func wakeupOrder(t *testing.T, o WakeupOrder) []string {
+	bus := New(120*time.Millisecond, WithWakeupOrder(o))
+	defer bus.Close()
+
+	keys := []string{"a", "b", "c", "d", "e"}
+	tw := newTestWait(len(keys))
+	for i, key := range keys {
+		go func(key string) {
+			if err := bus.Wait("foo", key); err != nil {
+				t.Error(err)
+			}
+
+			tw.done()
+		}(key)
+
+		for bus.Stats().Waiting != i+1 {
+			time.Sleep(time.Millisecond / 10)
+		}
+	}
+
+	bus.Signal(keys...)
+	events := bus.Events()
+	for {
+		if e := receiveEvent(t, events); e.Type == EventSignal {
+			break
+		}
+	}
+
+	bus.Signal("foo")
+	receiveEvent(t, events)
+
+	var order []string
+	for range keys {
+		e := receiveEvent(t, events)
+		if e.Type != EventRelease {
+			t.Fatal("unexpected event", e)
+		}
+
+		order = append(order, e.Keys[1])
+	}
+
+	if err := tw.wait(); err != nil {
+		t.Fatal(err)
+	}
+
+	return order
+}
+
+func checkOrder(t *testing.T, got []string, expected ...string) {
+	if len(got) != len(expected) {
+		t.Fatal("invalid order", got)
+	}
+
+	for i := range got {
+		if got[i] != expected[i] {
+			t.Fatal("invalid order", got)
+		}
+	}
+}
+
+func TestWakeupFIFO(t *testing.T) {
+	checkOrder(t, wakeupOrder(t, FIFO), "a", "b", "c", "d", "e")
+}
+
+func TestWakeupLIFO(t *testing.T) {
+	checkOrder(t, wakeupOrder(t, LIFO), "e", "d", "c", "b", "a")
+}
+
+func TestWakeupSeeded(t *testing.T) {
+	o := wakeupOrder(t, Seeded(42))
+	checkOrder(t, wakeupOrder(t, Seeded(42)), o...)
+
+	var differs bool
+	for seed := int64(0); seed < 9 && !differs; seed++ {
+		oi := wakeupOrder(t, Seeded(seed))
+		for i := range o {
+			if oi[i] != o[i] {
+				differs = true
+				break
+			}
+		}
+	}
+
+	if !differs {
+		t.Error("failed to randomize order")
+	}
+}
@@ -0,0 +1,121 @@
+package syncbus
+
+import "time"
+
+// KeyState is the state of a key, as reported by Watch.
+type KeyState struct {
+
+	// Set tells whether the signal represented by the key is set.
+	Set bool
+
+	// Count is the number of times the signal was set since the bus was created.
+	Count int
+
+	// Value is the value stored under the key by Put, if any.
+	Value interface{}
+
+	// HasValue tells whether a value is stored under the key.
+	HasValue bool
+}
+
+// Change describes a transition of a key, as reported by Watch.
+type Change struct {
+
+	// Key is the key that changed.
+	Key string
+
+	// Old is the state of the key before the change.
+	Old KeyState
+
+	// New is the state of the key after the change.
+	New KeyState
+
+	// Meta contains the metadata of the signal that caused the change, when it was set with SignalMeta.
+	Meta map[string]string
+
+	// Time tells when the change happened.
+	Time time.Time
+}
+
+type watchItem struct {
+	key    string
+	result chan (<-chan Change)
+}
+
+func (b *SyncBus) keyState(key string) KeyState {
+	v, ok := b.values[key]
+	return KeyState{Set: b.signals.has(key), Count: b.signals.count(key), Value: v, HasValue: ok}
+}
+
+func (b *SyncBus) addWatcher(key string) <-chan Change {
+	c := make(chan Change, b.options.eventBuffer)
+	b.watchers[key] = append(b.watchers[key], c)
+	return c
+}
+
+// notifyWatchers sends the change of a key to its watchers, without blocking. When the buffer of a watcher is
+// full, the change is dropped.
+func (b *SyncBus) notifyWatchers(now time.Time, key string, old KeyState, meta map[string]string) {
+	watchers := b.watchers[key]
+	if len(watchers) == 0 {
+		return
+	}
+
+	c := Change{Key: key, Old: old, New: b.keyState(key), Meta: meta, Time: now}
+	for _, w := range watchers {
+		select {
+		case w <- c:
+		default:
+		}
+	}
+}
+
+// watchedSet returns the state of the watched keys that are set.
+func (b *SyncBus) watchedSet() map[string]KeyState {
+	if len(b.watchers) == 0 {
+		return nil
+	}
+
+	s := make(map[string]KeyState)
+	for key := range b.watchers {
+		if b.signals.has(key) {
+			s[key] = b.keyState(key)
+		}
+	}
+
+	return s
+}
+
+func (b *SyncBus) closeWatchers() {
+	for _, watchers := range b.watchers {
+		for _, w := range watchers {
+			close(w)
+		}
+	}
+}
+
+// Watch returns a channel that receives a Change for every transition of the key: when its signal gets set,
+// including when it was already set, and when it gets cleared. The channel is buffered with the size set by
+// WithEventBuffer, and when the buffer is full, the changes are dropped. The channel is closed when the bus is
+// closed.
+//
+// If the receiver *SyncBus is nil, or the bus is closed, it returns a closed channel.
+func (b *SyncBus) Watch(key string) <-chan Change {
+	closed := make(chan Change)
+	close(closed)
+	if b == nil {
+		return closed
+	}
+
+	w := watchItem{key: key, result: make(chan (<-chan Change), 1)}
+	if b.closed() {
+		return closed
+	}
+
+	select {
+	case b.watch <- w:
+		return <-w.result
+	case <-b.done:
+		return closed
+	}
+}
@@ -0,0 +1,57 @@
+package syncbus
+
+import (
+	"testing"
+	"time"
+)
+
+func TestNilWatch(t *testing.T) {
+	var bus *SyncBus
+	if _, ok := <-bus.Watch("foo"); ok {
+		t.Error("unexpected change")
+	}
+}
+
+func TestWatch(t *testing.T) {
+	bus := New(120 * time.Millisecond)
+	c := bus.Watch("foo")
+	bus.Signal("foo")
+	bus.Put("foo", 42)
+	bus.Signal("bar")
+	bus.ResetSignals("foo")
+	bus.ResetSignals("foo")
+	bus.Signal("foo")
+	bus.Reset()
+	bus.Close()
+
+	expected := []Change{
+		{Old: KeyState{}, New: KeyState{Set: true, Count: 1}},
+		{Old: KeyState{Set: true, Count: 1}, New: KeyState{Set: true, Count: 2, Value: 42, HasValue: true}},
+		{Old: KeyState{Set: true, Count: 2, Value: 42, HasValue: true}, New: KeyState{Count: 2}},
+		{Old: KeyState{Count: 2}, New: KeyState{Set: true, Count: 3}},
+		{Old: KeyState{Set: true, Count: 3}, New: KeyState{Count: 3}},
+	}
+
+	var changes []Change
+	for ch := range c {
+		changes = append(changes, ch)
+	}
+
+	if len(changes) != len(expected) {
+		t.Fatal("invalid number of changes", changes)
+	}
+
+	for i := range expected {
+		if changes[i].Key != "foo" || changes[i].Old != expected[i].Old || changes[i].New != expected[i].New {
+			t.Error("invalid change", i, changes[i])
+		}
+	}
+}
+
+func TestWatchAfterClose(t *testing.T) {
+	bus := New(120 * time.Millisecond)
+	bus.Close()
+	if _, ok := <-bus.Watch("foo"); ok {
+		t.Error("unexpected change")
+	}
+}
@@ -0,0 +1,89 @@
+package syncbus
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"runtime"
+	"time"
+)
+
+type watchdog struct {
+	timeout time.Duration
+	handler func(dump string)
+	timer   *time.Timer
+	last    time.Time
+}
+
+// WithWatchdog sets a bus level watchdog, that fires when the bus sees no activity for d, independent of the
+// deadlines of the individual waits. It is meant to catch the wedged test suites, where the timeouts of the
+// waits are generous, yet the suite as a whole hangs. A typical value is twice the longest expected phase of
+// the suite. When the watchdog fires, h is called, on a separate goroutine, with a dump of the bus state and the
+// stacks of all the goroutines. When h is nil, the dump is written to the standard error, and the process is
+// aborted with a panic. After firing, the watchdog gets rearmed. Zero means no watchdog, which is the default.
+func WithWatchdog(d time.Duration, h func(dump string)) Option {
+	return func(o *options) {
+		o.watchdog = d
+		o.watchdogHandler = h
+	}
+}
+
+func newWatchdog(d time.Duration, h func(string)) *watchdog {
+	if d <= 0 {
+		return nil
+	}
+
+	return &watchdog{timeout: d, handler: h, timer: time.NewTimer(d), last: time.Now()}
+}
+
+func (b *SyncBus) watchdogTimer() <-chan time.Time {
+	if b.watchdog == nil {
+		return nil
+	}
+
+	return b.watchdog.timer.C
+}
+
+// feedWatchdog records the activity on the bus.
+func (b *SyncBus) feedWatchdog() {
+	if b.watchdog != nil {
+		b.watchdog.last = time.Now()
+	}
+}
+
+func (b *SyncBus) stopWatchdog() {
+	if b.watchdog != nil {
+		b.watchdog.timer.Stop()
+	}
+}
+
+// checkWatchdog fires the watchdog, when there was no activity on the bus for its timeout. Otherwise, it
+// rearms it for the rest of its timeout.
+func (b *SyncBus) checkWatchdog(now time.Time) {
+	wd := b.watchdog
+	idle := now.Sub(wd.last)
+	if idle < wd.timeout {
+		wd.timer.Reset(wd.timeout - idle)
+		return
+	}
+
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "watchdog: no activity on the bus for %v\n", idle)
+	fmt.Fprint(&buf, b.createSnapshot(now).String())
+	stack := make([]byte, 1<<20)
+	stack = stack[:runtime.Stack(stack, true)]
+	fmt.Fprintf(&buf, "goroutines:\n%s", stack)
+	dump := buf.String()
+
+	wd.last = now
+	wd.timer.Reset(wd.timeout)
+	if wd.handler != nil {
+		go wd.handler(dump)
+		return
+	}
+
+	go func() {
+		fmt.Fprint(os.Stderr, dump)
+		panic(fmt.Sprintf("syncbus: watchdog: no activity on the bus for %v", idle))
+	}()
+}
@@ -0,0 +1,51 @@
+package syncbus
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestWatchdog(t *testing.T) {
+	dumps := make(chan string, 1)
+	bus := New(time.Hour, WithWatchdog(15*time.Millisecond, func(dump string) {
+		select {
+		case dumps <- dump:
+		default:
+		}
+	}))
+
+	defer bus.Close()
+
+	go bus.Wait("foo")
+	select {
+	case dump := <-dumps:
+		if !strings.Contains(dump, "missing: foo") || !strings.Contains(dump, "goroutines:") {
+			t.Error("invalid dump", dump)
+		}
+	case <-time.After(120 * time.Millisecond):
+		t.Fatal("watchdog failed to fire")
+	}
+}
+
+func TestWatchdogActivity(t *testing.T) {
+	fired := make(chan struct{}, 1)
+	bus := New(time.Hour, WithWatchdog(30*time.Millisecond, func(string) {
+		select {
+		case fired <- struct{}{}:
+		default:
+		}
+	}))
+
+	for i := 0; i < 12; i++ {
+		time.Sleep(6 * time.Millisecond)
+		bus.Signal("foo")
+	}
+
+	bus.Close()
+	select {
+	case <-fired:
+		t.Error("watchdog fired despite the activity")
+	default:
+	}
+}
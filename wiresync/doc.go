@@ -0,0 +1,6 @@
+/*
+Package wiresync serves a syncbus.SyncBus over a line delimited JSON protocol, and provides a client for it,
+so that test components in other processes, or written in other languages, can participate in the same
+synchronization plan. It also allows sharing a single bus across the test processes of a suite.
+*/
+package wiresync
@@ -0,0 +1,152 @@
+package wiresync
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"sync"
+	"testing"
+
+	"github.com/aryszka/syncbus"
+)
+
+// SharedBusEnv is the name of the environment variable that holds the address of the bus shared by
+// RunSharedBus. It is set by RunSharedBus, so that the processes started by the tests can connect to the same
+// bus, and it is read by SharedBus.
+const SharedBusEnv = "SYNCBUS_SHARED_ADDR"
+
+// ErrNoSharedBus is returned by SharedBus when no shared bus was started or configured.
+var ErrNoSharedBus = errors.New("no shared bus")
+
+var shared struct {
+	lock sync.Mutex
+	bus  syncbus.Bus
+}
+
+type sharedHost struct {
+	bus      *syncbus.SyncBus
+	listener net.Listener
+	conns    sync.WaitGroup
+}
+
+type trackedListener struct {
+	net.Listener
+	conns *sync.WaitGroup
+}
+
+type trackedConn struct {
+	net.Conn
+	once sync.Once
+	done func()
+}
+
+func (l trackedListener) Accept() (net.Conn, error) {
+	conn, err := l.Listener.Accept()
+	if err != nil {
+		return nil, err
+	}
+
+	l.conns.Add(1)
+	return &trackedConn{Conn: conn, done: l.conns.Done}, nil
+}
+
+func (c *trackedConn) Close() error {
+	err := c.Conn.Close()
+	c.once.Do(c.done)
+	return err
+}
+
+func listenShared(addr string, opts []syncbus.Option) (*sharedHost, error) {
+	l, err := net.Listen("tcp", addr)
+	if err != nil {
+		return nil, err
+	}
+
+	h := &sharedHost{bus: syncbus.New(syncbus.DefaultTimeout, opts...), listener: l}
+
+	// The host itself counts as a connection until it gets closed, so that accepting a connection never
+	// races with waiting for the connections to finish.
+	h.conns.Add(1)
+
+	go Serve(h.bus, trackedListener{Listener: l, conns: &h.conns})
+	return h, nil
+}
+
+// close stops accepting connections, and closes the bus, after the connected clients disconnected.
+func (h *sharedHost) close() {
+	h.listener.Close()
+	h.conns.Done()
+	h.conns.Wait()
+	h.bus.Close()
+}
+
+func setShared(b syncbus.Bus) {
+	shared.lock.Lock()
+	defer shared.lock.Unlock()
+	shared.bus = b
+}
+
+// RunSharedBus runs the tests of a package, like m.Run, sharing a single bus across the packages and the
+// processes of the suite. It is meant to be called from TestMain:
+//
+//	func TestMain(m *testing.M) {
+//		os.Exit(wiresync.RunSharedBus(m, "127.0.0.1:7531"))
+//	}
+//
+// When running the tests of multiple packages in one go test ./... invocation, the first test process that
+// binds to addr hosts the bus, created with syncbus.DefaultTimeout and the provided options, and serves it
+// with the wire protocol of Serve. The other test processes connect to it as clients. The tests access the
+// shared bus with SharedBus. The hosting process, after its tests finished, keeps serving the bus until the
+// connected clients disconnect. When addr is empty, the value of SYNCBUS_SHARED_ADDR is used. RunSharedBus
+// sets SYNCBUS_SHARED_ADDR to the address of the shared bus, so that the processes started by the tests can
+// connect to it, too. It returns the exit code of the tests, or 1, when it failed to either host or connect
+// to the shared bus.
+func RunSharedBus(m *testing.M, addr string, opts ...syncbus.Option) int {
+	if addr == "" {
+		addr = os.Getenv(SharedBusEnv)
+	}
+
+	h, err := listenShared(addr, opts)
+	if err == nil {
+		defer h.close()
+		os.Setenv(SharedBusEnv, h.listener.Addr().String())
+		setShared(h.bus)
+		return m.Run()
+	}
+
+	c, derr := Dial(addr)
+	if derr != nil {
+		fmt.Fprintf(os.Stderr, "syncbus: failed to host or connect to the shared bus: %v; %v\n", err, derr)
+		return 1
+	}
+
+	defer c.Close()
+	os.Setenv(SharedBusEnv, addr)
+	setShared(c)
+	return m.Run()
+}
+
+// SharedBus returns the bus shared by RunSharedBus. When RunSharedBus was not called in the current process,
+// e.g. in a process started by the tests, it connects to the address found in SYNCBUS_SHARED_ADDR. When
+// neither is available, it returns ErrNoSharedBus.
+func SharedBus() (syncbus.Bus, error) {
+	shared.lock.Lock()
+	defer shared.lock.Unlock()
+	if shared.bus != nil {
+		return shared.bus, nil
+	}
+
+	addr := os.Getenv(SharedBusEnv)
+	if addr == "" {
+		return nil, ErrNoSharedBus
+	}
+
+	c, err := Dial(addr)
+	if err != nil {
+		return nil, err
+	}
+
+	shared.bus = c
+	return c, nil
+}
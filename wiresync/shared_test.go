@@ -0,0 +1,55 @@
+package wiresync
+
+import (
+	"testing"
+	"time"
+)
+
+func TestSharedBus(t *testing.T) {
+	h, err := listenShared("127.0.0.1:0", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Setenv(SharedBusEnv, h.listener.Addr().String())
+	defer setShared(nil)
+	b, err := SharedBus()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if bb, _ := SharedBus(); bb != b {
+		t.Error("failed to reuse the shared bus")
+	}
+
+	b.Signal("foo")
+	if err := h.bus.Wait("foo"); err != nil {
+		t.Fatal(err)
+	}
+
+	closed := make(chan struct{})
+	go func() {
+		h.close()
+		close(closed)
+	}()
+
+	select {
+	case <-closed:
+		t.Fatal("closed with connected clients")
+	case <-time.After(15 * time.Millisecond):
+	}
+
+	b.Close()
+	select {
+	case <-closed:
+	case <-time.After(120 * time.Millisecond):
+		t.Fatal("failed to close")
+	}
+}
+
+func TestNoSharedBus(t *testing.T) {
+	t.Setenv(SharedBusEnv, "")
+	if _, err := SharedBus(); err != ErrNoSharedBus {
+		t.Error("failed to fail", err)
+	}
+}
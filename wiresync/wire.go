@@ -0,0 +1,325 @@
+package wiresync
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/aryszka/syncbus"
+)
+
+// ProtocolVersion is the version of the wire protocol implemented by Serve and Client.
+const ProtocolVersion = 1
+
+// Error codes of the wire protocol.
+const (
+	CodeTimeout = "timeout"
+	CodeClosed  = "closed"
+	CodeVersion = "version"
+	CodeInvalid = "invalid"
+	CodeError   = "error"
+)
+
+// ErrProtocol is returned by the Client when the server responds with an unsupported protocol version, or an
+// invalid message.
+var ErrProtocol = errors.New("protocol error")
+
+// RemoteError is returned by the Client when the server failed to execute a request, for a reason other than
+// timeout or closing.
+type RemoteError struct {
+
+	// Code is the error code sent by the server.
+	Code string
+
+	// Message describes the error.
+	Message string
+}
+
+func (err *RemoteError) Error() string {
+	return fmt.Sprintf("remote error: %s: %s", err.Code, err.Message)
+}
+
+type wireRequest struct {
+	V       int      `json:"v"`
+	ID      uint64   `json:"id"`
+	Op      string   `json:"op"`
+	Keys    []string `json:"keys,omitempty"`
+	Timeout int64    `json:"timeout_ms,omitempty"`
+}
+
+type wireResponse struct {
+	V       int      `json:"v"`
+	ID      uint64   `json:"id"`
+	Code    string   `json:"code,omitempty"`
+	Error   string   `json:"error,omitempty"`
+	Signals []string `json:"signals,omitempty"`
+	Waiting int      `json:"waiting,omitempty"`
+}
+
+func errorCode(err error) string {
+	switch {
+	case errors.Is(err, syncbus.ErrTimeout):
+		return CodeTimeout
+	case errors.Is(err, syncbus.ErrClosed):
+		return CodeClosed
+	default:
+		return CodeError
+	}
+}
+
+func serveRequest(b *syncbus.SyncBus, req wireRequest) wireResponse {
+	rsp := wireResponse{V: ProtocolVersion, ID: req.ID}
+	if req.V != ProtocolVersion {
+		rsp.Code = CodeVersion
+		rsp.Error = fmt.Sprintf("unsupported protocol version: %d", req.V)
+		return rsp
+	}
+
+	var err error
+	switch req.Op {
+	case "signal":
+		b.Signal(req.Keys...)
+	case "wait":
+		err = b.WaitTimeout(time.Duration(req.Timeout)*time.Millisecond, req.Keys...)
+	case "reset":
+		b.ResetSignals(req.Keys...)
+	case "resetall":
+		b.Reset()
+	case "inspect":
+		g := b.WaitGraph()
+		rsp.Signals = g.Signals
+		rsp.Waiting = len(g.Waiters)
+	default:
+		rsp.Code = CodeInvalid
+		rsp.Error = fmt.Sprintf("invalid operation: %q", req.Op)
+		return rsp
+	}
+
+	if err != nil {
+		rsp.Code = errorCode(err)
+		rsp.Error = err.Error()
+	}
+
+	return rsp
+}
+
+func serveConn(b *syncbus.SyncBus, conn net.Conn) {
+	defer conn.Close()
+	var (
+		mx sync.Mutex
+		wg sync.WaitGroup
+	)
+
+	enc := json.NewEncoder(conn)
+	send := func(rsp wireResponse) {
+		mx.Lock()
+		defer mx.Unlock()
+		enc.Encode(rsp)
+	}
+
+	defer wg.Wait()
+	dec := json.NewDecoder(bufio.NewReader(conn))
+	for {
+		var req wireRequest
+		if err := dec.Decode(&req); err != nil {
+			if err != io.EOF {
+				send(wireResponse{V: ProtocolVersion, Code: CodeInvalid, Error: err.Error()})
+			}
+
+			return
+		}
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			send(serveRequest(b, req))
+		}()
+	}
+}
+
+// Serve accepts connections on the listener, and serves the requests of the wire protocol on them with bus,
+// so that test components written in other languages can participate in the same synchronization plan. It
+// returns when the listener fails, e.g. because it was closed.
+//
+// The protocol is line delimited JSON. The requests have the form:
+//
+//	{"v": 1, "id": 42, "op": "wait", "keys": ["foo", "bar"], "timeout_ms": 100}
+//
+// where v is the protocol version, id is chosen by the client to match the response, op is one of signal,
+// wait, reset, resetall and inspect, keys are the keys of the operation, and timeout_ms is the optional
+// timeout of a wait, overriding the timeout of the bus. The requests on a connection are executed
+// concurrently, and the responses are sent as soon as they complete, in the form:
+//
+//	{"v": 1, "id": 42, "code": "timeout", "error": "timeout"}
+//
+// where code and error are set only when the request failed. The codes are timeout, closed, version (the
+// version of the request is not supported), invalid (invalid request) and error (other failures). The
+// response to inspect contains the set signals in the field signals, and the number of the pending waits in
+// the field waiting.
+//
+// If bus is nil, it serves the requests as noops.
+func Serve(bus *syncbus.SyncBus, l net.Listener) error {
+	for {
+		conn, err := l.Accept()
+		if err != nil {
+			return err
+		}
+
+		go serveConn(bus, conn)
+	}
+}
+
+// Client connects to a bus served with Serve. It implements the syncbus.Bus interface.
+type Client struct {
+	conn    net.Conn
+	mx      sync.Mutex
+	enc     *json.Encoder
+	nextID  uint64
+	pending map[uint64]chan wireResponse
+	err     error
+	done    chan struct{}
+}
+
+// Dial connects to a bus served with Serve, at the provided TCP address.
+func Dial(addr string) (*Client, error) {
+	conn, err := net.Dial("tcp", addr)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewClient(conn), nil
+}
+
+// NewClient creates a client of the wire protocol using an existing connection.
+func NewClient(conn net.Conn) *Client {
+	c := &Client{
+		conn:    conn,
+		enc:     json.NewEncoder(conn),
+		pending: make(map[uint64]chan wireResponse),
+		done:    make(chan struct{}),
+	}
+
+	go c.receive()
+	return c
+}
+
+func (c *Client) receive() {
+	dec := json.NewDecoder(bufio.NewReader(c.conn))
+	for {
+		var rsp wireResponse
+		err := dec.Decode(&rsp)
+		if err == nil && rsp.V != ProtocolVersion {
+			err = ErrProtocol
+		}
+
+		c.mx.Lock()
+		if err != nil {
+			c.err = err
+			close(c.done)
+			c.mx.Unlock()
+			return
+		}
+
+		r, ok := c.pending[rsp.ID]
+		delete(c.pending, rsp.ID)
+		c.mx.Unlock()
+		if ok {
+			r <- rsp
+		}
+	}
+}
+
+func (c *Client) request(op string, timeout time.Duration, keys []string) (wireResponse, error) {
+	c.mx.Lock()
+	if c.err != nil {
+		err := c.err
+		c.mx.Unlock()
+		return wireResponse{}, err
+	}
+
+	c.nextID++
+	req := wireRequest{V: ProtocolVersion, ID: c.nextID, Op: op, Keys: keys, Timeout: timeout.Milliseconds()}
+	r := make(chan wireResponse, 1)
+	c.pending[req.ID] = r
+	err := c.enc.Encode(req)
+	c.mx.Unlock()
+	if err != nil {
+		return wireResponse{}, err
+	}
+
+	select {
+	case rsp := <-r:
+		return rsp, responseError(rsp)
+	case <-c.done:
+		c.mx.Lock()
+		defer c.mx.Unlock()
+		return wireResponse{}, c.err
+	}
+}
+
+func responseError(rsp wireResponse) error {
+	switch rsp.Code {
+	case "":
+		return nil
+	case CodeTimeout:
+		return syncbus.ErrTimeout
+	case CodeClosed:
+		return syncbus.ErrClosed
+	default:
+		return &RemoteError{Code: rsp.Code, Message: rsp.Error}
+	}
+}
+
+// Wait blocks until all the signals represented by the keys are set on the remote bus, or the timeout of the
+// remote bus expires.
+func (c *Client) Wait(keys ...string) error {
+	return c.WaitTimeout(0, keys...)
+}
+
+// WaitTimeout is like Wait, but it uses the provided timeout instead of the timeout of the remote bus.
+func (c *Client) WaitTimeout(timeout time.Duration, keys ...string) error {
+	if len(keys) == 0 {
+		return nil
+	}
+
+	_, err := c.request("wait", timeout, keys)
+	return err
+}
+
+// Signal sets the signals represented by the keys on the remote bus. It returns after the remote bus accepted
+// the signals. The connection errors are ignored, they are returned by the subsequent waits.
+func (c *Client) Signal(keys ...string) {
+	if len(keys) > 0 {
+		c.request("signal", 0, keys)
+	}
+}
+
+// ResetSignals clears the signals represented by the keys on the remote bus.
+func (c *Client) ResetSignals(keys ...string) {
+	if len(keys) > 0 {
+		c.request("reset", 0, keys)
+	}
+}
+
+// Reset clears all the signals on the remote bus.
+func (c *Client) Reset() {
+	c.request("resetall", 0, nil)
+}
+
+// Inspect returns the keys of the set signals, and the number of the pending waits of the remote bus.
+func (c *Client) Inspect() ([]string, int, error) {
+	rsp, err := c.request("inspect", 0, nil)
+	return rsp.Signals, rsp.Waiting, err
+}
+
+// Close closes the connection to the remote bus. It doesn't close the remote bus. The pending waits of the
+// client return with an error.
+func (c *Client) Close() {
+	c.conn.Close()
+	<-c.done
+}
@@ -0,0 +1,161 @@
+package wiresync
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/aryszka/syncbus"
+)
+
+func serveTest(t *testing.T, bus *syncbus.SyncBus) (net.Listener, *Client) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	go Serve(bus, l)
+	c, err := Dial(l.Addr().String())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return l, c
+}
+
+func TestWire(t *testing.T) {
+	bus := syncbus.New(120 * time.Millisecond)
+	defer bus.Close()
+	l, c := serveTest(t, bus)
+	defer l.Close()
+	defer c.Close()
+
+	errs := make(chan error, 1)
+	go func() { errs <- c.Wait("foo", "bar") }()
+	bus.Signal("foo")
+	c.Signal("bar")
+	if err := <-errs; err != nil {
+		t.Fatal(err)
+	}
+
+	if err := c.WaitTimeout(3*time.Millisecond, "baz"); err != syncbus.ErrTimeout {
+		t.Error("failed to timeout", err)
+	}
+
+	go func() { errs <- bus.Wait("baz") }()
+	for bus.Stats().Waiting != 1 {
+		time.Sleep(time.Millisecond / 10)
+	}
+
+	signals, waiting, err := c.Inspect()
+	if err != nil || len(signals) != 2 || signals[0] != "bar" || waiting != 1 {
+		t.Error("invalid state", signals, waiting, err)
+	}
+
+	c.Signal("baz")
+	if err := <-errs; err != nil {
+		t.Fatal(err)
+	}
+
+	c.ResetSignals("foo")
+	if signals, _, _ := c.Inspect(); len(signals) != 2 || signals[0] != "bar" || signals[1] != "baz" {
+		t.Error("failed to reset", signals)
+	}
+
+	c.Reset()
+	if signals, _, _ := c.Inspect(); len(signals) != 0 {
+		t.Error("failed to reset", signals)
+	}
+}
+
+func TestWireClosedBus(t *testing.T) {
+	bus := syncbus.New(120 * time.Millisecond)
+	l, c := serveTest(t, bus)
+	defer l.Close()
+	defer c.Close()
+
+	bus.Close()
+	if err := c.Wait("foo"); err != syncbus.ErrClosed {
+		t.Error("failed to fail", err)
+	}
+}
+
+func TestWireClientClose(t *testing.T) {
+	bus := syncbus.New(120 * time.Millisecond)
+	defer bus.Close()
+	l, c := serveTest(t, bus)
+	defer l.Close()
+
+	errs := make(chan error, 1)
+	go func() { errs <- c.Wait("foo") }()
+	for bus.Stats().Waiting != 1 {
+		time.Sleep(time.Millisecond / 10)
+	}
+
+	c.Close()
+	if err := <-errs; err == nil {
+		t.Error("failed to fail")
+	}
+
+	if err := c.Wait("foo"); err == nil {
+		t.Error("failed to fail")
+	}
+}
+
+func TestWireInvalidRequests(t *testing.T) {
+	bus := syncbus.New(120 * time.Millisecond)
+	defer bus.Close()
+	l, c := serveTest(t, bus)
+	defer l.Close()
+	c.Close()
+
+	conn, err := net.Dial("tcp", l.Addr().String())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer conn.Close()
+	r := bufio.NewReader(conn)
+	for _, test := range []struct {
+		request string
+		code    string
+	}{
+		{`{"v": 2, "id": 1, "op": "signal", "keys": ["foo"]}`, CodeVersion},
+		{`{"v": 1, "id": 2, "op": "foo"}`, CodeInvalid},
+		{`{"v": 1, "id": 3, "op": "signal", "keys": ["foo"]}`, ""},
+		{`foo`, CodeInvalid},
+	} {
+		if _, err := conn.Write([]byte(test.request + "\n")); err != nil {
+			t.Fatal(err)
+		}
+
+		line, err := r.ReadBytes('\n')
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		var rsp wireResponse
+		if err := json.Unmarshal(line, &rsp); err != nil {
+			t.Fatal(err)
+		}
+
+		if rsp.V != ProtocolVersion || rsp.Code != test.code {
+			t.Error("invalid response", test.request, string(line))
+		}
+	}
+}
+
+func TestWireRemoteError(t *testing.T) {
+	err := responseError(wireResponse{Code: CodeInvalid, Error: "foo"})
+	var rerr *RemoteError
+	if !errors.As(err, &rerr) || rerr.Code != CodeInvalid || rerr.Message != "foo" {
+		t.Error("invalid error", err)
+	}
+}
+
+func TestClientImplementsBus(t *testing.T) {
+	var _ syncbus.Bus = &Client{}
+}
@@ -0,0 +1,112 @@
+package syncbus
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// Stage is a step of a workflow executed by RunStages.
+type Stage struct {
+
+	// Name is the name of the stage, used for the keys of the underlying pipeline, see StageStartKey and
+	// StageDoneKey.
+	Name string
+
+	// Timeout is the time limit of the stage. A zero or negative timeout means the timeout of the bus.
+	Timeout time.Duration
+
+	// Run executes the stage. The context is canceled when the timeout of the stage expires.
+	Run func(ctx context.Context) error
+}
+
+// StageResult contains the outcome of a stage executed by RunStages.
+type StageResult struct {
+
+	// Name is the name of the stage.
+	Name string
+
+	// Duration is how long the stage took.
+	Duration time.Duration
+
+	// Err is the error of the stage, if it failed.
+	Err error
+}
+
+// StageError is returned by RunStages when a stage fails.
+type StageError struct {
+
+	// Stage is the name of the failed stage.
+	Stage string
+
+	// Err is the error returned by the stage, or ErrTimeout, when it didn't complete in time.
+	Err error
+}
+
+func (err *StageError) Error() string {
+	return fmt.Sprintf("stage %s failed: %v", err.Stage, err.Err)
+}
+
+// Unwrap returns the error of the stage.
+func (err *StageError) Unwrap() error {
+	return err.Err
+}
+
+func (b *SyncBus) runStage(s Stage) error {
+	timeout := s.Timeout
+	if timeout <= 0 {
+		timeout = b.timeout
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	done := make(chan error, 1)
+	go func() { done <- s.Run(ctx) }()
+	select {
+	case err := <-done:
+		return err
+	case <-ctx.Done():
+		return ErrTimeout
+	}
+}
+
+// RunStages executes the stages one after the other, as a Pipeline. Each stage is started only after the
+// previous one was completed, and it is limited by its own timeout. Other goroutines can synchronize with the
+// stages, by waiting for the keys returned by StageStartKey and StageDoneKey. It returns the results of the
+// executed stages, including their durations. When a stage fails, it stops, and returns a *StageError, telling
+// which stage failed. When a stage times out, its function is left running, but its result is ignored.
+//
+// If the receiver *SyncBus is nil, the stages are executed without synchronization.
+func (b *SyncBus) RunStages(stages ...Stage) ([]StageResult, error) {
+	names := make([]string, len(stages))
+	for i, s := range stages {
+		names[i] = s.Name
+	}
+
+	p := b.Pipeline(names...)
+	var results []StageResult
+	for _, s := range stages {
+		if err := p.AwaitStage(s.Name); err != nil {
+			return results, &StageError{Stage: s.Name, Err: err}
+		}
+
+		start := time.Now()
+		var err error
+		if b == nil {
+			err = s.Run(context.Background())
+		} else {
+			err = b.runStage(s)
+		}
+
+		results = append(results, StageResult{Name: s.Name, Duration: time.Since(start), Err: err})
+		if err != nil {
+			return results, &StageError{Stage: s.Name, Err: err}
+		}
+
+		if err := p.ReleaseStage(s.Name); err != nil {
+			return results, &StageError{Stage: s.Name, Err: err}
+		}
+	}
+
+	return results, nil
+}
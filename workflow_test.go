@@ -0,0 +1,99 @@
+package syncbus
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestNilRunStages(t *testing.T) {
+	var bus *SyncBus
+	var order []string
+	stage := func(name string) Stage {
+		return Stage{Name: name, Run: func(context.Context) error {
+			order = append(order, name)
+			return nil
+		}}
+	}
+
+	r, err := bus.RunStages(stage("foo"), stage("bar"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(r) != 2 || len(order) != 2 || order[0] != "foo" || order[1] != "bar" {
+		t.Error("failed to run the stages", r, order)
+	}
+}
+
+func TestRunStages(t *testing.T) {
+	bus := New(120 * time.Millisecond)
+	defer bus.Close()
+
+	observed := make(chan error, 1)
+	go func() { observed <- bus.Wait(StageDoneKey("ingest"), StageStartKey("store")) }()
+	r, err := bus.RunStages(
+		Stage{Name: "ingest", Run: func(context.Context) error {
+			time.Sleep(3 * time.Millisecond)
+			return nil
+		}},
+		Stage{Name: "store", Run: func(context.Context) error { return nil }},
+	)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(r) != 2 || r[0].Name != "ingest" || r[0].Duration < 3*time.Millisecond || r[1].Name != "store" {
+		t.Error("invalid results", r)
+	}
+
+	if err := <-observed; err != nil {
+		t.Error(err)
+	}
+}
+
+func TestRunStagesFails(t *testing.T) {
+	bus := New(120 * time.Millisecond)
+	defer bus.Close()
+
+	testErr := errors.New("test")
+	var ran bool
+	r, err := bus.RunStages(
+		Stage{Name: "ingest", Run: func(context.Context) error { return testErr }},
+		Stage{Name: "store", Run: func(context.Context) error {
+			ran = true
+			return nil
+		}},
+	)
+
+	var serr *StageError
+	if !errors.As(err, &serr) || serr.Stage != "ingest" || !errors.Is(err, testErr) {
+		t.Fatal("failed to fail", err)
+	}
+
+	if len(r) != 1 || r[0].Err != testErr || ran {
+		t.Error("invalid results", r)
+	}
+}
+
+func TestRunStagesTimeout(t *testing.T) {
+	bus := New(120 * time.Millisecond)
+	defer bus.Close()
+
+	block := make(chan struct{})
+	defer close(block)
+	_, err := bus.RunStages(
+		Stage{Name: "ingest", Run: func(context.Context) error { return nil }},
+		Stage{Name: "store", Timeout: 3 * time.Millisecond, Run: func(context.Context) error {
+			<-block
+			return nil
+		}},
+	)
+
+	var serr *StageError
+	if !errors.As(err, &serr) || serr.Stage != "store" || serr.Err != ErrTimeout {
+		t.Error("failed to timeout", err)
+	}
+}